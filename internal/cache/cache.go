@@ -2,12 +2,19 @@
 package cache
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
-	"github.com/adrg/xdg"
-	_ "go.seanlatimer.dev/ignr/internal/xdginit"
+	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/templates"
 )
 
 const (
@@ -17,18 +24,63 @@ const (
 	defaultConfigDirName = "ignr"
 )
 
+// DefaultSource is the template repository ignr clones into its cache.
+const DefaultSource = defaultRepoCloneURL
+
+var unsafeDirChars = regexp.MustCompile(`[^a-z0-9._]+`)
+
+// cloneRepoRef is replaced in tests to fake clones without the network.
+var cloneRepoRef = CloneRepoRef
+
+// Status describes the template cache on disk.
 type Status struct {
-	Initialized bool
-	Path        string
-	HeadCommit  string
+	Initialized bool   `json:"initialized"`
+	Path        string `json:"path"`
+	HeadCommit  string `json:"head_commit,omitempty"`
+	// LastModified is the modification time of the clone's .git directory, which
+	// changes whenever the cache is cloned or pulled.
+	LastModified time.Time `json:"last_modified,omitzero"`
 }
 
-func GetCachePath() (string, error) {
-	return filepath.Join(xdg.ConfigHome, defaultConfigDirName, defaultCacheDirName, defaultRepoDirName), nil
+// GetCachePath returns where the clone of source lives. Each source gets its own
+// directory under the cache root, named by RepoDirName.
+func GetCachePath(source string) (string, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, defaultCacheDirName, RepoDirName(source)), nil
+}
+
+// RepoDirName derives a cache directory name from a repository URL, e.g.
+// "gitlab.com-acme-gitignore-1a2b3c4d" for https://gitlab.com/acme/gitignore.git.
+// The short hash of the whole source keeps URLs that sanitize alike apart. The
+// default source keeps its original "github-gitignore" name so existing caches stay valid.
+func RepoDirName(source string) string {
+	source = strings.TrimSpace(source)
+	if source == defaultRepoCloneURL {
+		return defaultRepoDirName
+	}
+
+	name := source
+	if u, err := url.Parse(source); err == nil && u.Scheme != "" {
+		name = u.Host + u.Path
+	} else if at := strings.Index(name, "@"); at >= 0 {
+		// scp-like syntax: git@host:owner/repo.git
+		name = name[at+1:]
+	}
+	name = strings.TrimSuffix(strings.TrimRight(name, "/"), ".git")
+	name = strings.Trim(unsafeDirChars.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+	if name == "" {
+		name = "source"
+	}
+
+	sum := sha256.Sum256([]byte(source))
+	return name + "-" + hex.EncodeToString(sum[:4])
 }
 
 func IsCacheInitialized() (bool, error) {
-	cachePath, err := GetCachePath()
+	cachePath, err := GetCachePath(defaultRepoCloneURL)
 	if err != nil {
 		return false, err
 	}
@@ -50,8 +102,16 @@ func IsCacheInitialized() (bool, error) {
 	return false, nil
 }
 
-func InitializeCache() (string, error) {
-	cachePath, err := GetCachePath()
+// InitializeCache returns the cache path, cloning the template repository first if needed.
+// The clone is made beside the cache path and moved into place, so cancelling ctx never
+// leaves a partial clone that would count as initialized.
+func InitializeCache(ctx context.Context) (string, error) {
+	cachePath, err := GetCachePath(defaultRepoCloneURL)
+	if err != nil {
+		return "", err
+	}
+
+	ref, err := templateRepoRef()
 	if err != nil {
 		return "", err
 	}
@@ -61,22 +121,90 @@ func InitializeCache() (string, error) {
 		return "", err
 	}
 	if initialized {
+		if err := syncRef(ctx, cachePath, ref); err != nil {
+			return "", err
+		}
+		if err := refreshIfStale(ctx, cachePath); err != nil {
+			return "", err
+		}
 		return cachePath, nil
 	}
 
-	if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err != nil {
-		return "", fmt.Errorf("create cache dir: %w", err)
+	cacheDir := filepath.Dir(cachePath)
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return "", config.WrapWriteError(cacheDir, fmt.Errorf("create cache dir: %w", err))
+	}
+	if err := recloneCache(ctx, cachePath, ref); err != nil {
+		return "", err
 	}
+	return cachePath, nil
+}
 
-	if err := CloneRepo(defaultRepoCloneURL, cachePath); err != nil {
+// templateRepoRef returns the configured branch, tag, or commit to pin the cache to.
+func templateRepoRef() (string, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
 		return "", err
 	}
+	return strings.TrimSpace(cfg.TemplateRepoRef), nil
+}
 
+// ReinitializeCache replaces the cache with a fresh clone at the configured ref, creating it
+// if missing. The old cache is kept until the new clone succeeds.
+func ReinitializeCache(ctx context.Context) (string, error) {
+	cachePath, err := GetCachePath(defaultRepoCloneURL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := templateRepoRef()
+	if err != nil {
+		return "", err
+	}
+
+	cacheDir := filepath.Dir(cachePath)
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return "", config.WrapWriteError(cacheDir, fmt.Errorf("create cache dir: %w", err))
+	}
+	if err := recloneCache(ctx, cachePath, ref); err != nil {
+		return "", err
+	}
 	return cachePath, nil
 }
 
-func UpdateCache() (string, error) {
-	cachePath, err := GetCachePath()
+// syncRef re-clones the cache when it was cloned at a different ref than ref.
+func syncRef(ctx context.Context, cachePath, ref string) error {
+	changed, err := RefChanged(cachePath, ref)
+	if err != nil || !changed {
+		return err
+	}
+	return recloneCache(ctx, cachePath, ref)
+}
+
+// recloneCache clones ref into cachePath. The new clone is made beside the old one so
+// a failure leaves the cache intact.
+func recloneCache(ctx context.Context, cachePath, ref string) error {
+	tmpPath := cachePath + ".tmp"
+	if err := os.RemoveAll(tmpPath); err != nil {
+		return fmt.Errorf("remove stale clone: %w", err)
+	}
+	if err := cloneRepoRef(ctx, defaultRepoCloneURL, tmpPath, ref); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return config.WrapWriteError(filepath.Dir(cachePath), err)
+	}
+	if err := os.RemoveAll(cachePath); err != nil {
+		return config.WrapWriteError(filepath.Dir(cachePath), fmt.Errorf("remove old cache: %w", err))
+	}
+	if err := os.Rename(tmpPath, cachePath); err != nil {
+		return config.WrapWriteError(filepath.Dir(cachePath), fmt.Errorf("replace cache: %w", err))
+	}
+	templates.ForgetDiscovered(cachePath)
+	// The clone is already in place; a missing timestamp only falls back to the .git mtime.
+	_ = markUpdated(cachePath)
+	return nil
+}
+
+func UpdateCache(ctx context.Context) (string, error) {
+	cachePath, err := GetCachePath(defaultRepoCloneURL)
 	if err != nil {
 		return "", err
 	}
@@ -89,15 +217,63 @@ func UpdateCache() (string, error) {
 		return "", fmt.Errorf("cache not initialized; run init or generate first")
 	}
 
-	if err := PullRepo(cachePath); err != nil {
+	ref, err := templateRepoRef()
+	if err != nil {
+		return "", err
+	}
+	changed, err := RefChanged(cachePath, ref)
+	if err != nil {
 		return "", err
 	}
+	if changed {
+		if err := syncRef(ctx, cachePath, ref); err != nil {
+			return "", err
+		}
+		return cachePath, nil
+	}
 
+	if err := pullCache(ctx, cachePath); err != nil {
+		return "", err
+	}
 	return cachePath, nil
 }
 
+// ClearCache deletes the cloned template repository, including any leftover partial clone.
+// Config and presets live outside the clone and are left alone. It reports whether a cache existed.
+func ClearCache() (string, bool, error) {
+	cachePath, err := GetCachePath(defaultRepoCloneURL)
+	if err != nil {
+		return "", false, err
+	}
+
+	existed := false
+	for _, path := range []string{cachePath, cachePath + ".tmp"} {
+		if _, err := os.Lstat(path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", false, fmt.Errorf("stat cache: %w", err)
+		}
+		existed = existed || path == cachePath
+		if err := os.RemoveAll(path); err != nil {
+			return "", false, config.WrapWriteError(filepath.Dir(cachePath), fmt.Errorf("remove cache: %w", err))
+		}
+	}
+	templates.ForgetDiscovered(cachePath)
+	return cachePath, existed, nil
+}
+
+// CheckForUpdate reports whether the remote template repository has commits the cache lacks.
+func CheckForUpdate(ctx context.Context) (bool, error) {
+	cachePath, err := GetCachePath(defaultRepoCloneURL)
+	if err != nil {
+		return false, err
+	}
+	return IsUpdateAvailable(ctx, cachePath)
+}
+
 func GetStatus() (Status, error) {
-	cachePath, err := GetCachePath()
+	cachePath, err := GetCachePath(defaultRepoCloneURL)
 	if err != nil {
 		return Status{}, err
 	}
@@ -118,6 +294,12 @@ func GetStatus() (Status, error) {
 			return Status{}, err
 		}
 		status.HeadCommit = head
+
+		info, err := os.Stat(filepath.Join(cachePath, ".git"))
+		if err != nil {
+			return Status{}, fmt.Errorf("stat cache: %w", err)
+		}
+		status.LastModified = info.ModTime()
 	}
 
 	return status, nil
@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -52,20 +54,79 @@ func TestGetCachePath(t *testing.T) {
 	cleanup := setupCacheTest(t)
 	defer cleanup()
 
-	path, err := GetCachePath()
+	path, err := GetCachePath(DefaultSource)
 	if err != nil {
-		t.Fatalf("GetCachePath() error = %v", err)
+		t.Fatalf("GetCachePath(DefaultSource) error = %v", err)
 	}
 
 	// Should contain cache directory components
 	if !strings.Contains(path, defaultConfigDirName) {
-		t.Errorf("GetCachePath() = %q, want path containing %q", path, defaultConfigDirName)
+		t.Errorf("GetCachePath(DefaultSource) = %q, want path containing %q", path, defaultConfigDirName)
 	}
 	if !strings.Contains(path, defaultCacheDirName) {
-		t.Errorf("GetCachePath() = %q, want path containing %q", path, defaultCacheDirName)
+		t.Errorf("GetCachePath(DefaultSource) = %q, want path containing %q", path, defaultCacheDirName)
 	}
 	if !strings.Contains(path, defaultRepoDirName) {
-		t.Errorf("GetCachePath() = %q, want path containing %q", path, defaultRepoDirName)
+		t.Errorf("GetCachePath(DefaultSource) = %q, want path containing %q", path, defaultRepoDirName)
+	}
+}
+
+func TestGetCachePathPerSource(t *testing.T) {
+	cleanup := setupCacheTest(t)
+	defer cleanup()
+
+	sources := []string{
+		DefaultSource,
+		"https://gitlab.com/acme/gitignore.git",
+		"https://gitlab.com/acme/gitignore-extra.git",
+		"git@gitlab.com:acme/gitignore.git",
+		"https://gitlab.com/acme-gitignore.git",
+		"file:///srv/templates",
+	}
+
+	seen := make(map[string]string)
+	for _, source := range sources {
+		path, err := GetCachePath(source)
+		if err != nil {
+			t.Fatalf("GetCachePath(%q) error = %v", source, err)
+		}
+		if other, ok := seen[path]; ok {
+			t.Errorf("GetCachePath(%q) = GetCachePath(%q) = %q, want distinct paths", source, other, path)
+		}
+		seen[path] = source
+
+		if filepath.Dir(path) != filepath.Join(xdg.ConfigHome, defaultConfigDirName, defaultCacheDirName) {
+			t.Errorf("GetCachePath(%q) = %q, want a direct child of the cache root", source, path)
+		}
+		if again, _ := GetCachePath(source); again != path {
+			t.Errorf("GetCachePath(%q) not stable: %q then %q", source, path, again)
+		}
+	}
+}
+
+func TestRepoDirName(t *testing.T) {
+	tests := []struct {
+		source     string
+		wantPrefix string
+	}{
+		{source: DefaultSource, wantPrefix: defaultRepoDirName},
+		{source: "https://gitlab.com/acme/gitignore.git", wantPrefix: "gitlab.com-acme-gitignore-"},
+		{source: "https://GitLab.com/Acme/GitIgnore/", wantPrefix: "gitlab.com-acme-gitignore-"},
+		{source: "git@gitlab.com:acme/gitignore.git", wantPrefix: "gitlab.com-acme-gitignore-"},
+		{source: "file:///srv/templates", wantPrefix: "srv-templates-"},
+		{source: "../..", wantPrefix: "source-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			got := RepoDirName(tt.source)
+			if !strings.HasPrefix(got, tt.wantPrefix) {
+				t.Errorf("RepoDirName(%q) = %q, want prefix %q", tt.source, got, tt.wantPrefix)
+			}
+			if strings.ContainsAny(got, `/\:@`) || got == "." || got == ".." {
+				t.Errorf("RepoDirName(%q) = %q, want a single safe path element", tt.source, got)
+			}
+		})
 	}
 }
 
@@ -83,7 +144,7 @@ func TestIsCacheInitialized(t *testing.T) {
 			name: "non-initialized cache",
 			setup: func() string {
 				// Don't create cache
-				path, _ := GetCachePath()
+				path, _ := GetCachePath(DefaultSource)
 				return path
 			},
 			want:    false,
@@ -92,7 +153,7 @@ func TestIsCacheInitialized(t *testing.T) {
 		{
 			name: "initialized cache with .git",
 			setup: func() string {
-				path, _ := GetCachePath()
+				path, _ := GetCachePath(DefaultSource)
 				if err := os.MkdirAll(path, 0o755); err != nil {
 					t.Fatalf("failed to create cache dir: %v", err)
 				}
@@ -109,7 +170,7 @@ func TestIsCacheInitialized(t *testing.T) {
 		{
 			name: "cache directory exists but no .git",
 			setup: func() string {
-				path, _ := GetCachePath()
+				path, _ := GetCachePath(DefaultSource)
 				// Ensure parent directories exist but not the cache path itself
 				// or create it as a directory but without .git
 				parentDir := filepath.Dir(path)
@@ -161,7 +222,7 @@ func TestInitializeCache(t *testing.T) {
 	// In a real test environment, you might want to use a mock or local git repo
 
 	// Test with non-existent cache
-	path, err := InitializeCache()
+	path, err := InitializeCache(context.Background())
 
 	// InitializeCache will try to clone, which might fail in test environment
 	// So we just check that it returns an error (expected in test) or succeeds
@@ -173,7 +234,7 @@ func TestInitializeCache(t *testing.T) {
 		}
 	} else {
 		// If it succeeds, verify path is correct
-		wantPath, _ := GetCachePath()
+		wantPath, _ := GetCachePath(DefaultSource)
 		if path != wantPath {
 			t.Errorf("InitializeCache() = %q, want %q", path, wantPath)
 		}
@@ -194,7 +255,7 @@ func TestInitializeCacheAlreadyInitialized(t *testing.T) {
 	defer cleanup()
 
 	// Create an already initialized cache
-	path, _ := GetCachePath()
+	path, _ := GetCachePath(DefaultSource)
 	if err := os.MkdirAll(path, 0o755); err != nil {
 		t.Fatalf("failed to create cache dir: %v", err)
 	}
@@ -206,7 +267,7 @@ func TestInitializeCacheAlreadyInitialized(t *testing.T) {
 	}
 
 	// InitializeCache should return existing path without cloning
-	resultPath, err := InitializeCache()
+	resultPath, err := InitializeCache(context.Background())
 	if err != nil {
 		t.Fatalf("InitializeCache() error = %v", err)
 	}
@@ -216,12 +277,44 @@ func TestInitializeCacheAlreadyInitialized(t *testing.T) {
 	}
 }
 
+func TestInitializeCacheCancelledLeavesNoPartialClone(t *testing.T) {
+	cleanup := setupCacheTest(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	original := cloneRepoRef
+	cloneRepoRef = func(ctx context.Context, repoURL, dest, ref string) error {
+		// Write part of a clone, then get interrupted.
+		if err := os.MkdirAll(filepath.Join(dest, ".git", "objects"), 0o755); err != nil {
+			return err
+		}
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	defer func() { cloneRepoRef = original }()
+
+	if _, err := InitializeCache(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("InitializeCache() error = %v, want context.Canceled", err)
+	}
+
+	path, _ := GetCachePath(DefaultSource)
+	for _, leftover := range []string{path, path + ".tmp"} {
+		if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+			t.Errorf("%s exists after a cancelled clone (stat error %v)", leftover, err)
+		}
+	}
+	if initialized, err := IsCacheInitialized(); err != nil || initialized {
+		t.Errorf("IsCacheInitialized() = %v, %v; want false after a cancelled clone", initialized, err)
+	}
+}
+
 func TestUpdateCache(t *testing.T) {
 	cleanup := setupCacheTest(t)
 	defer cleanup()
 
 	// Test with non-initialized cache
-	_, err := UpdateCache()
+	_, err := UpdateCache(context.Background())
 	if err == nil {
 		t.Error("UpdateCache() expected error for non-initialized cache, got nil")
 		return
@@ -254,7 +347,7 @@ func TestGetStatus(t *testing.T) {
 			name: "initialized cache",
 			setup: func() {
 				// Create a proper git repository
-				path, _ := GetCachePath()
+				path, _ := GetCachePath(DefaultSource)
 				repo, err := git.PlainInit(path, false)
 				if err != nil {
 					t.Fatalf("failed to init git repo: %v", err)
@@ -310,8 +403,12 @@ func TestGetStatus(t *testing.T) {
 				t.Error("GetStatus() Path is empty")
 			}
 
-			// If initialized, HeadCommit might be empty (if git operations fail)
-			// or might contain a commit hash
+			if tt.wantInitialized && status.LastModified.IsZero() {
+				t.Error("GetStatus() LastModified is zero for an initialized cache")
+			}
+			if !tt.wantInitialized && !status.LastModified.IsZero() {
+				t.Errorf("GetStatus() LastModified = %v, want zero", status.LastModified)
+			}
 		})
 	}
 }
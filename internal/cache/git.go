@@ -2,13 +2,27 @@
 package cache
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage/memory"
 )
 
-func CloneRepo(repoURL, dest string) error {
-	_, err := git.PlainClone(dest, false, &git.CloneOptions{
+// pinnedRefFile records, inside .git, the ref the cache was cloned at so a config change can be detected.
+const pinnedRefFile = "ignr-ref"
+
+func CloneRepo(ctx context.Context, repoURL, dest string) error {
+	_, err := git.PlainCloneContext(ctx, dest, false, &git.CloneOptions{
 		URL:           repoURL,
 		Depth:         1,
 		SingleBranch:  true,
@@ -20,7 +34,119 @@ func CloneRepo(repoURL, dest string) error {
 	return nil
 }
 
-func PullRepo(repoPath string) error {
+// CloneRepoRef clones repoURL at ref, which may be a branch, a tag, or a full commit hash.
+// An empty ref clones the default branch. The ref is recorded for RefChanged.
+func CloneRepoRef(ctx context.Context, repoURL, dest, ref string) error {
+	switch {
+	case ref == "":
+		if err := CloneRepo(ctx, repoURL, dest); err != nil {
+			return err
+		}
+	case isCommitHash(ref):
+		// Servers do not let shallow clones ask for arbitrary commits, so fetch full history.
+		repo, err := git.PlainCloneContext(ctx, dest, false, &git.CloneOptions{URL: repoURL})
+		if err != nil {
+			return fmt.Errorf("git clone %s %s: %w", repoURL, dest, err)
+		}
+		wt, err := repo.Worktree()
+		if err != nil {
+			return fmt.Errorf("git checkout %s: %w", ref, err)
+		}
+		if err := wt.Checkout(&git.CheckoutOptions{Hash: plumbing.NewHash(ref)}); err != nil {
+			return fmt.Errorf("git checkout %s: %w", ref, err)
+		}
+	default:
+		remote := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{
+			Name: git.DefaultRemoteName,
+			URLs: []string{repoURL},
+		})
+		refs, err := remote.ListContext(ctx, &git.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("git ls-remote %s: %w", repoURL, err)
+		}
+		refName, ok := resolveRemoteRef(refs, ref)
+		if !ok {
+			return fmt.Errorf("git clone --branch %s: no branch or tag named %q in %s", ref, ref, repoURL)
+		}
+		if _, err := git.PlainCloneContext(ctx, dest, false, cloneOptions(repoURL, refName)); err != nil {
+			return fmt.Errorf("git clone --depth 1 --branch %s %s %s: %w", ref, repoURL, dest, err)
+		}
+	}
+
+	return writePinnedRef(dest, ref)
+}
+
+// cloneOptions returns shallow single-branch options for cloning refName.
+func cloneOptions(repoURL string, refName plumbing.ReferenceName) *git.CloneOptions {
+	return &git.CloneOptions{
+		URL:           repoURL,
+		ReferenceName: refName,
+		Depth:         1,
+		SingleBranch:  true,
+	}
+}
+
+// resolveRemoteRef finds ref among the advertised refs, accepting full ref names or
+// short branch and tag names. Branches win over tags of the same name, like git.
+func resolveRemoteRef(refs []*plumbing.Reference, ref string) (plumbing.ReferenceName, bool) {
+	candidates := []plumbing.ReferenceName{
+		plumbing.ReferenceName(ref),
+		plumbing.NewBranchReferenceName(ref),
+		plumbing.NewTagReferenceName(ref),
+	}
+	for _, candidate := range candidates {
+		for _, r := range refs {
+			if r.Name() == candidate && (candidate.IsBranch() || candidate.IsTag()) {
+				return candidate, true
+			}
+		}
+	}
+	return "", false
+}
+
+func isCommitHash(ref string) bool {
+	if len(ref) != 40 {
+		return false
+	}
+	return strings.Trim(strings.ToLower(ref), "0123456789abcdef") == ""
+}
+
+func writePinnedRef(repoPath, ref string) error {
+	path := filepath.Join(repoPath, ".git", pinnedRefFile)
+	if err := os.WriteFile(path, []byte(ref+"\n"), 0o644); err != nil {
+		return fmt.Errorf("record template ref: %w", err)
+	}
+	return nil
+}
+
+// RefChanged reports whether the cache at repoPath was cloned at a different ref than ref.
+// Caches cloned before refs were recorded count as the default branch ("").
+func RefChanged(repoPath, ref string) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(repoPath, ".git", pinnedRefFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ref != "", nil
+		}
+		return false, fmt.Errorf("read template ref: %w", err)
+	}
+	return strings.TrimSpace(string(data)) != ref, nil
+}
+
+// IsDetachedHead reports whether HEAD points at a commit rather than a branch,
+// which is the case for caches pinned to a tag or commit.
+func IsDetachedHead(repoPath string) (bool, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return false, fmt.Errorf("git symbolic-ref HEAD: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return false, fmt.Errorf("git symbolic-ref HEAD: %w", err)
+	}
+	return !head.Name().IsBranch(), nil
+}
+
+func PullRepo(ctx context.Context, repoPath string) error {
 	repo, err := git.PlainOpen(repoPath)
 	if err != nil {
 		return fmt.Errorf("git pull --ff-only: %w", err)
@@ -31,9 +157,15 @@ func PullRepo(repoPath string) error {
 		return fmt.Errorf("git pull --ff-only: %w", err)
 	}
 
-	err = wt.Pull(&git.PullOptions{
+	opts := &git.PullOptions{
 		Depth: 1,
-	})
+	}
+	// Pull the checked-out branch, which may be a pinned non-default branch.
+	if head, err := repo.Head(); err == nil && head.Name().IsBranch() {
+		opts.ReferenceName = head.Name()
+		opts.SingleBranch = true
+	}
+	err = wt.PullContext(ctx, opts)
 	if err != nil {
 		// NoErrAlreadyUpToDate is not actually an error, it means we're already up to date
 		if err == git.NoErrAlreadyUpToDate {
@@ -57,3 +189,145 @@ func GetHeadCommit(repoPath string) (string, error) {
 
 	return ref.Hash().String(), nil
 }
+
+// IsUpdateAvailable reports whether the origin remote's copy of the checked-out
+// branch differs from the local HEAD. It contacts the remote, so callers should
+// bound ctx with a timeout.
+func IsUpdateAvailable(ctx context.Context, repoPath string) (bool, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return false, fmt.Errorf("git ls-remote origin: %w", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return false, fmt.Errorf("git ls-remote origin: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		// Pinned to a tag or commit; there is nothing newer to fetch.
+		return false, nil
+	}
+	remote, err := repo.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return false, fmt.Errorf("git ls-remote origin: %w", err)
+	}
+	refs, err := remote.ListContext(ctx, &git.ListOptions{})
+	if err != nil {
+		return false, fmt.Errorf("git ls-remote origin: %w", err)
+	}
+
+	for _, ref := range refs {
+		if ref.Name() == head.Name() {
+			return ref.Hash() != head.Hash(), nil
+		}
+	}
+	return false, fmt.Errorf("git ls-remote origin: %s not found on remote", head.Name().Short())
+}
+
+// IsShallowRepo reports whether the repository at repoPath has truncated history.
+func IsShallowRepo(repoPath string) (bool, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return false, fmt.Errorf("git rev-parse --is-shallow-repository: %w", err)
+	}
+	shallow, err := repo.Storer.Shallow()
+	if err != nil {
+		return false, fmt.Errorf("git rev-parse --is-shallow-repository: %w", err)
+	}
+	return len(shallow) > 0, nil
+}
+
+// GetLastCommitDates returns the committer date of the most recent commit touching each of relPaths
+// (slash-separated, relative to the repo root). Paths whose history is cut off by a shallow clone
+// are omitted from the result.
+func GetLastCommitDates(repoPath string, relPaths []string) (map[string]time.Time, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+
+	ref, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	head, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+
+	shallow, err := repo.Storer.Shallow()
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	shallowSet := make(map[plumbing.Hash]struct{}, len(shallow))
+	ignore := []plumbing.Hash{}
+	for _, hash := range shallow {
+		shallowSet[hash] = struct{}{}
+		if c, err := repo.CommitObject(hash); err == nil {
+			ignore = append(ignore, c.ParentHashes...)
+		}
+	}
+
+	pending := make(map[string]struct{}, len(relPaths))
+	for _, path := range relPaths {
+		pending[path] = struct{}{}
+	}
+	dates := make(map[string]time.Time, len(relPaths))
+
+	record := func(path string, when time.Time) {
+		if _, ok := pending[path]; ok {
+			dates[path] = when
+			delete(pending, path)
+		}
+	}
+
+	iter := object.NewCommitIterCTime(head, nil, ignore)
+	err = iter.ForEach(func(c *object.Commit) error {
+		if len(pending) == 0 {
+			return storer.ErrStop
+		}
+		if _, ok := shallowSet[c.Hash]; ok {
+			// Parent is missing, so we cannot tell which files this commit changed.
+			return nil
+		}
+
+		tree, err := c.Tree()
+		if err != nil {
+			return err
+		}
+
+		if c.NumParents() == 0 {
+			for path := range pending {
+				if _, err := tree.File(path); err == nil {
+					record(path, c.Committer.When)
+				}
+			}
+			return nil
+		}
+
+		parent, err := c.Parent(0)
+		if err != nil {
+			return err
+		}
+		parentTree, err := parent.Tree()
+		if err != nil {
+			return err
+		}
+		changes, err := object.DiffTree(parentTree, tree)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			if change.To.Name != "" {
+				record(change.To.Name, c.Committer.When)
+				continue
+			}
+			record(change.From.Name, c.Committer.When)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, storer.ErrStop) {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+
+	return dates, nil
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
@@ -39,7 +41,7 @@ func TestCloneRepo(t *testing.T) {
 	
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := CloneRepo(tt.repoURL, tt.dest)
+			err := CloneRepo(context.Background(), tt.repoURL, tt.dest)
 			
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CloneRepo() error = %v, wantErr %v", err, tt.wantErr)
@@ -62,7 +64,7 @@ func TestCloneRepoSuccess(t *testing.T) {
 	
 	// Try to clone the actual repository
 	dest := filepath.Join(tmpDir, "github-gitignore")
-	err := CloneRepo(defaultRepoCloneURL, dest)
+	err := CloneRepo(context.Background(), defaultRepoCloneURL, dest)
 	
 	if err != nil {
 		// Expected in test environments without network access
@@ -118,7 +120,7 @@ func TestPullRepo(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			repoPath := tt.setup()
 			
-			err := PullRepo(repoPath)
+			err := PullRepo(context.Background(), repoPath)
 			
 			if (err != nil) != tt.wantErr {
 				t.Errorf("PullRepo() error = %v, wantErr %v", err, tt.wantErr)
@@ -170,7 +172,7 @@ func TestPullRepoSuccess(t *testing.T) {
 	}
 	
 	// Pull should handle NoErrAlreadyUpToDate gracefully
-	err = PullRepo(repoPath)
+	err = PullRepo(context.Background(), repoPath)
 	
 	// Pull might fail if there's no remote, but that's expected
 	// The important thing is it doesn't crash
@@ -327,7 +329,7 @@ func TestPullRepoAlreadyUpToDate(t *testing.T) {
 	
 	// Try to pull (will fail without remote, but that's ok)
 	// The test is that PullRepo handles errors gracefully
-	err = PullRepo(repoPath)
+	err = PullRepo(context.Background(), repoPath)
 	
 	// Error is expected since there's no remote configured
 	if err != nil {
@@ -338,3 +340,301 @@ func TestPullRepoAlreadyUpToDate(t *testing.T) {
 		}
 	}
 }
+
+func commitFileAt(t *testing.T, repo *git.Repository, repoPath, rel, content string, when time.Time) {
+	t.Helper()
+	full := filepath.Join(repoPath, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if _, err := wt.Add(rel); err != nil {
+		t.Fatalf("failed to add file: %v", err)
+	}
+	sig := &object.Signature{Name: "Test User", Email: "test@example.com", When: when}
+	if _, err := wt.Commit("update "+rel, &git.CommitOptions{Author: sig, Committer: sig}); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+}
+
+func TestGetLastCommitDates(t *testing.T) {
+	repoPath := filepath.Join(t.TempDir(), "repo")
+	repo, err := git.PlainInit(repoPath, false)
+	if err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+
+	day1 := time.Date(2023, 1, 10, 12, 0, 0, 0, time.UTC)
+	day2 := time.Date(2023, 6, 15, 12, 0, 0, 0, time.UTC)
+	day3 := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	commitFileAt(t, repo, repoPath, "Go.gitignore", "# Go", day1)
+	commitFileAt(t, repo, repoPath, "Node.gitignore", "# Node", day2)
+	commitFileAt(t, repo, repoPath, "Global/macOS.gitignore", "# macOS", day2)
+	commitFileAt(t, repo, repoPath, "Node.gitignore", "# Node\nnode_modules/", day3)
+
+	dates, err := GetLastCommitDates(repoPath, []string{
+		"Go.gitignore",
+		"Node.gitignore",
+		"Global/macOS.gitignore",
+		"Missing.gitignore",
+	})
+	if err != nil {
+		t.Fatalf("GetLastCommitDates() error = %v", err)
+	}
+
+	want := map[string]time.Time{
+		"Go.gitignore":           day1,
+		"Node.gitignore":         day3,
+		"Global/macOS.gitignore": day2,
+	}
+	for path, when := range want {
+		got, ok := dates[path]
+		if !ok {
+			t.Errorf("GetLastCommitDates() missing %q", path)
+			continue
+		}
+		if !got.Equal(when) {
+			t.Errorf("GetLastCommitDates()[%q] = %v, want %v", path, got, when)
+		}
+	}
+	if _, ok := dates["Missing.gitignore"]; ok {
+		t.Error("GetLastCommitDates() returned a date for a file not in history")
+	}
+
+	shallow, err := IsShallowRepo(repoPath)
+	if err != nil {
+		t.Fatalf("IsShallowRepo() error = %v", err)
+	}
+	if shallow {
+		t.Error("IsShallowRepo() = true for full repository")
+	}
+}
+
+func TestGetLastCommitDatesShallow(t *testing.T) {
+	srcPath := filepath.Join(t.TempDir(), "src")
+	repo, err := git.PlainInit(srcPath, false)
+	if err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+	commitFileAt(t, repo, srcPath, "Go.gitignore", "# Go", time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC))
+	commitFileAt(t, repo, srcPath, "Node.gitignore", "# Node", time.Date(2023, 2, 10, 0, 0, 0, 0, time.UTC))
+
+	dest := filepath.Join(t.TempDir(), "shallow")
+	if err := CloneRepo(context.Background(), "file://"+filepath.ToSlash(srcPath), dest); err != nil {
+		t.Skipf("shallow clone from local repo unsupported: %v", err)
+	}
+
+	shallow, err := IsShallowRepo(dest)
+	if err != nil {
+		t.Fatalf("IsShallowRepo() error = %v", err)
+	}
+	if !shallow {
+		t.Skip("local clone was not shallow")
+	}
+
+	dates, err := GetLastCommitDates(dest, []string{"Go.gitignore", "Node.gitignore"})
+	if err != nil {
+		t.Fatalf("GetLastCommitDates() on shallow clone error = %v", err)
+	}
+	if len(dates) != 0 {
+		t.Errorf("GetLastCommitDates() on shallow clone = %v, want no dates", dates)
+	}
+}
+
+func TestIsUpdateAvailable(t *testing.T) {
+	srcPath := filepath.Join(t.TempDir(), "src")
+	repo, err := git.PlainInit(srcPath, false)
+	if err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+	commitFileAt(t, repo, srcPath, "Go.gitignore", "# Go", time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC))
+
+	dest := filepath.Join(t.TempDir(), "clone")
+	if err := CloneRepo(context.Background(), "file://"+filepath.ToSlash(srcPath), dest); err != nil {
+		t.Skipf("clone from local repo unsupported: %v", err)
+	}
+
+	ctx := context.Background()
+	available, err := IsUpdateAvailable(ctx, dest)
+	if err != nil {
+		t.Fatalf("IsUpdateAvailable() error = %v", err)
+	}
+	if available {
+		t.Error("IsUpdateAvailable() = true right after clone, want false")
+	}
+
+	commitFileAt(t, repo, srcPath, "Node.gitignore", "# Node", time.Date(2023, 2, 10, 0, 0, 0, 0, time.UTC))
+	available, err = IsUpdateAvailable(ctx, dest)
+	if err != nil {
+		t.Fatalf("IsUpdateAvailable() error = %v", err)
+	}
+	if !available {
+		t.Error("IsUpdateAvailable() = false after remote commit, want true")
+	}
+}
+
+func TestIsUpdateAvailableNoRemote(t *testing.T) {
+	repoPath := t.TempDir()
+	repo, err := git.PlainInit(repoPath, false)
+	if err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+	commitFileAt(t, repo, repoPath, "Go.gitignore", "# Go", time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC))
+
+	if _, err := IsUpdateAvailable(context.Background(), repoPath); err == nil {
+		t.Error("IsUpdateAvailable() without origin expected error, got nil")
+	}
+}
+
+func TestResolveRemoteRef(t *testing.T) {
+	hash := plumbing.NewHash("0123456789abcdef0123456789abcdef01234567")
+	refs := []*plumbing.Reference{
+		plumbing.NewHashReference(plumbing.HEAD, hash),
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName("main"), hash),
+		plumbing.NewHashReference(plumbing.NewBranchReferenceName("v1"), hash),
+		plumbing.NewHashReference(plumbing.NewTagReferenceName("v1"), hash),
+		plumbing.NewHashReference(plumbing.NewTagReferenceName("v2"), hash),
+	}
+
+	tests := []struct {
+		name   string
+		ref    string
+		want   plumbing.ReferenceName
+		wantOK bool
+	}{
+		{name: "short branch", ref: "main", want: "refs/heads/main", wantOK: true},
+		{name: "short tag", ref: "v2", want: "refs/tags/v2", wantOK: true},
+		{name: "branch wins over tag", ref: "v1", want: "refs/heads/v1", wantOK: true},
+		{name: "full tag name", ref: "refs/tags/v1", want: "refs/tags/v1", wantOK: true},
+		{name: "HEAD is not a branch or tag", ref: "HEAD", wantOK: false},
+		{name: "missing", ref: "nope", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := resolveRemoteRef(refs, tt.ref)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("resolveRemoteRef(%q) = %q, %v, want %q, %v", tt.ref, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCloneOptions(t *testing.T) {
+	opts := cloneOptions("https://example.com/repo.git", plumbing.NewTagReferenceName("v1"))
+	if opts.ReferenceName != "refs/tags/v1" {
+		t.Errorf("cloneOptions() ReferenceName = %q, want %q", opts.ReferenceName, "refs/tags/v1")
+	}
+	if !opts.SingleBranch || opts.Depth != 1 {
+		t.Errorf("cloneOptions() SingleBranch = %v, Depth = %d, want shallow single-branch clone", opts.SingleBranch, opts.Depth)
+	}
+}
+
+func TestRefChanged(t *testing.T) {
+	repoPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repoPath, ".git"), 0o755); err != nil {
+		t.Fatalf("failed to create .git dir: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		stored *string
+		ref    string
+		want   bool
+	}{
+		{name: "unrecorded cache, default ref", ref: "", want: false},
+		{name: "unrecorded cache, pinned ref", ref: "main", want: true},
+		{name: "same ref", stored: ptr("v1"), ref: "v1", want: false},
+		{name: "different ref", stored: ptr("v1"), ref: "v2", want: true},
+		{name: "unpinned", stored: ptr("v1"), ref: "", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(repoPath, ".git", pinnedRefFile)
+			_ = os.Remove(path)
+			if tt.stored != nil {
+				if err := writePinnedRef(repoPath, *tt.stored); err != nil {
+					t.Fatalf("writePinnedRef() error = %v", err)
+				}
+			}
+			got, err := RefChanged(repoPath, tt.ref)
+			if err != nil {
+				t.Fatalf("RefChanged() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RefChanged(%q) = %v, want %v", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloneRepoRef(t *testing.T) {
+	srcPath := filepath.Join(t.TempDir(), "src")
+	repo, err := git.PlainInit(srcPath, false)
+	if err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+	commitFileAt(t, repo, srcPath, "Go.gitignore", "# Go", time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC))
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to read HEAD: %v", err)
+	}
+	first := head.Hash()
+	if _, err := repo.CreateTag("v1", first, nil); err != nil {
+		t.Fatalf("failed to create tag: %v", err)
+	}
+	commitFileAt(t, repo, srcPath, "Node.gitignore", "# Node", time.Date(2023, 2, 10, 0, 0, 0, 0, time.UTC))
+	url := "file://" + filepath.ToSlash(srcPath)
+
+	tests := []struct {
+		name         string
+		ref          string
+		wantNode     bool
+		wantDetached bool
+	}{
+		{name: "default branch", ref: "", wantNode: true},
+		{name: "branch", ref: head.Name().Short(), wantNode: true},
+		{name: "tag", ref: "v1", wantNode: false, wantDetached: true},
+		{name: "commit", ref: first.String(), wantNode: false, wantDetached: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := filepath.Join(t.TempDir(), "clone")
+			if err := CloneRepoRef(context.Background(), url, dest, tt.ref); err != nil {
+				t.Skipf("clone from local repo unsupported: %v", err)
+			}
+
+			_, err := os.Stat(filepath.Join(dest, "Node.gitignore"))
+			if gotNode := err == nil; gotNode != tt.wantNode {
+				t.Errorf("Node.gitignore present = %v, want %v", gotNode, tt.wantNode)
+			}
+			detached, err := IsDetachedHead(dest)
+			if err != nil {
+				t.Fatalf("IsDetachedHead() error = %v", err)
+			}
+			if detached != tt.wantDetached {
+				t.Errorf("IsDetachedHead() = %v, want %v", detached, tt.wantDetached)
+			}
+			if changed, err := RefChanged(dest, tt.ref); err != nil || changed {
+				t.Errorf("RefChanged() after clone = %v, %v, want false, nil", changed, err)
+			}
+		})
+	}
+
+	if err := CloneRepoRef(context.Background(), url, filepath.Join(t.TempDir(), "clone"), "missing"); err == nil {
+		t.Error("CloneRepoRef() with unknown ref expected error, got nil")
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
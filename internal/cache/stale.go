@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+// updatedFile records, inside .git, when the cache was last cloned or pulled.
+const updatedFile = "ignr-updated"
+
+// staleRefresh controls InitializeCache's pull of a cache older than cache_ttl. It is off
+// until enabled, so shell completion and other callers never reach the network unasked.
+var staleRefresh struct {
+	enabled bool
+	warn    func(error) error
+}
+
+// SetStaleRefresh sets whether InitializeCache pulls a cache older than cache_ttl, and how a
+// failed pull is reported. A failed pull only fails InitializeCache when warn returns an
+// error, such as under --strict; warn may be nil to ignore it.
+func SetStaleRefresh(enabled bool, warn func(error) error) {
+	staleRefresh.enabled = enabled
+	staleRefresh.warn = warn
+}
+
+// LastUpdated returns when the cache at repoPath was last cloned or pulled. Caches cloned
+// before this was recorded fall back to the modification time of their .git directory.
+func LastUpdated(repoPath string) (time.Time, error) {
+	data, err := os.ReadFile(filepath.Join(repoPath, ".git", updatedFile))
+	if err == nil {
+		if t, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data))); err == nil {
+			return t, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return time.Time{}, fmt.Errorf("read cache update time: %w", err)
+	}
+	info, err := os.Stat(filepath.Join(repoPath, ".git"))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("stat cache: %w", err)
+	}
+	return info.ModTime(), nil
+}
+
+// IsStale reports whether the cache at repoPath was last updated more than ttl ago.
+// A ttl of zero or less never goes stale.
+func IsStale(repoPath string, ttl time.Duration, now time.Time) (bool, error) {
+	if ttl <= 0 {
+		return false, nil
+	}
+	updated, err := LastUpdated(repoPath)
+	if err != nil {
+		return false, err
+	}
+	return now.Sub(updated) > ttl, nil
+}
+
+func markUpdated(repoPath string) error {
+	stamp := time.Now().UTC().Format(time.RFC3339) + "\n"
+	if err := os.WriteFile(filepath.Join(repoPath, ".git", updatedFile), []byte(stamp), 0o644); err != nil {
+		return fmt.Errorf("record cache update time: %w", err)
+	}
+	return nil
+}
+
+// refreshIfStale pulls the cache at cachePath when it is older than cache_ttl. Failures are
+// passed to the staleRefresh warning, so a stale cache is still used unless the warning
+// returns an error.
+func refreshIfStale(ctx context.Context, cachePath string) error {
+	if !staleRefresh.enabled {
+		return nil
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil
+	}
+	ttl, err := cfg.CacheTTLDuration()
+	if err != nil {
+		return reportRefreshError(err)
+	}
+	if stale, err := IsStale(cachePath, ttl, time.Now()); err != nil || !stale {
+		return reportRefreshError(err)
+	}
+	return reportRefreshError(pullCache(ctx, cachePath))
+}
+
+// pullCache pulls the checked-out branch and records the update time. Caches pinned to a
+// tag or commit never move, so only the time is recorded for them.
+func pullCache(ctx context.Context, cachePath string) error {
+	detached, err := IsDetachedHead(cachePath)
+	if err != nil {
+		return err
+	}
+	if !detached {
+		if err := PullRepo(ctx, cachePath); err != nil {
+			return err
+		}
+		templates.ForgetDiscovered(cachePath)
+	}
+	return markUpdated(cachePath)
+}
+
+func reportRefreshError(err error) error {
+	if err == nil || staleRefresh.warn == nil {
+		return nil
+	}
+	return staleRefresh.warn(err)
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.seanlatimer.dev/ignr/internal/config"
+)
+
+func TestIsStale(t *testing.T) {
+	repo := t.TempDir()
+	gitDir := filepath.Join(repo, ".git")
+	if err := os.MkdirAll(gitDir, 0o755); err != nil {
+		t.Fatalf("failed to create .git dir: %v", err)
+	}
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	if err := os.Chtimes(gitDir, old, old); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+
+	// Without a recorded time, the .git mtime is used.
+	if stale, err := IsStale(repo, 24*time.Hour, now); err != nil || !stale {
+		t.Errorf("IsStale(.git mtime 48h ago, 24h) = %v, %v; want true", stale, err)
+	}
+	if stale, err := IsStale(repo, 0, now); err != nil || stale {
+		t.Errorf("IsStale(ttl 0) = %v, %v; want false", stale, err)
+	}
+
+	if err := markUpdated(repo); err != nil {
+		t.Fatalf("markUpdated() error = %v", err)
+	}
+	if stale, err := IsStale(repo, 24*time.Hour, now); err != nil || stale {
+		t.Errorf("IsStale(just updated, 24h) = %v, %v; want false", stale, err)
+	}
+	if stale, err := IsStale(repo, time.Hour, now.Add(2*time.Hour)); err != nil || !stale {
+		t.Errorf("IsStale(updated 2h ago, 1h) = %v, %v; want true", stale, err)
+	}
+}
+
+func TestInitializeCacheRefreshesStaleCache(t *testing.T) {
+	cleanup := setupCacheTest(t)
+	defer cleanup()
+	t.Cleanup(func() { SetStaleRefresh(false, nil) })
+
+	path, _ := GetCachePath(DefaultSource)
+	gitDir := filepath.Join(path, ".git")
+	if err := os.MkdirAll(gitDir, 0o755); err != nil {
+		t.Fatalf("failed to create .git dir: %v", err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(gitDir, old, old); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+	if err := config.SaveConfig(config.Config{CacheTTL: "1h"}); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		wantWarn bool
+	}{
+		{name: "disabled", enabled: false},
+		// The fake .git is not a repository, so the pull fails and is only reported.
+		{name: "enabled", enabled: true, wantWarn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warned error
+			SetStaleRefresh(tt.enabled, func(err error) error {
+				warned = err
+				return nil
+			})
+			got, err := InitializeCache(context.Background())
+			if err != nil {
+				t.Fatalf("InitializeCache() error = %v, want a failed refresh to be non-fatal", err)
+			}
+			if got != path {
+				t.Errorf("InitializeCache() = %q, want %q", got, path)
+			}
+			if (warned != nil) != tt.wantWarn {
+				t.Errorf("refresh warning = %v, want warning = %v", warned, tt.wantWarn)
+			}
+		})
+	}
+}
+
+func TestInitializeCacheStaleRefreshErrorFromWarn(t *testing.T) {
+	cleanup := setupCacheTest(t)
+	defer cleanup()
+	t.Cleanup(func() { SetStaleRefresh(false, nil) })
+
+	path, _ := GetCachePath(DefaultSource)
+	if err := os.MkdirAll(filepath.Join(path, ".git"), 0o755); err != nil {
+		t.Fatalf("failed to create .git dir: %v", err)
+	}
+	if err := config.SaveConfig(config.Config{CacheTTL: "1ns"}); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	strictErr := errors.New("refresh failed (--strict)")
+	SetStaleRefresh(true, func(error) error { return strictErr })
+	if _, err := InitializeCache(context.Background()); !errors.Is(err, strictErr) {
+		t.Errorf("InitializeCache() error = %v, want the error returned by warn", err)
+	}
+}
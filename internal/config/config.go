@@ -3,30 +3,117 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/adrg/xdg"
+	"go.seanlatimer.dev/ignr/internal/templates"
 	_ "go.seanlatimer.dev/ignr/internal/xdginit"
 )
 
 const (
 	configDirName  = "ignr"
 	configFileName = "config.json"
+
+	// HomeEnv overrides the directory holding config, presets, and the template cache.
+	HomeEnv = "IGNR_HOME"
+	// ConfigEnv overrides the path of config.json only; presets and the cache stay under GetConfigDir.
+	ConfigEnv = "IGNR_CONFIG"
+)
+
+// configPathOverride is the config file chosen with SetConfigPath (the --config flag).
+var configPathOverride string
+
+// ErrNotWritable is returned when the config or cache directory cannot be written to.
+var ErrNotWritable = errors.New("config/cache directory is not writable")
+
+// SearchMode controls how search queries are matched against template and preset names.
+type SearchMode string
+
+const (
+	SearchModeFuzzy     SearchMode = "fuzzy"
+	SearchModeSubstring SearchMode = "substring"
+	SearchModePrefix    SearchMode = "prefix"
 )
 
 type Config struct {
-	DefaultOutput    string `json:"default_output"`
-	UserTemplatePath string `json:"user_template_path"`
+	DefaultOutput    string     `json:"default_output"`
+	UserTemplatePath string     `json:"user_template_path"`
+	SearchMode       SearchMode `json:"search_mode,omitempty"`
+	// AutoUpdateOnGenerate pulls the template cache before every generate (best effort).
+	AutoUpdateOnGenerate bool `json:"auto_update_on_generate,omitempty"`
+	// HiddenCategories are left out of list, search, and interactive selection,
+	// e.g. "community" or "community/JavaScript". Explicit names still resolve.
+	HiddenCategories []templates.Category `json:"hidden_categories,omitempty"`
+	// TemplateRepoRef pins the template cache to a branch, tag, or full commit hash.
+	// Empty follows the repository's default branch.
+	TemplateRepoRef string `json:"template_repo_ref,omitempty"`
+	// AlwaysExclude lists patterns removed from every generated file, even when a selected
+	// template contains them (e.g. ".env" so secrets files must be ignored deliberately).
+	AlwaysExclude []string `json:"always_exclude,omitempty"`
+	// DefaultTemplates are added ahead of the selection in every generate and preset use,
+	// e.g. "macOS" or "VisualStudioCode".
+	DefaultTemplates []string `json:"default_templates,omitempty"`
+	// PlainTUI draws interactive views with ASCII borders instead of box-drawing characters.
+	PlainTUI bool `json:"plain_tui,omitempty"`
+	// LineEnding is the default line ending of written files, "lf" or "crlf". Empty means "lf".
+	LineEnding string `json:"line_ending,omitempty"`
+	// CacheTTL is how old the template cache may get, as a duration such as "168h", before
+	// commands that read it pull it first. Empty never refreshes automatically.
+	CacheTTL string `json:"cache_ttl,omitempty"`
+}
+
+// CacheTTLDuration parses CacheTTL. An empty value is zero, meaning no automatic refresh.
+func (c Config) CacheTTLDuration() (time.Duration, error) {
+	if strings.TrimSpace(c.CacheTTL) == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(strings.TrimSpace(c.CacheTTL))
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid cache_ttl %q: want a duration such as 24h or 168h", c.CacheTTL)
+	}
+	return ttl, nil
 }
 
 func GetConfigDir() (string, error) {
+	if home := strings.TrimSpace(os.Getenv(HomeEnv)); home != "" {
+		return home, nil
+	}
 	return filepath.Join(xdg.ConfigHome, configDirName), nil
 }
 
+// WrapWriteError reports permission and read-only filesystem failures under dir as ErrNotWritable.
+// Other errors are returned unchanged.
+func WrapWriteError(dir string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, fs.ErrPermission) || errors.Is(err, syscall.EROFS) {
+		return fmt.Errorf("%w: %s (set %s to a writable directory)", ErrNotWritable, dir, HomeEnv)
+	}
+	return err
+}
+
+// SetConfigPath makes GetConfigPath return path, ahead of ConfigEnv. Empty clears the override.
+func SetConfigPath(path string) {
+	configPathOverride = strings.TrimSpace(path)
+}
+
+// GetConfigPath returns the config file path: the SetConfigPath override, then ConfigEnv,
+// then config.json in GetConfigDir.
 func GetConfigPath() (string, error) {
+	if configPathOverride != "" {
+		return configPathOverride, nil
+	}
+	if path := strings.TrimSpace(os.Getenv(ConfigEnv)); path != "" {
+		return path, nil
+	}
 	dir, err := GetConfigDir()
 	if err != nil {
 		return "", err
@@ -51,7 +138,7 @@ func GetUserTemplatePath() (string, error) {
 	}
 
 	if err := os.MkdirAll(path, 0o755); err != nil {
-		return "", fmt.Errorf("create user templates dir: %w", err)
+		return "", WrapWriteError(path, fmt.Errorf("create user templates dir: %w", err))
 	}
 	return path, nil
 }
@@ -63,7 +150,7 @@ func GetPresetsPath() (string, error) {
 	}
 
 	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return "", fmt.Errorf("create config dir: %w", err)
+		return "", WrapWriteError(dir, fmt.Errorf("create config dir: %w", err))
 	}
 
 	path := filepath.Join(dir, "presets.yaml")
@@ -72,12 +159,22 @@ func GetPresetsPath() (string, error) {
 			return "", fmt.Errorf("check presets file: %w", err)
 		}
 		if err := os.WriteFile(path, []byte("presets: []\n"), 0o644); err != nil {
-			return "", fmt.Errorf("create presets file: %w", err)
+			return "", WrapWriteError(dir, fmt.Errorf("create presets file: %w", err))
 		}
 	}
 	return path, nil
 }
 
+// GetDetectionRulesPath returns where user detection rules are read from. The file is optional
+// and never created.
+func GetDetectionRulesPath() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "detection.yaml"), nil
+}
+
 func LoadConfig() (Config, error) {
 	path, err := GetConfigPath()
 	if err != nil {
@@ -106,7 +203,7 @@ func SaveConfig(cfg Config) error {
 	}
 
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return fmt.Errorf("create config dir: %w", err)
+		return WrapWriteError(filepath.Dir(path), fmt.Errorf("create config dir: %w", err))
 	}
 
 	data, err := json.MarshalIndent(cfg, "", "  ")
@@ -115,7 +212,7 @@ func SaveConfig(cfg Config) error {
 	}
 
 	if err := os.WriteFile(path, data, 0o644); err != nil {
-		return fmt.Errorf("write config: %w", err)
+		return WrapWriteError(filepath.Dir(path), fmt.Errorf("write config: %w", err))
 	}
 	return nil
 }
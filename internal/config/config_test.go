@@ -2,8 +2,11 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -412,3 +415,101 @@ func TestConfigJSONFormatting(t *testing.T) {
 		t.Fatalf("SaveConfig() saved invalid JSON: %v", err)
 	}
 }
+
+func TestGetConfigDirHomeOverride(t *testing.T) {
+	cleanup := setupConfigTest(t)
+	defer cleanup()
+
+	home := t.TempDir()
+	t.Setenv(HomeEnv, home)
+
+	dir, err := GetConfigDir()
+	if err != nil {
+		t.Fatalf("GetConfigDir() error = %v", err)
+	}
+	if dir != home {
+		t.Errorf("GetConfigDir() = %q, want %q", dir, home)
+	}
+}
+
+func TestWrapWriteError(t *testing.T) {
+	permErr := &fs.PathError{Op: "open", Path: "/ro/config.json", Err: fs.ErrPermission}
+	err := WrapWriteError("/ro", permErr)
+	if !errors.Is(err, ErrNotWritable) {
+		t.Fatalf("WrapWriteError() = %v, want ErrNotWritable", err)
+	}
+	if !strings.Contains(err.Error(), "/ro") || !strings.Contains(err.Error(), HomeEnv) {
+		t.Errorf("WrapWriteError() = %q, want path and %s guidance", err, HomeEnv)
+	}
+
+	otherErr := errors.New("disk on fire")
+	if got := WrapWriteError("/ro", otherErr); got != otherErr {
+		t.Errorf("WrapWriteError() = %v, want unchanged error", got)
+	}
+	if WrapWriteError("/ro", nil) != nil {
+		t.Error("WrapWriteError(nil) should return nil")
+	}
+}
+
+func TestSaveConfigReadOnlyDir(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("chmod-based read-only directories are not enforced on this platform/user")
+	}
+	cleanup := setupConfigTest(t)
+	defer cleanup()
+
+	home := t.TempDir()
+	t.Setenv(HomeEnv, home)
+	if err := os.Chmod(home, 0o555); err != nil {
+		t.Fatalf("failed to make dir read-only: %v", err)
+	}
+	defer func() {
+		_ = os.Chmod(home, 0o755)
+	}()
+
+	err := SaveConfig(Config{DefaultOutput: ".gitignore"})
+	if !errors.Is(err, ErrNotWritable) {
+		t.Fatalf("SaveConfig() error = %v, want ErrNotWritable", err)
+	}
+	if !strings.Contains(err.Error(), "config/cache directory is not writable: "+home) {
+		t.Errorf("SaveConfig() error = %q, want friendly message with path", err)
+	}
+}
+
+func TestGetConfigPathPrecedence(t *testing.T) {
+	cleanup := setupConfigTest(t)
+	defer cleanup()
+	t.Cleanup(func() { SetConfigPath("") })
+
+	dir, err := GetConfigDir()
+	if err != nil {
+		t.Fatalf("GetConfigDir() error = %v", err)
+	}
+	envPath := filepath.Join(t.TempDir(), "env.json")
+	flagPath := filepath.Join(t.TempDir(), "flag.json")
+
+	tests := []struct {
+		name     string
+		env      string
+		override string
+		want     string
+	}{
+		{name: "default", want: filepath.Join(dir, configFileName)},
+		{name: "env", env: envPath, want: envPath},
+		{name: "override beats env", env: envPath, override: flagPath, want: flagPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ConfigEnv, tt.env)
+			SetConfigPath(tt.override)
+			got, err := GetConfigPath()
+			if err != nil {
+				t.Fatalf("GetConfigPath() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetConfigPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownKey is returned by Get and Set for a key that is not a config field.
+var ErrUnknownKey = errors.New("unknown config key")
+
+// allowedValues restricts keys that only accept a fixed set of values. Empty is always
+// allowed and restores the default.
+var allowedValues = map[string][]string{
+	"search_mode": {string(SearchModeFuzzy), string(SearchModeSubstring), string(SearchModePrefix)},
+	"line_ending": {"lf", "crlf"},
+}
+
+// validators check keys whose values need more than a fixed set of choices.
+var validators = map[string]func(string) error{
+	"cache_ttl": func(value string) error {
+		_, err := Config{CacheTTL: value}.CacheTTLDuration()
+		return err
+	},
+}
+
+// Keys returns the config keys, as written in config.json, in the order of the Config fields.
+func Keys() []string {
+	t := reflect.TypeFor[Config]()
+	keys := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		keys = append(keys, jsonKey(t.Field(i)))
+	}
+	return keys
+}
+
+// Get returns the value of key for display: lists are comma-separated and an unset
+// value is empty.
+func (c Config) Get(key string) (string, error) {
+	field, err := configField(reflect.ValueOf(&c).Elem(), key)
+	if err != nil {
+		return "", err
+	}
+	switch field.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool()), nil
+	case reflect.Slice:
+		list := make([]string, field.Len())
+		for i := range list {
+			list[i] = field.Index(i).String()
+		}
+		return strings.Join(list, ","), nil
+	default:
+		return field.String(), nil
+	}
+}
+
+// Set parses value and stores it under key. Booleans accept the forms strconv.ParseBool does,
+// lists are comma-separated, and an empty value resets the key to its default.
+func (c *Config) Set(key, value string) error {
+	field, err := configField(reflect.ValueOf(c).Elem(), key)
+	if err != nil {
+		return err
+	}
+	value = strings.TrimSpace(value)
+	switch field.Kind() {
+	case reflect.Bool:
+		if value == "" {
+			field.SetBool(false)
+			return nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for %s: must be true or false", value, key)
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		// Lists hold strings or string types such as templates.Category; empty stays nil.
+		list := reflect.Zero(field.Type())
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = reflect.Append(list, reflect.ValueOf(item).Convert(field.Type().Elem()))
+			}
+		}
+		field.Set(list)
+	default:
+		if allowed, ok := allowedValues[key]; ok && value != "" {
+			if !containsFold(allowed, value) {
+				return fmt.Errorf("invalid value %q for %s: must be one of %s", value, key, strings.Join(allowed, ", "))
+			}
+			value = strings.ToLower(value)
+		}
+		if validate, ok := validators[key]; ok {
+			if err := validate(value); err != nil {
+				return err
+			}
+		}
+		field.SetString(value)
+	}
+	return nil
+}
+
+// configField returns the field of cfg, a Config, whose JSON key is key.
+func configField(cfg reflect.Value, key string) (reflect.Value, error) {
+	key = strings.TrimSpace(key)
+	for i := range cfg.NumField() {
+		if jsonKey(cfg.Type().Field(i)) == key {
+			return cfg.Field(i), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("%w: %q (valid keys: %s)", ErrUnknownKey, key, strings.Join(Keys(), ", "))
+}
+
+func jsonKey(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return name
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+func TestKeys(t *testing.T) {
+	keys := Keys()
+	if len(keys) == 0 || keys[0] != "default_output" || keys[1] != "user_template_path" {
+		t.Errorf("Keys() = %v, want default_output and user_template_path first", keys)
+	}
+}
+
+func TestConfigSetGet(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		want    string
+		wantErr string
+	}{
+		{name: "string", key: "default_output", value: "out/.gitignore", want: "out/.gitignore"},
+		{name: "bool", key: "auto_update_on_generate", value: "true", want: "true"},
+		{name: "list", key: "always_exclude", value: " Go, ,Node ", want: "Go,Node"},
+		{name: "allowed value is lowercased", key: "search_mode", value: "Prefix", want: "prefix"},
+		{name: "empty resets", key: "line_ending", value: "", want: ""},
+		{name: "bad bool", key: "plain_tui", value: "maybe", wantErr: "must be true or false"},
+		{name: "duration", key: "cache_ttl", value: "168h", want: "168h"},
+		{name: "bad duration", key: "cache_ttl", value: "7d", wantErr: "invalid cache_ttl"},
+		{name: "bad choice", key: "line_ending", value: "cr", wantErr: "must be one of lf, crlf"},
+		{name: "unknown key", key: "DefaultOutput", value: "x", wantErr: "unknown config key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg Config
+			err := cfg.Set(tt.key, tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Set() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			got, err := cfg.Get(tt.key)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Get() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigSetList(t *testing.T) {
+	var cfg Config
+	if err := cfg.Set("hidden_categories", "community,global"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if want := []templates.Category{"community", "global"}; !reflect.DeepEqual(cfg.HiddenCategories, want) {
+		t.Errorf("HiddenCategories = %v, want %v", cfg.HiddenCategories, want)
+	}
+	if err := cfg.Set("hidden_categories", ""); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if cfg.HiddenCategories != nil {
+		t.Errorf("HiddenCategories = %v, want nil after clearing", cfg.HiddenCategories)
+	}
+	if _, err := cfg.Get("nope"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Get(nope) error = %v, want ErrUnknownKey", err)
+	}
+}
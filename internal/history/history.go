@@ -0,0 +1,164 @@
+// Package history keeps previous versions of files ignr overwrites so they can be restored.
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.seanlatimer.dev/ignr/internal/config"
+)
+
+const (
+	historyDirName = "history"
+	pathFileName   = "path"
+	versionExt     = ".bak"
+
+	// MaxVersions is how many previous versions are kept per file; older ones are dropped.
+	MaxVersions = 5
+)
+
+// ErrNoHistory is returned by Restore when no previous version of a file is stored.
+var ErrNoHistory = errors.New("no stashed versions")
+
+// GetHistoryDir returns the directory holding stashed versions.
+func GetHistoryDir() (string, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, historyDirName), nil
+}
+
+// entryDir returns the absolute form of target and the directory holding its versions.
+// Entries are keyed by a hash of the absolute path so any path maps to one safe name.
+func entryDir(target string) (string, string, error) {
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return "", "", fmt.Errorf("resolve path: %w", err)
+	}
+	dir, err := GetHistoryDir()
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return abs, filepath.Join(dir, hex.EncodeToString(sum[:8])), nil
+}
+
+// Stash saves the current content of target as its newest version, keeping at most
+// MaxVersions. A missing target is not an error; there is nothing to undo to.
+func Stash(target string) error {
+	data, err := os.ReadFile(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("read %s: %w", target, err)
+	}
+
+	abs, dir, err := entryDir(target)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return config.WrapWriteError(dir, fmt.Errorf("create history dir: %w", err))
+	}
+	if err := os.WriteFile(filepath.Join(dir, pathFileName), []byte(abs+"\n"), 0o644); err != nil {
+		return config.WrapWriteError(dir, fmt.Errorf("write history: %w", err))
+	}
+
+	// Names sort chronologically; bump past a clash from two stashes in the same nanosecond.
+	stamp := time.Now().UnixNano()
+	path := filepath.Join(dir, fmt.Sprintf("%020d%s", stamp, versionExt))
+	for fileExists(path) {
+		stamp++
+		path = filepath.Join(dir, fmt.Sprintf("%020d%s", stamp, versionExt))
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return config.WrapWriteError(dir, fmt.Errorf("write history: %w", err))
+	}
+	return prune(dir)
+}
+
+// Restore writes the newest stashed version back to target and removes it from the
+// history, so repeated calls step further back. It returns ErrNoHistory when nothing is stored.
+func Restore(target string) error {
+	_, dir, err := entryDir(target)
+	if err != nil {
+		return err
+	}
+	versions, err := listVersions(dir)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		return fmt.Errorf("%w for %s", ErrNoHistory, target)
+	}
+
+	latest := filepath.Join(dir, versions[len(versions)-1])
+	data, err := os.ReadFile(latest)
+	if err != nil {
+		return fmt.Errorf("read history: %w", err)
+	}
+	if err := os.WriteFile(target, data, 0o644); err != nil {
+		return fmt.Errorf("restore %s: %w", target, err)
+	}
+	if err := os.Remove(latest); err != nil {
+		return fmt.Errorf("remove restored version: %w", err)
+	}
+	return nil
+}
+
+// Count returns how many versions of target are stored.
+func Count(target string) (int, error) {
+	_, dir, err := entryDir(target)
+	if err != nil {
+		return 0, err
+	}
+	versions, err := listVersions(dir)
+	return len(versions), err
+}
+
+// listVersions returns version file names oldest first.
+func listVersions(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+	versions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), versionExt) {
+			versions = append(versions, entry.Name())
+		}
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func prune(dir string) error {
+	versions, err := listVersions(dir)
+	if err != nil {
+		return err
+	}
+	for len(versions) > MaxVersions {
+		if err := os.Remove(filepath.Join(dir, versions[0])); err != nil {
+			return fmt.Errorf("prune history: %w", err)
+		}
+		versions = versions[1:]
+	}
+	return nil
+}
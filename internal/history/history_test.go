@@ -0,0 +1,122 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrg/xdg"
+)
+
+func setupHistoryTest(t *testing.T) func() {
+	t.Helper()
+	originalConfigHome := xdg.ConfigHome
+	xdg.ConfigHome = t.TempDir()
+	return func() {
+		xdg.ConfigHome = originalConfigHome
+	}
+}
+
+func TestStashThenRestore(t *testing.T) {
+	cleanup := setupHistoryTest(t)
+	defer cleanup()
+
+	target := filepath.Join(t.TempDir(), ".gitignore")
+	original := "# hand-written\n*.log\n\n!keep.log\n"
+	if err := os.WriteFile(target, []byte(original), 0o644); err != nil {
+		t.Fatalf("failed to write target: %v", err)
+	}
+
+	if err := Stash(target); err != nil {
+		t.Fatalf("Stash() error = %v", err)
+	}
+	if err := os.WriteFile(target, []byte("# generated\n"), 0o644); err != nil {
+		t.Fatalf("failed to overwrite target: %v", err)
+	}
+
+	if err := Restore(target); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("failed to read target: %v", err)
+	}
+	if string(data) != original {
+		t.Errorf("restored content = %q, want %q", string(data), original)
+	}
+
+	if err := Restore(target); !errors.Is(err, ErrNoHistory) {
+		t.Errorf("second Restore() error = %v, want ErrNoHistory", err)
+	}
+}
+
+func TestRestoreStepsBack(t *testing.T) {
+	cleanup := setupHistoryTest(t)
+	defer cleanup()
+
+	target := filepath.Join(t.TempDir(), ".gitignore")
+	for i := 1; i <= 3; i++ {
+		if err := os.WriteFile(target, []byte(fmt.Sprintf("v%d\n", i)), 0o644); err != nil {
+			t.Fatalf("failed to write target: %v", err)
+		}
+		if err := Stash(target); err != nil {
+			t.Fatalf("Stash() error = %v", err)
+		}
+	}
+
+	for _, want := range []string{"v3\n", "v2\n", "v1\n"} {
+		if err := Restore(target); err != nil {
+			t.Fatalf("Restore() error = %v", err)
+		}
+		data, err := os.ReadFile(target)
+		if err != nil {
+			t.Fatalf("failed to read target: %v", err)
+		}
+		if string(data) != want {
+			t.Errorf("restored content = %q, want %q", string(data), want)
+		}
+	}
+}
+
+func TestStashKeepsMaxVersions(t *testing.T) {
+	cleanup := setupHistoryTest(t)
+	defer cleanup()
+
+	target := filepath.Join(t.TempDir(), ".gitignore")
+	for i := 0; i < MaxVersions+3; i++ {
+		if err := os.WriteFile(target, []byte(fmt.Sprintf("v%d\n", i)), 0o644); err != nil {
+			t.Fatalf("failed to write target: %v", err)
+		}
+		if err := Stash(target); err != nil {
+			t.Fatalf("Stash() error = %v", err)
+		}
+	}
+
+	count, err := Count(target)
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != MaxVersions {
+		t.Errorf("Count() = %d, want %d", count, MaxVersions)
+	}
+
+	other := filepath.Join(t.TempDir(), ".gitignore")
+	if count, _ := Count(other); count != 0 {
+		t.Errorf("Count() for another path = %d, want 0", count)
+	}
+}
+
+func TestStashMissingFile(t *testing.T) {
+	cleanup := setupHistoryTest(t)
+	defer cleanup()
+
+	target := filepath.Join(t.TempDir(), ".gitignore")
+	if err := Stash(target); err != nil {
+		t.Fatalf("Stash() of missing file error = %v", err)
+	}
+	if count, _ := Count(target); count != 0 {
+		t.Errorf("Count() = %d, want 0", count)
+	}
+}
@@ -0,0 +1,166 @@
+// Package ignorefile writes generated ignore files. The CLI and the preset TUI both go through it,
+// so the same templates and config produce the same file either way.
+package ignorefile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/history"
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+const (
+	LineEndingLF   = "lf"
+	LineEndingCRLF = "crlf"
+)
+
+// ResolveLineEnding returns flag, falling back to line_ending in cfg and then to LF on every OS.
+func ResolveLineEnding(flag string, cfg config.Config) (string, error) {
+	lineEnding := strings.ToLower(strings.TrimSpace(flag))
+	if lineEnding == "" {
+		lineEnding = strings.ToLower(strings.TrimSpace(cfg.LineEnding))
+	}
+	switch lineEnding {
+	case "":
+		return LineEndingLF, nil
+	case LineEndingLF, LineEndingCRLF:
+		return lineEnding, nil
+	default:
+		return "", fmt.Errorf("invalid line ending %q: must be %s or %s", lineEnding, LineEndingLF, LineEndingCRLF)
+	}
+}
+
+// ConvertLineEndings rewrites every line break in content as lineEnding. Templates with
+// CRLF line breaks are normalized too, so LF output never contains \r\n.
+func ConvertLineEndings(content, lineEnding string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if lineEnding == LineEndingCRLF {
+		return strings.ReplaceAll(content, "\n", "\r\n")
+	}
+	return content
+}
+
+// ResolveDefaults looks up the default_templates names in items. Names that match nothing
+// are returned in missing for the caller to report.
+func ResolveDefaults(names []string, items []templates.Template) (found []templates.Template, missing []string) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	index := templates.BuildIndex(items)
+	found = make([]templates.Template, 0, len(names))
+	for _, name := range names {
+		t, ok := templates.FindTemplate(index, name)
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		found = append(found, t)
+	}
+	return found, missing
+}
+
+// WithDefaults returns defaults followed by selected, keeping only the first occurrence of
+// each template.
+func WithDefaults(defaults, selected []templates.Template) []templates.Template {
+	if len(defaults) == 0 {
+		return selected
+	}
+	seen := make(map[string]bool, len(defaults)+len(selected))
+	combined := make([]templates.Template, 0, len(defaults)+len(selected))
+	for _, t := range slices.Concat(defaults, selected) {
+		if seen[t.Path] {
+			continue
+		}
+		seen[t.Path] = true
+		combined = append(combined, t)
+	}
+	return combined
+}
+
+// Render loads selected and merges them with opts.
+func Render(selected []templates.Template, opts templates.MergeOptions) (string, error) {
+	loaded, err := templates.LoadTemplates(selected)
+	if err != nil {
+		return "", err
+	}
+	return templates.MergeTemplates(loaded, opts), nil
+}
+
+// Generate renders selected with opts and replaces path with the result; see Write.
+func Generate(path string, selected []templates.Template, opts templates.MergeOptions, lineEnding string) error {
+	content, err := Render(selected, opts)
+	if err != nil {
+		return err
+	}
+	return Write(path, content, false, lineEnding)
+}
+
+// Write writes content to path with lineEnding line breaks, first stashing any existing file so
+// `ignr undo` can restore it. Missing parent directories are created. The file is replaced
+// atomically, or content is added to its end with appendMode.
+func Write(path, content string, appendMode bool, lineEnding string) error {
+	content = ConvertLineEndings(content, lineEnding)
+	if err := history.Stash(path); err != nil {
+		return fmt.Errorf("save previous %s: %w", path, err)
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return config.WrapWriteError(dir, fmt.Errorf("create %s: %w", dir, err))
+	}
+	if appendMode {
+		return AppendFile(path, content)
+	}
+	return WriteFileAtomic(path, []byte(content))
+}
+
+// WriteFileAtomic replaces path with data through a temporary file in the same directory,
+// so an interrupted write never leaves a truncated file. An existing file keeps its mode.
+func WriteFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// AppendFile adds content to the end of path, creating it if needed.
+func AppendFile(path, content string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	_, writeErr := file.WriteString(content)
+	closeErr := file.Close()
+	if writeErr != nil {
+		return writeErr
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package ignorefile
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/history"
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+func TestResolveLineEnding(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    string
+		config  string
+		want    string
+		wantErr bool
+	}{
+		{name: "default", want: LineEndingLF},
+		{name: "config", config: "CRLF", want: LineEndingCRLF},
+		{name: "flag wins", flag: "lf", config: "crlf", want: LineEndingLF},
+		{name: "invalid", flag: "cr", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveLineEnding(tt.flag, config.Config{LineEnding: tt.config})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveLineEnding() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveLineEnding() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	items := []templates.Template{
+		{Name: "Go", Category: templates.CategoryRoot, Path: "/Go.gitignore"},
+		{Name: "macOS", Category: templates.CategoryGlobal, Path: "/Global/macOS.gitignore"},
+	}
+
+	defaults, missing := ResolveDefaults([]string{"macos", "Nope"}, items)
+	if len(defaults) != 1 || defaults[0].Name != "macOS" || !slices.Equal(missing, []string{"Nope"}) {
+		t.Fatalf("ResolveDefaults() = %v, %v; want [macOS], [Nope]", defaults, missing)
+	}
+
+	var names []string
+	for _, tmpl := range WithDefaults(defaults, []templates.Template{items[0], items[1]}) {
+		names = append(names, tmpl.Name)
+	}
+	if !slices.Equal(names, []string{"macOS", "Go"}) {
+		t.Errorf("WithDefaults() = %v, want [macOS Go]", names)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Setenv(config.HomeEnv, t.TempDir())
+	path := filepath.Join(t.TempDir(), "nested", ".gitignore")
+
+	if err := Write(path, "a\nb\n", false, LineEndingCRLF); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := Write(path, "c\n", true, LineEndingLF); err != nil {
+		t.Fatalf("Write(append) error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if string(data) != "a\r\nb\r\nc\n" {
+		t.Errorf("written = %q, want %q", data, "a\r\nb\r\nc\n")
+	}
+
+	count, err := history.Count(path)
+	if err != nil {
+		t.Fatalf("history.Count() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("history has %d versions, want the first write stashed before appending", count)
+	}
+}
@@ -4,27 +4,66 @@ package presets
 import (
 	"fmt"
 	"io/fs"
+	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 )
 
+// DetectionRule suggests Templates when any of Patterns, matched case-insensitively with
+// filepath.Match, names a file or (with a trailing slash) a directory in the project.
 type DetectionRule struct {
-	Patterns  []string
-	Templates []string
+	Patterns  []string `yaml:"patterns"`
+	Templates []string `yaml:"templates"`
 }
 
+// DefaultDetectDepth is how many directory levels below the scanned root DetectFiles enters.
+const DefaultDetectDepth = 2
+
+// DetectFiles lists the file and directory names (directories with a trailing slash) in
+// repoPath and up to DefaultDetectDepth directory levels below it; see DetectFilesDepth.
 func DetectFiles(repoPath string) ([]string, error) {
+	return DetectFilesDepth(repoPath, DefaultDetectDepth)
+}
+
+// DetectFilesDepth lists the file and directory names in repoPath and in the directories up
+// to maxDepth levels below it (0 means repoPath's own entries only). node_modules, vendor, and directories ignored by
+// repoPath's .gitignore are listed but not entered, so markers of dependencies are not
+// picked up; .git is skipped entirely.
+func DetectFilesDepth(repoPath string, maxDepth int) ([]string, error) {
+	if maxDepth < 0 {
+		return nil, fmt.Errorf("invalid depth %d: must not be negative", maxDepth)
+	}
+	ignored, err := readIgnoreMatcher(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
 	detected := map[string]struct{}{}
-	err := filepath.WalkDir(repoPath, func(path string, d fs.DirEntry, err error) error {
+	err = filepath.WalkDir(repoPath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 		name := strings.ToLower(d.Name())
+		if path == repoPath {
+			detected[name+"/"] = struct{}{}
+			return nil
+		}
+		rel, err := filepath.Rel(repoPath, path)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+
 		if d.IsDir() {
 			if name == ".git" {
 				return filepath.SkipDir
 			}
 			detected[name+"/"] = struct{}{}
+			if len(parts) > maxDepth || skippedSubdirs[name] || ignored.Match(parts, true) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
@@ -42,8 +81,103 @@ func DetectFiles(repoPath string) ([]string, error) {
 	return list, nil
 }
 
+// readIgnoreMatcher parses the .gitignore in dir. A missing file ignores nothing.
+func readIgnoreMatcher(dir string) (gitignore.Matcher, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read .gitignore: %w", err)
+	}
+	var patterns []gitignore.Pattern
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	return gitignore.NewMatcher(patterns), nil
+}
+
+// DirSuggestions holds the templates suggested for one subdirectory.
+type DirSuggestions struct {
+	// Dir is relative to the scanned root, with forward slashes.
+	Dir       string
+	Templates []string
+}
+
+// skippedSubdirs hold dependencies: they are never treated as subprojects, and
+// DetectFiles does not look inside them.
+var skippedSubdirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// SuggestSubdirs runs detection separately in each directory up to depth levels below
+// root (1 means immediate subdirectories), so each package of a monorepo gets its own
+// suggestions. Hidden directories, node_modules, and vendor are skipped. Directories
+// with no suggestions are left out; results are in walk order.
+func SuggestSubdirs(root string, depth int) ([]DirSuggestions, error) {
+	if depth < 1 {
+		return nil, fmt.Errorf("invalid depth %d: must be at least 1", depth)
+	}
+
+	rules, err := DetectionRules()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []DirSuggestions
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || path == root {
+			return nil
+		}
+		name := d.Name()
+		if strings.HasPrefix(name, ".") || skippedSubdirs[strings.ToLower(name)] {
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		level := len(strings.Split(rel, string(filepath.Separator)))
+
+		detected, err := DetectFiles(path)
+		if err != nil {
+			return err
+		}
+		suggested := MatchRules(rules, detected)
+		if len(suggested) > 0 {
+			results = append(results, DirSuggestions{Dir: filepath.ToSlash(rel), Templates: suggested})
+		}
+
+		if level >= depth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan subdirectories: %w", err)
+	}
+	return results, nil
+}
+
+// SuggestTemplates returns the templates suggested for detected names by the built-in
+// rules and the user's detection.yaml.
 func SuggestTemplates(detected []string) ([]string, error) {
-	rules := defaultDetectionRules()
+	rules, err := DetectionRules()
+	if err != nil {
+		return nil, err
+	}
+	return MatchRules(rules, detected), nil
+}
+
+// MatchRules returns the templates of every rule matching detected, in rule order, keeping
+// the first spelling of names that differ only in case.
+func MatchRules(rules []DetectionRule, detected []string) []string {
 	suggestions := make([]string, 0)
 	seen := map[string]struct{}{}
 
@@ -60,7 +194,7 @@ func SuggestTemplates(detected []string) ([]string, error) {
 		}
 	}
 
-	return suggestions, nil
+	return suggestions
 }
 
 func ruleMatches(rule DetectionRule, detected []string) bool {
@@ -90,14 +224,21 @@ func defaultDetectionRules() []DetectionRule {
 		{Patterns: []string{"build.gradle", "build.gradle.kts"}, Templates: []string{"Gradle"}},
 		{Patterns: []string{"*.csproj"}, Templates: []string{"VisualStudio"}},
 		{Patterns: []string{"composer.json"}, Templates: []string{"Composer"}},
-		{Patterns: []string{"gemfile"}, Templates: []string{"Ruby"}},
+		{Patterns: []string{"gemfile", "gemfile.lock"}, Templates: []string{"Ruby"}},
 		{Patterns: []string{"*.swift"}, Templates: []string{"Swift"}},
 		{Patterns: []string{"*.kt", "*.kts"}, Templates: []string{"Kotlin"}},
-		{Patterns: []string{"*.dart"}, Templates: []string{"Dart"}},
+		{Patterns: []string{"*.dart", "pubspec.yaml"}, Templates: []string{"Dart"}},
+		{Patterns: []string{"mix.exs"}, Templates: []string{"Elixir"}},
+		{Patterns: []string{"*.cabal", "stack.yaml"}, Templates: []string{"Haskell"}},
+		{Patterns: []string{"cmakelists.txt"}, Templates: []string{"C++"}},
+		{Patterns: []string{"*.r", "description"}, Templates: []string{"R"}},
 		{Patterns: []string{"*.ts", "*.tsx"}, Templates: []string{"TypeScript"}},
 		{Patterns: []string{".idea/"}, Templates: []string{"IntelliJ"}},
 		{Patterns: []string{".vscode/"}, Templates: []string{"VisualStudioCode"}},
 		{Patterns: []string{"*.xcodeproj"}, Templates: []string{"Xcode"}},
 		{Patterns: []string{"*.sln"}, Templates: []string{"VisualStudio"}},
+		// Deno and Docker have no upstream template; these match user templates of the same name.
+		{Patterns: []string{"deno.json", "deno.jsonc"}, Templates: []string{"Deno"}},
+		{Patterns: []string{"dockerfile", "*.dockerfile"}, Templates: []string{"Docker"}},
 	}
 }
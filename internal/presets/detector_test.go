@@ -3,8 +3,11 @@ package presets
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+
+	"go.seanlatimer.dev/ignr/internal/config"
 )
 
 func TestDetectFiles(t *testing.T) {
@@ -262,6 +265,72 @@ func TestSuggestTemplates(t *testing.T) {
 			wantSuggest: []string{"TypeScript"},
 			wantErr:     false,
 		},
+		{
+			name:        "Gemfile.lock suggests Ruby",
+			detected:    []string{"Gemfile.lock"},
+			wantSuggest: []string{"Ruby"},
+			wantErr:     false,
+		},
+		{
+			name:        "Gemfile and Gemfile.lock suggest Ruby once",
+			detected:    []string{"gemfile", "gemfile.lock"},
+			wantSuggest: []string{"Ruby"},
+			wantErr:     false,
+		},
+		{
+			name:        "mix.exs suggests Elixir",
+			detected:    []string{"mix.exs"},
+			wantSuggest: []string{"Elixir"},
+			wantErr:     false,
+		},
+		{
+			name:        "pubspec.yaml suggests Dart",
+			detected:    []string{"pubspec.yaml"},
+			wantSuggest: []string{"Dart"},
+			wantErr:     false,
+		},
+		{
+			name:        "deno.json suggests Deno",
+			detected:    []string{"deno.json"},
+			wantSuggest: []string{"Deno"},
+			wantErr:     false,
+		},
+		{
+			name:        "cabal file suggests Haskell",
+			detected:    []string{"app.cabal"},
+			wantSuggest: []string{"Haskell"},
+			wantErr:     false,
+		},
+		{
+			name:        "stack.yaml suggests Haskell",
+			detected:    []string{"stack.yaml"},
+			wantSuggest: []string{"Haskell"},
+			wantErr:     false,
+		},
+		{
+			name:        "CMakeLists.txt suggests C++",
+			detected:    []string{"CMakeLists.txt"},
+			wantSuggest: []string{"C++"},
+			wantErr:     false,
+		},
+		{
+			name:        "R script suggests R",
+			detected:    []string{"analysis.R"},
+			wantSuggest: []string{"R"},
+			wantErr:     false,
+		},
+		{
+			name:        "DESCRIPTION suggests R",
+			detected:    []string{"DESCRIPTION"},
+			wantSuggest: []string{"R"},
+			wantErr:     false,
+		},
+		{
+			name:        "Dockerfile suggests Docker",
+			detected:    []string{"Dockerfile"},
+			wantSuggest: []string{"Docker"},
+			wantErr:     false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -382,3 +451,213 @@ func TestRuleMatches(t *testing.T) {
 		})
 	}
 }
+
+// writeMonorepo creates a fixture with a Go service, a Node app with a nested Python
+// tool, a docs folder without a stack, and directories that must be skipped.
+func writeMonorepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := []string{
+		"go.work",
+		"services/api/go.mod",
+		"web/package.json",
+		"web/tools/requirements.txt",
+		"docs/index.md",
+		".cache/Cargo.toml",
+		"node_modules/left-pad/package.json",
+	}
+	for _, file := range files {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+	}
+	return root
+}
+
+func TestSuggestSubdirs(t *testing.T) {
+	root := writeMonorepo(t)
+
+	tests := []struct {
+		name  string
+		depth int
+		want  []string
+	}{
+		{
+			name:  "immediate subdirectories",
+			depth: 1,
+			want:  []string{"services: Go", "web: Node, Python"},
+		},
+		{
+			name:  "two levels",
+			depth: 2,
+			want:  []string{"services: Go", "services/api: Go", "web: Node, Python", "web/tools: Python"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, err := SuggestSubdirs(root, tt.depth)
+			if err != nil {
+				t.Fatalf("SuggestSubdirs() error = %v", err)
+			}
+			got := make([]string, 0, len(groups))
+			for _, group := range groups {
+				got = append(got, group.Dir+": "+strings.Join(group.Templates, ", "))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("SuggestSubdirs(depth %d) = %q, want %q", tt.depth, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := SuggestSubdirs(root, 0); err == nil {
+		t.Error("SuggestSubdirs() with depth 0 expected error, got nil")
+	}
+}
+
+func TestSuggestTemplatesCIConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(config.HomeEnv, home)
+
+	tests := []struct {
+		name  string
+		paths []string
+		want  string
+	}{
+		{name: "github directory", paths: []string{".github/workflows/ci.yml"}, want: "GitHubActions"},
+		{name: "gitlab ci file", paths: []string{".gitlab-ci.yml"}, want: "GitLabCI"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, path := range tt.paths {
+				full := filepath.Join(dir, filepath.FromSlash(path))
+				if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+					t.Fatalf("failed to create dir: %v", err)
+				}
+				if err := os.WriteFile(full, []byte("# test"), 0o644); err != nil {
+					t.Fatalf("failed to create file: %v", err)
+				}
+			}
+
+			detected, err := DetectFiles(dir)
+			if err != nil {
+				t.Fatalf("DetectFiles() error = %v", err)
+			}
+			// There is no upstream template for CI config, so nothing is suggested built in.
+			if suggested := MatchRules(defaultDetectionRules(), detected); len(suggested) != 0 {
+				t.Errorf("built-in rules suggested %v for %v, want nothing", suggested, detected)
+			}
+
+			rules := "rules:\n  - patterns: [.github/]\n    templates: [GitHubActions]\n  - patterns: [.gitlab-ci.yml]\n    templates: [GitLabCI]\n"
+			if err := os.WriteFile(filepath.Join(home, "detection.yaml"), []byte(rules), 0o644); err != nil {
+				t.Fatalf("failed to write rules: %v", err)
+			}
+			suggested, err := SuggestTemplates(detected)
+			if err != nil {
+				t.Fatalf("SuggestTemplates() error = %v", err)
+			}
+			if !slices.Contains(suggested, tt.want) {
+				t.Errorf("SuggestTemplates(%v) = %v, want %s from detection.yaml", detected, suggested, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectFilesSkipsDependenciesAndIgnoredDirs(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		depth int
+		want  []string
+		skip  []string
+	}{
+		{
+			name:  "nested node_modules package.json",
+			files: map[string]string{"go.mod": "", "node_modules/some-pkg/package.json": "{}"},
+			depth: DefaultDetectDepth,
+			want:  []string{"Go"},
+			skip:  []string{"Node"},
+		},
+		{
+			name:  "vendor",
+			files: map[string]string{"package.json": "{}", "vendor/github.com/x/go.mod": ""},
+			depth: DefaultDetectDepth,
+			want:  []string{"Node"},
+			skip:  []string{"Go"},
+		},
+		{
+			name:  "directory ignored by .gitignore",
+			files: map[string]string{".gitignore": "# deps\nthird_party/\n", "go.mod": "", "third_party/lib/Cargo.toml": ""},
+			depth: DefaultDetectDepth,
+			want:  []string{"Go"},
+			skip:  []string{"Rust"},
+		},
+		{
+			name:  "negated .gitignore pattern keeps the directory",
+			files: map[string]string{".gitignore": "*/\n!web/\n", "web/package.json": "{}"},
+			depth: DefaultDetectDepth,
+			want:  []string{"Node"},
+		},
+		{
+			name:  "beyond max depth",
+			files: map[string]string{"go.mod": "", "a/b/c/package.json": "{}"},
+			depth: DefaultDetectDepth,
+			want:  []string{"Go"},
+			skip:  []string{"Node"},
+		},
+		{
+			name:  "within a larger depth",
+			files: map[string]string{"a/b/c/package.json": "{}"},
+			depth: 3,
+			want:  []string{"Node"},
+		},
+		{
+			name:  "depth 0 reads only the root",
+			files: map[string]string{"go.mod": "", "web/package.json": "{}"},
+			depth: 0,
+			want:  []string{"Go"},
+			skip:  []string{"Node"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for path, content := range tt.files {
+				full := filepath.Join(dir, filepath.FromSlash(path))
+				if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+					t.Fatalf("failed to create dir: %v", err)
+				}
+				if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+					t.Fatalf("failed to create file: %v", err)
+				}
+			}
+
+			detected, err := DetectFilesDepth(dir, tt.depth)
+			if err != nil {
+				t.Fatalf("DetectFilesDepth() error = %v", err)
+			}
+			suggested := MatchRules(defaultDetectionRules(), detected)
+			for _, want := range tt.want {
+				if !slices.Contains(suggested, want) {
+					t.Errorf("suggestions %v (detected %v) missing %s", suggested, detected, want)
+				}
+			}
+			for _, skip := range tt.skip {
+				if slices.Contains(suggested, skip) {
+					t.Errorf("suggestions %v (detected %v) include %s", suggested, detected, skip)
+				}
+			}
+		})
+	}
+
+	if _, err := DetectFilesDepth(t.TempDir(), -1); err == nil {
+		t.Error("DetectFilesDepth(-1) expected an error")
+	}
+}
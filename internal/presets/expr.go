@@ -0,0 +1,106 @@
+package presets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+// ErrInvalidTemplateExpr is returned for malformed template set expressions.
+var ErrInvalidTemplateExpr = errors.New("invalid template expression")
+
+// IsTemplateExpr reports whether arg uses template expression syntax (a comma list
+// or a leading + or -) rather than naming a single template.
+func IsTemplateExpr(arg string) bool {
+	arg = strings.TrimSpace(arg)
+	return strings.Contains(arg, ",") || strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-")
+}
+
+// ParseTemplateExpr resolves a comma-separated template set expression such as
+// "Node,+VisualStudioCode,-Global/Linux" into template names, in the order added.
+// Terms are evaluated left to right: a bare or "+" term adds a template and a "-"
+// term removes one already added. Names may carry a category prefix
+// ("Global/Linux", "community/JavaScript/Vue") to pick a template within that category.
+// When index is nil, names are not checked and category prefixes are dropped.
+func ParseTemplateExpr(expr string, index *templates.Index) ([]string, error) {
+	var result []string
+	position := make(map[string]int)
+
+	for _, term := range strings.Split(expr, ",") {
+		term = strings.TrimSpace(term)
+		remove := false
+		switch {
+		case strings.HasPrefix(term, "+"):
+			term = strings.TrimSpace(term[1:])
+		case strings.HasPrefix(term, "-"):
+			term = strings.TrimSpace(term[1:])
+			remove = true
+		}
+		if term == "" {
+			return nil, fmt.Errorf("%w: empty term in %q", ErrInvalidTemplateExpr, expr)
+		}
+
+		name, err := resolveExprTerm(term, index)
+		if err != nil {
+			return nil, err
+		}
+		key := normalizeTemplateName(name)
+
+		if remove {
+			i, ok := position[key]
+			if !ok {
+				return nil, fmt.Errorf("%w: cannot remove %s, it was not added", ErrInvalidTemplateExpr, term)
+			}
+			result = append(result[:i], result[i+1:]...)
+			delete(position, key)
+			for k, j := range position {
+				if j > i {
+					position[k] = j - 1
+				}
+			}
+			continue
+		}
+		if _, ok := position[key]; ok {
+			continue
+		}
+		position[key] = len(result)
+		result = append(result, name)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%w: %q selects no templates", ErrInvalidTemplateExpr, expr)
+	}
+	return result, nil
+}
+
+// resolveExprTerm returns the template name a term refers to.
+func resolveExprTerm(term string, index *templates.Index) (string, error) {
+	category, name := "", term
+	if i := strings.LastIndex(term, "/"); i >= 0 {
+		category, name = strings.Trim(term[:i], "/"), term[i+1:]
+	}
+
+	if index == nil {
+		return name, nil
+	}
+	if category == "" {
+		t, ok := templates.FindTemplate(*index, name)
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, term)
+		}
+		return t.Name, nil
+	}
+
+	key := normalizeTemplateName(name)
+	for _, t := range index.List {
+		if normalizeTemplateName(t.Name) != key {
+			continue
+		}
+		if strings.EqualFold(category, string(t.Category)) || strings.EqualFold(category, t.QualifiedCategory()) {
+			return t.Name, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, term)
+}
@@ -0,0 +1,65 @@
+package presets
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+func TestParseTemplateExpr(t *testing.T) {
+	index := templates.BuildIndex([]templates.Template{
+		{Name: "Node", Category: templates.CategoryRoot},
+		{Name: "Go", Category: templates.CategoryRoot},
+		{Name: "VisualStudioCode", Category: templates.CategoryGlobal},
+		{Name: "Linux", Category: templates.CategoryGlobal},
+		{Name: "Vue", Category: templates.CategoryCommunity, Subcategory: "JavaScript"},
+	})
+
+	tests := []struct {
+		name    string
+		expr    string
+		noIndex bool
+		want    []string
+		wantErr error
+	}{
+		{name: "plain list", expr: "Node, go", want: []string{"Node", "Go"}},
+		{name: "add operator", expr: "Node,+VisualStudioCode", want: []string{"Node", "VisualStudioCode"}},
+		{name: "remove operator", expr: "Node,Linux,Go,-linux", want: []string{"Node", "Go"}},
+		{name: "category prefix", expr: "Node,+Global/Linux", want: []string{"Node", "Linux"}},
+		{name: "remove with category prefix", expr: "Node,Linux,-Global/Linux", want: []string{"Node"}},
+		{name: "subcategory prefix", expr: "community/JavaScript/Vue", want: []string{"Vue"}},
+		{name: "re-add after remove", expr: "Go,Node,-Go,+Go", want: []string{"Node", "Go"}},
+		{name: "duplicates collapse", expr: "Go,go.gitignore,+Go", want: []string{"Go"}},
+		{name: "no index keeps names", expr: "Node,+Global/Anything,-Node,Extra", noIndex: true, want: []string{"Anything", "Extra"}},
+		{name: "unknown template", expr: "Node,+Nope", wantErr: ErrTemplateNotFound},
+		{name: "wrong category", expr: "community/Linux", wantErr: ErrTemplateNotFound},
+		{name: "remove missing", expr: "Node,-Go", wantErr: ErrInvalidTemplateExpr},
+		{name: "empty term", expr: "Node,,Go", wantErr: ErrInvalidTemplateExpr},
+		{name: "bare operator", expr: "Node,+", wantErr: ErrInvalidTemplateExpr},
+		{name: "empty result", expr: "Node,-Node", wantErr: ErrInvalidTemplateExpr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := &index
+			if tt.noIndex {
+				idx = nil
+			}
+			got, err := ParseTemplateExpr(tt.expr, idx)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ParseTemplateExpr(%q) error = %v, want %v", tt.expr, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTemplateExpr(%q) error = %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTemplateExpr(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package presets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.seanlatimer.dev/ignr/internal/templates"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoPresets is returned by ParsePresets when a file holds no presets.
+var ErrNoPresets = errors.New("no presets found")
+
+// ImportConflict decides what ImportPresets does with a preset whose key is already taken.
+type ImportConflict int
+
+const (
+	// ConflictSkip keeps the existing preset and skips the imported one.
+	ConflictSkip ImportConflict = iota
+	// ConflictOverwrite replaces the existing preset in place.
+	ConflictOverwrite
+	// ConflictRename imports the preset under the first free key with a numeric suffix, e.g. web-dev-2.
+	ConflictRename
+)
+
+// ParsePresets decodes a shared presets file, such as one written by preset export.
+// The file must pass LintPresets and hold at least one preset; every issue is listed otherwise.
+func ParsePresets(data []byte) ([]Preset, error) {
+	if issues := LintPresets(data); len(issues) > 0 {
+		messages := make([]string, 0, len(issues))
+		for _, issue := range issues {
+			messages = append(messages, issue.String())
+		}
+		return nil, fmt.Errorf("invalid presets file: %s", strings.Join(messages, "; "))
+	}
+	var store PresetStore
+	if err := yaml.Unmarshal(data, &store); err != nil {
+		return nil, fmt.Errorf("parse presets: %w", err)
+	}
+	if len(store.Presets) == 0 {
+		return nil, ErrNoPresets
+	}
+	for i := range store.Presets {
+		if strings.TrimSpace(store.Presets[i].Key) == "" {
+			store.Presets[i].Key = SluggifyName(store.Presets[i].Name)
+		}
+	}
+	return store.Presets, nil
+}
+
+// ImportPresets adds list to the stored presets. A preset whose key is already taken is
+// handled by conflict; skipped ones are returned as skipped and the stored preset is left
+// unchanged. Created and Updated times in list are kept. When index is non-nil, every
+// template name in list must exist in it; nothing is saved otherwise.
+func ImportPresets(list []Preset, index *templates.Index, conflict ImportConflict) (imported, skipped []Preset, err error) {
+	for _, preset := range list {
+		if err := ValidateTemplates(preset.Templates, index); err != nil {
+			return nil, nil, fmt.Errorf("preset %s: %w", preset.Key, err)
+		}
+	}
+	store, err := LoadPresets()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	taken := make(map[string]int, len(store.Presets))
+	for i, preset := range store.Presets {
+		taken[strings.ToLower(preset.Key)] = i
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, preset := range list {
+		preset.Templates, _ = DedupeTemplates(preset.Templates)
+		if strings.TrimSpace(preset.Created) == "" {
+			preset.Created = now
+		}
+		if strings.TrimSpace(preset.Updated) == "" {
+			preset.Updated = preset.Created
+		}
+
+		if existing, ok := taken[strings.ToLower(preset.Key)]; ok {
+			switch conflict {
+			case ConflictOverwrite:
+				store.Presets[existing] = preset
+				imported = append(imported, preset)
+				continue
+			case ConflictRename:
+				preset.Key = freeKey(preset.Key, taken)
+			default:
+				skipped = append(skipped, preset)
+				continue
+			}
+		}
+		taken[strings.ToLower(preset.Key)] = len(store.Presets)
+		store.Presets = append(store.Presets, preset)
+		imported = append(imported, preset)
+	}
+	if len(imported) == 0 {
+		return nil, skipped, nil
+	}
+	return imported, skipped, SavePresets(store)
+}
+
+// freeKey returns key with the lowest numeric suffix, starting at 2, that is not in taken.
+func freeKey(key string, taken map[string]int) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", key, n)
+		if _, ok := taken[strings.ToLower(candidate)]; !ok {
+			return candidate
+		}
+	}
+}
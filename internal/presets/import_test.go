@@ -0,0 +1,136 @@
+package presets
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+func TestParsePresets(t *testing.T) {
+	list, err := ParsePresets([]byte("presets:\n  - name: My Web\n    templates: [Node, Go]\n"))
+	if err != nil {
+		t.Fatalf("ParsePresets() error = %v", err)
+	}
+	if len(list) != 1 || list[0].Key != "my-web" {
+		t.Errorf("ParsePresets() = %+v, want one preset keyed my-web", list)
+	}
+
+	if _, err := ParsePresets([]byte("presets: []\n")); !errors.Is(err, ErrNoPresets) {
+		t.Errorf("ParsePresets(empty) error = %v, want ErrNoPresets", err)
+	}
+	if _, err := ParsePresets([]byte("presets:\n  - templates: [Go]\n")); err == nil {
+		t.Error("ParsePresets(missing name) error = nil, want lint failure")
+	}
+}
+
+func TestImportPresets(t *testing.T) {
+	cleanup := setupPresetTest(t)
+	defer cleanup()
+
+	if err := CreatePreset("web", []string{"Node"}, nil); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+
+	incoming := []Preset{
+		{Key: "web", Name: "Web", Templates: []string{"Go"}},
+		{Key: "backend", Name: "Backend", Templates: []string{"Go", "go"}},
+	}
+	imported, skipped, err := ImportPresets(incoming, nil, ConflictSkip)
+	if err != nil {
+		t.Fatalf("ImportPresets() error = %v", err)
+	}
+	if len(imported) != 1 || imported[0].Key != "backend" {
+		t.Errorf("imported = %+v, want backend", imported)
+	}
+	if len(skipped) != 1 || skipped[0].Key != "web" {
+		t.Errorf("skipped = %+v, want web", skipped)
+	}
+
+	web, _, err := FindPreset("web")
+	if err != nil {
+		t.Fatalf("FindPreset() error = %v", err)
+	}
+	if !reflect.DeepEqual(web.Templates, []string{"Node"}) {
+		t.Errorf("existing preset templates = %v, want it left unchanged", web.Templates)
+	}
+	backend, _, err := FindPreset("backend")
+	if err != nil {
+		t.Fatalf("FindPreset() error = %v", err)
+	}
+	if !reflect.DeepEqual(backend.Templates, []string{"Go"}) || backend.Created == "" {
+		t.Errorf("imported preset = %+v, want deduped templates and a created time", backend)
+	}
+}
+
+func TestImportPresetsValidatesTemplates(t *testing.T) {
+	cleanup := setupPresetTest(t)
+	defer cleanup()
+
+	index := templates.BuildIndex([]templates.Template{{Name: "Go", Path: "/Go.gitignore"}})
+	_, _, err := ImportPresets([]Preset{{Key: "web", Name: "Web", Templates: []string{"Nope"}}}, &index, ConflictSkip)
+	if !errors.Is(err, ErrTemplateNotFound) || !strings.Contains(err.Error(), "web") {
+		t.Fatalf("ImportPresets() error = %v, want ErrTemplateNotFound naming the preset", err)
+	}
+	list, err := ListPresets()
+	if err != nil {
+		t.Fatalf("ListPresets() error = %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("presets after failed import = %+v, want none saved", list)
+	}
+}
+
+func TestImportPresetsConflict(t *testing.T) {
+	tests := []struct {
+		name     string
+		conflict ImportConflict
+		wantKeys []string
+		wantWeb  []string
+	}{
+		{name: "skip", conflict: ConflictSkip, wantKeys: []string{"web", "web-2"}, wantWeb: []string{"Node"}},
+		{name: "overwrite", conflict: ConflictOverwrite, wantKeys: []string{"web", "web-2"}, wantWeb: []string{"Go"}},
+		{name: "rename", conflict: ConflictRename, wantKeys: []string{"web", "web-2", "web-3"}, wantWeb: []string{"Node"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupPresetTest(t)
+			defer cleanup()
+
+			if err := SavePresets(PresetStore{Presets: []Preset{
+				{Key: "web", Name: "Web", Templates: []string{"Node"}},
+				{Key: "web-2", Name: "Web 2", Templates: []string{"Python"}},
+			}}); err != nil {
+				t.Fatalf("SavePresets() error = %v", err)
+			}
+			if _, _, err := ImportPresets([]Preset{{Key: "web", Name: "Web", Templates: []string{"Go"}}}, nil, tt.conflict); err != nil {
+				t.Fatalf("ImportPresets() error = %v", err)
+			}
+
+			list, err := ListPresets()
+			if err != nil {
+				t.Fatalf("ListPresets() error = %v", err)
+			}
+			var keys []string
+			for _, preset := range list {
+				keys = append(keys, preset.Key)
+			}
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("keys = %v, want %v", keys, tt.wantKeys)
+			}
+			if !reflect.DeepEqual(list[0].Templates, tt.wantWeb) {
+				t.Errorf("web templates = %v, want %v", list[0].Templates, tt.wantWeb)
+			}
+		})
+	}
+}
+
+func TestParsePresetsListsEveryIssue(t *testing.T) {
+	_, err := ParsePresets([]byte("presets:\n  - templates: [Go]\n  - name: \"\"\n    templates: [Node]\n"))
+	if err == nil || !strings.Contains(err.Error(), `missing required field "name"`) || !strings.Contains(err.Error(), `"name" must be a non-empty string`) {
+		t.Errorf("ParsePresets() error = %v, want both nameless presets listed", err)
+	}
+}
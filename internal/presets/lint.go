@@ -0,0 +1,192 @@
+package presets
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.seanlatimer.dev/ignr/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// LintIssue is a problem found in the presets file. Line is 0 when it cannot be determined.
+type LintIssue struct {
+	Line    int
+	Message string
+}
+
+func (i LintIssue) String() string {
+	if i.Line == 0 {
+		return i.Message
+	}
+	return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+}
+
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+var presetFields = map[string]bool{
+	"key":         true,
+	"name":        true,
+	"description": true,
+	"templates":   true,
+	"created":     true,
+	"updated":     true,
+}
+
+// LintPresetsFile checks the presets file and returns its path with any issues found.
+func LintPresetsFile() (string, []LintIssue, error) {
+	path, err := config.GetPresetsPath()
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("read presets: %w", err)
+	}
+	return path, LintPresets(data), nil
+}
+
+// LintPresets checks presets YAML for structural problems that LoadPresets would
+// either reject with a single parse error or silently accept: a name is required,
+// templates must be a list of strings, keys must be unique, and timestamps must be RFC3339.
+func LintPresets(data []byte) []LintIssue {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []LintIssue{yamlIssue(err)}
+	}
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		return nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return []LintIssue{{Line: root.Line, Message: "top level must be a mapping with a presets list"}}
+	}
+
+	var issues []LintIssue
+	var presetList *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if key.Value != "presets" {
+			issues = append(issues, LintIssue{Line: key.Line, Message: fmt.Sprintf("unknown top-level field %q", key.Value)})
+			continue
+		}
+		presetList = value
+	}
+	if presetList == nil {
+		return append(issues, LintIssue{Line: root.Line, Message: `missing "presets" list`})
+	}
+	if presetList.Tag == "!!null" {
+		return issues
+	}
+	if presetList.Kind != yaml.SequenceNode {
+		return append(issues, LintIssue{Line: presetList.Line, Message: `"presets" must be a list`})
+	}
+
+	seenKeys := make(map[string]int)
+	for i, node := range presetList.Content {
+		issues = append(issues, lintPreset(i+1, node, seenKeys)...)
+	}
+	return issues
+}
+
+func lintPreset(n int, node *yaml.Node, seenKeys map[string]int) []LintIssue {
+	if node.Kind != yaml.MappingNode {
+		return []LintIssue{{Line: node.Line, Message: fmt.Sprintf("preset %d must be a mapping", n)}}
+	}
+
+	var issues []LintIssue
+	report := func(line int, format string, args ...any) {
+		issues = append(issues, LintIssue{Line: line, Message: fmt.Sprintf("preset %d: ", n) + fmt.Sprintf(format, args...)})
+	}
+
+	fields := make(map[string]*yaml.Node)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if !presetFields[key.Value] {
+			report(key.Line, "unknown field %q", key.Value)
+			continue
+		}
+		if _, ok := fields[key.Value]; ok {
+			report(key.Line, "duplicate field %q", key.Value)
+			continue
+		}
+		fields[key.Value] = value
+	}
+
+	name := ""
+	if value, ok := fields["name"]; !ok {
+		report(node.Line, `missing required field "name"`)
+	} else if !isStringScalar(value) || strings.TrimSpace(value.Value) == "" {
+		report(value.Line, `"name" must be a non-empty string`)
+	} else {
+		name = value.Value
+	}
+
+	key := SluggifyName(name)
+	keyLine := node.Line
+	if value, ok := fields["key"]; ok {
+		if !isStringScalar(value) {
+			report(value.Line, `"key" must be a string`)
+		} else if strings.TrimSpace(value.Value) != "" {
+			key, keyLine = value.Value, value.Line
+		}
+	}
+	if key != "" {
+		folded := strings.ToLower(key)
+		if first, ok := seenKeys[folded]; ok {
+			report(keyLine, "key %q is already used by preset %d", key, first)
+		} else {
+			seenKeys[folded] = n
+		}
+	}
+
+	if value, ok := fields["description"]; ok && !isStringScalar(value) {
+		report(value.Line, `"description" must be a string`)
+	}
+
+	if value, ok := fields["templates"]; !ok {
+		report(node.Line, `missing required field "templates"`)
+	} else if value.Kind != yaml.SequenceNode {
+		report(value.Line, `"templates" must be a list of template names`)
+	} else {
+		for _, item := range value.Content {
+			if !isStringScalar(item) || strings.TrimSpace(item.Value) == "" {
+				report(item.Line, "template entries must be non-empty strings")
+			}
+		}
+	}
+
+	for _, field := range []string{"created", "updated"} {
+		value, ok := fields[field]
+		if !ok || value.Tag == "!!null" || value.Value == "" {
+			continue
+		}
+		if value.Kind != yaml.ScalarNode {
+			report(value.Line, "%q must be an RFC3339 timestamp", field)
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, value.Value); err != nil {
+			report(value.Line, "%q is not an RFC3339 timestamp: %q", field, value.Value)
+		}
+	}
+
+	return issues
+}
+
+// isStringScalar reports whether node decodes cleanly into a Go string.
+func isStringScalar(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag != "!!null"
+}
+
+func yamlIssue(err error) LintIssue {
+	msg := strings.TrimSpace(err.Error())
+	if m := yamlErrorLine.FindStringSubmatch(msg); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return LintIssue{Line: line, Message: m[2]}
+	}
+	return LintIssue{Message: strings.TrimPrefix(msg, "yaml: ")}
+}
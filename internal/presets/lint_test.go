@@ -0,0 +1,119 @@
+package presets
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestLintPresets(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []LintIssue
+	}{
+		{
+			name: "valid file",
+			data: "presets:\n" +
+				"  - key: web\n" +
+				"    name: Web\n" +
+				"    templates: [Go, Node]\n" +
+				"    created: \"2024-01-01T00:00:00Z\"\n" +
+				"    updated: \"2024-01-02T00:00:00Z\"\n",
+		},
+		{
+			name: "empty file",
+			data: "",
+		},
+		{
+			name: "empty presets list",
+			data: "presets: []\n",
+		},
+		{
+			name: "syntax error",
+			data: "presets:\n  - name: Web\n    templates: [Go\n",
+			want: []LintIssue{{Line: 2, Message: "did not find expected ',' or ']'"}},
+		},
+		{
+			name: "missing name",
+			data: "presets:\n  - templates: [Go]\n",
+			want: []LintIssue{{Line: 2, Message: `preset 1: missing required field "name"`}},
+		},
+		{
+			name: "templates not a list",
+			data: "presets:\n  - name: Web\n    templates: Go\n",
+			want: []LintIssue{{Line: 3, Message: `preset 1: "templates" must be a list of template names`}},
+		},
+		{
+			name: "template entry not a string",
+			data: "presets:\n  - name: Web\n    templates:\n      - Go\n      - [Node]\n      -\n",
+			want: []LintIssue{
+				{Line: 5, Message: "preset 1: template entries must be non-empty strings"},
+				{Line: 6, Message: "preset 1: template entries must be non-empty strings"},
+			},
+		},
+		{
+			name: "bad timestamp",
+			data: "presets:\n  - name: Web\n    templates: [Go]\n    created: yesterday\n",
+			want: []LintIssue{{Line: 4, Message: `preset 1: "created" is not an RFC3339 timestamp: "yesterday"`}},
+		},
+		{
+			name: "duplicate derived key",
+			data: "presets:\n  - name: My Web\n    templates: [Go]\n  - key: MY-WEB\n    name: Other\n    templates: [Node]\n",
+			want: []LintIssue{{Line: 4, Message: `preset 2: key "MY-WEB" is already used by preset 1`}},
+		},
+		{
+			name: "unknown fields",
+			data: "version: 2\npresets:\n  - name: Web\n    template: [Go]\n    templates: [Go]\n",
+			want: []LintIssue{
+				{Line: 1, Message: `unknown top-level field "version"`},
+				{Line: 4, Message: `preset 1: unknown field "template"`},
+			},
+		},
+		{
+			name: "preset not a mapping",
+			data: "presets:\n  - Web\n",
+			want: []LintIssue{{Line: 2, Message: "preset 1 must be a mapping"}},
+		},
+		{
+			name: "presets not a list",
+			data: "presets:\n  name: Web\n",
+			want: []LintIssue{{Line: 2, Message: `"presets" must be a list`}},
+		},
+		{
+			name: "top level not a mapping",
+			data: "- name: Web\n",
+			want: []LintIssue{{Line: 1, Message: "top level must be a mapping with a presets list"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LintPresets([]byte(tt.data))
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LintPresets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintPresetsMatchesSavedFile(t *testing.T) {
+	cleanup := setupPresetTest(t)
+	defer cleanup()
+
+	if err := CreatePreset("Web", []string{"Go", "Node"}, nil); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+
+	path, issues, err := LintPresetsFile()
+	if err != nil {
+		t.Fatalf("LintPresetsFile() error = %v", err)
+	}
+	if len(issues) != 0 {
+		data, _ := os.ReadFile(path)
+		t.Errorf("LintPresetsFile() issues = %v for saved file:\n%s", issues, data)
+	}
+}
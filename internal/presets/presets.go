@@ -2,21 +2,34 @@
 package presets
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/templates"
 	"gopkg.in/yaml.v3"
 )
 
+// ErrPresetExists is returned when a new preset's key is already taken.
+// Keys are the unique identifier; display names may repeat.
+var ErrPresetExists = errors.New("preset key already exists")
+
+// ErrTemplateNotFound is returned when a preset references templates missing from the index.
+var ErrTemplateNotFound = errors.New("template not found")
+
 type Preset struct {
-	Key       string   `yaml:"key,omitempty"`
-	Name      string   `yaml:"name"`
-	Templates []string `yaml:"templates"`
-	Created   string   `yaml:"created"`
-	Updated   string   `yaml:"updated"`
+	Key  string `yaml:"key,omitempty"`
+	Name string `yaml:"name"`
+	// Description is an optional note on what the preset is for.
+	Description string   `yaml:"description,omitempty"`
+	Templates   []string `yaml:"templates"`
+	Created     string   `yaml:"created"`
+	Updated     string   `yaml:"updated"`
 }
 
 type PresetStore struct {
@@ -61,29 +74,44 @@ func SavePresets(store PresetStore) error {
 	}
 
 	if err := os.WriteFile(path, data, 0o644); err != nil {
-		return fmt.Errorf("write presets: %w", err)
+		return config.WrapWriteError(filepath.Dir(path), fmt.Errorf("write presets: %w", err))
 	}
 	return nil
 }
 
+// MarshalPresets encodes list as a standalone presets file.
+func MarshalPresets(list []Preset) ([]byte, error) {
+	data, err := yaml.Marshal(PresetStore{Presets: list})
+	if err != nil {
+		return nil, fmt.Errorf("marshal presets: %w", err)
+	}
+	return data, nil
+}
+
 func FindPreset(name string) (Preset, bool, error) {
 	store, err := LoadPresets()
 	if err != nil {
 		return Preset{}, false, err
 	}
-	targetKey := SluggifyName(name)
-	for _, preset := range store.Presets {
-		if strings.EqualFold(preset.Key, name) || strings.EqualFold(preset.Key, targetKey) {
-			return preset, true, nil
-		}
-		if strings.EqualFold(preset.Name, name) {
-			return preset, true, nil
-		}
+	index, ok := findPresetIndex(store, name)
+	if !ok {
+		return Preset{}, false, nil
 	}
-	return Preset{}, false, nil
+	return store.Presets[index], true, nil
+}
+
+// CreatePreset saves a new preset. When index is non-nil, every template name
+// must exist in it.
+func CreatePreset(name string, templateNames []string, index *templates.Index) error {
+	return CreatePresetWithDescription(name, "", templateNames, index)
 }
 
-func CreatePreset(name string, templates []string) error {
+// CreatePresetWithDescription is CreatePreset that also sets the description, in the same
+// write to the presets file.
+func CreatePresetWithDescription(name, description string, templateNames []string, index *templates.Index) error {
+	if err := ValidateTemplates(templateNames, index); err != nil {
+		return err
+	}
 	store, err := LoadPresets()
 	if err != nil {
 		return err
@@ -91,36 +119,141 @@ func CreatePreset(name string, templates []string) error {
 	key := SluggifyName(name)
 	for _, preset := range store.Presets {
 		if strings.EqualFold(preset.Key, key) {
-			return fmt.Errorf("preset key already exists: %s", key)
+			return fmt.Errorf("%w: %s", ErrPresetExists, key)
 		}
 	}
 
+	templateNames, _ = DedupeTemplates(templateNames)
 	now := time.Now().UTC().Format(time.RFC3339)
 	store.Presets = append(store.Presets, Preset{
-		Key:       key,
-		Name:      name,
-		Templates: templates,
-		Created:   now,
-		Updated:   now,
+		Key:         key,
+		Name:        name,
+		Description: strings.TrimSpace(description),
+		Templates:   templateNames,
+		Created:     now,
+		Updated:     now,
 	})
 	return SavePresets(store)
 }
 
-func EditPreset(name string, templates []string) error {
+// EditPreset replaces a preset's templates. When index is non-nil, every
+// template name must exist in it.
+func EditPreset(name string, templateNames []string, index *templates.Index) error {
+	if err := ValidateTemplates(templateNames, index); err != nil {
+		return err
+	}
 	store, err := LoadPresets()
 	if err != nil {
 		return err
 	}
 
-	index, ok := findPresetIndex(store, name)
+	i, ok := findPresetIndex(store, name)
+	if !ok {
+		return fmt.Errorf("preset not found: %s", name)
+	}
+	store.Presets[i].Templates, _ = DedupeTemplates(templateNames)
+	store.Presets[i].Updated = time.Now().UTC().Format(time.RFC3339)
+	return SavePresets(store)
+}
+
+// ApplyTemplateChanges returns current with the names in remove taken out and the names in add
+// appended, deduplicated and otherwise in order. Names match case-insensitively and with or
+// without the .gitignore suffix; entries of remove that are not in current are returned as notFound.
+func ApplyTemplateChanges(current, add, remove []string) (templateNames, notFound []string) {
+	templateNames = slices.Clone(current)
+	for _, target := range remove {
+		before := len(templateNames)
+		templateNames = slices.DeleteFunc(templateNames, func(existing string) bool {
+			return normalizeTemplateName(existing) == normalizeTemplateName(target)
+		})
+		if len(templateNames) == before {
+			notFound = append(notFound, target)
+		}
+	}
+	templateNames, _ = DedupeTemplates(append(templateNames, add...))
+	return templateNames, notFound
+}
+
+// SetDescription replaces a preset's description; an empty description removes it.
+func SetDescription(name, description string) error {
+	store, err := LoadPresets()
+	if err != nil {
+		return err
+	}
+
+	i, ok := findPresetIndex(store, name)
 	if !ok {
 		return fmt.Errorf("preset not found: %s", name)
 	}
-	store.Presets[index].Templates = templates
-	store.Presets[index].Updated = time.Now().UTC().Format(time.RFC3339)
+	store.Presets[i].Description = strings.TrimSpace(description)
+	store.Presets[i].Updated = time.Now().UTC().Format(time.RFC3339)
 	return SavePresets(store)
 }
 
+// ValidatePresetName rejects names that are blank or have no ASCII letter or digit. SluggifyName
+// falls back to "preset" for those, so they would not produce a key of their own.
+func ValidatePresetName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("preset name is empty")
+	}
+	if !strings.ContainsFunc(strings.ToLower(name), func(r rune) bool {
+		return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+	}) {
+		return fmt.Errorf("invalid preset name %q: needs at least one letter or digit", name)
+	}
+	return nil
+}
+
+// RenamePreset gives a preset a new display name and the key derived from it, keeping its
+// templates and Created time. It fails if another preset already has the new key.
+func RenamePreset(name, newName string) (Preset, error) {
+	newName = strings.TrimSpace(newName)
+	if err := ValidatePresetName(newName); err != nil {
+		return Preset{}, err
+	}
+	key := SluggifyName(newName)
+	store, err := LoadPresets()
+	if err != nil {
+		return Preset{}, err
+	}
+
+	i, ok := findPresetIndex(store, name)
+	if !ok {
+		return Preset{}, fmt.Errorf("preset not found: %s", name)
+	}
+	for j, preset := range store.Presets {
+		if j != i && strings.EqualFold(preset.Key, key) {
+			return Preset{}, fmt.Errorf("%w: %s", ErrPresetExists, key)
+		}
+	}
+	store.Presets[i].Name = newName
+	store.Presets[i].Key = key
+	store.Presets[i].Updated = time.Now().UTC().Format(time.RFC3339)
+	return store.Presets[i], SavePresets(store)
+}
+
+// CopyPreset saves a new preset named newName with the templates of an existing preset.
+// The copy gets its own key and timestamps; it fails if the new key is already taken.
+func CopyPreset(name, newName string) (Preset, error) {
+	source, ok, err := FindPreset(name)
+	if err != nil {
+		return Preset{}, err
+	}
+	if !ok {
+		return Preset{}, fmt.Errorf("preset not found: %s", name)
+	}
+	newName = strings.TrimSpace(newName)
+	if err := ValidatePresetName(newName); err != nil {
+		return Preset{}, err
+	}
+	if err := CreatePresetWithDescription(newName, source.Description, slices.Clone(source.Templates), nil); err != nil {
+		return Preset{}, err
+	}
+	created, _, err := FindPreset(SluggifyName(newName))
+	return created, err
+}
+
 func DeletePreset(name string) error {
 	store, err := LoadPresets()
 	if err != nil {
@@ -144,12 +277,96 @@ func ListPresets() ([]Preset, error) {
 	return store.Presets, nil
 }
 
+// ValidateTemplates checks that every name exists in index, reporting all
+// missing names at once. A nil index skips validation.
+func ValidateTemplates(templateNames []string, index *templates.Index) error {
+	if index == nil {
+		return nil
+	}
+	var missing []string
+	for _, name := range templateNames {
+		if _, ok := templates.FindTemplate(*index, name); !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrTemplateNotFound, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// PresetsUsingTemplate returns the presets that list templateName, matched
+// case-insensitively and ignoring a ".gitignore" suffix.
+func PresetsUsingTemplate(templateName string) ([]Preset, error) {
+	store, err := LoadPresets()
+	if err != nil {
+		return nil, err
+	}
+	target := normalizeTemplateName(templateName)
+	var using []Preset
+	for _, preset := range store.Presets {
+		for _, name := range preset.Templates {
+			if normalizeTemplateName(name) == target {
+				using = append(using, preset)
+				break
+			}
+		}
+	}
+	return using, nil
+}
+
+// DedupeTemplates drops repeated template names, keeping the first spelling of each.
+// Names match case-insensitively and ignoring a ".gitignore" suffix. The dropped
+// entries are returned so callers can warn about them.
+func DedupeTemplates(templateNames []string) (unique, removed []string) {
+	seen := make(map[string]struct{}, len(templateNames))
+	unique = make([]string, 0, len(templateNames))
+	for _, name := range templateNames {
+		key := normalizeTemplateName(name)
+		if _, ok := seen[key]; ok {
+			removed = append(removed, name)
+			continue
+		}
+		seen[key] = struct{}{}
+		unique = append(unique, name)
+	}
+	return unique, removed
+}
+
+func normalizeTemplateName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.TrimSuffix(name, ".gitignore")
+}
+
+// DuplicateNames returns existing presets whose display name matches name
+// (case-insensitively) but whose key differs from the key name would get.
+// Such presets are allowed but make lookups by name ambiguous.
+func DuplicateNames(name string) ([]Preset, error) {
+	store, err := LoadPresets()
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	key := SluggifyName(name)
+	var duplicates []Preset
+	for _, preset := range store.Presets {
+		if strings.EqualFold(strings.TrimSpace(preset.Name), name) && !strings.EqualFold(preset.Key, key) {
+			duplicates = append(duplicates, preset)
+		}
+	}
+	return duplicates, nil
+}
+
+// findPresetIndex resolves name to a preset, preferring key matches over
+// display-name matches so a key always selects the same preset.
 func findPresetIndex(store PresetStore, name string) (int, bool) {
 	targetKey := SluggifyName(name)
 	for i, preset := range store.Presets {
 		if strings.EqualFold(preset.Key, name) || strings.EqualFold(preset.Key, targetKey) {
 			return i, true
 		}
+	}
+	for i, preset := range store.Presets {
 		if strings.EqualFold(preset.Name, name) {
 			return i, true
 		}
@@ -48,7 +48,7 @@ func TestPresetLifecycleIntegration(t *testing.T) {
 	presetName := "My Project"
 	templates := []string{"Go", "Python", "Node"}
 	
-	err := CreatePreset(presetName, templates)
+	err := CreatePreset(presetName, templates, nil)
 	if err != nil {
 		t.Fatalf("CreatePreset() error = %v", err)
 	}
@@ -84,7 +84,7 @@ func TestPresetLifecycleIntegration(t *testing.T) {
 	newTemplates := []string{"Go", "Python", "Node", "Rust"}
 	originalCreated := preset.Created
 	
-	err = EditPreset(presetName, newTemplates)
+	err = EditPreset(presetName, newTemplates, nil)
 	if err != nil {
 		t.Fatalf("EditPreset() error = %v", err)
 	}
@@ -134,7 +134,7 @@ func TestPresetPersistenceIntegration(t *testing.T) {
 	presetName := "Test Preset"
 	templates := []string{"Go", "Python"}
 	
-	err := CreatePreset(presetName, templates)
+	err := CreatePreset(presetName, templates, nil)
 	if err != nil {
 		t.Fatalf("CreatePreset() error = %v", err)
 	}
@@ -174,7 +174,7 @@ func TestPresetYAMLFormatIntegration(t *testing.T) {
 	presetName := "YAML Test"
 	templates := []string{"Go", "Python", "Node"}
 	
-	err := CreatePreset(presetName, templates)
+	err := CreatePreset(presetName, templates, nil)
 	if err != nil {
 		t.Fatalf("CreatePreset() error = %v", err)
 	}
@@ -226,7 +226,7 @@ func TestMultiplePresetsIntegration(t *testing.T) {
 	}
 	
 	for _, p := range presets {
-		err := CreatePreset(p.name, p.templates)
+		err := CreatePreset(p.name, p.templates, nil)
 		if err != nil {
 			t.Fatalf("CreatePreset(%q) error = %v", p.name, err)
 		}
@@ -278,7 +278,7 @@ func TestPresetKeyGenerationIntegration(t *testing.T) {
 	}
 	
 	for _, tc := range testCases {
-		err := CreatePreset(tc.name, []string{"Go"})
+		err := CreatePreset(tc.name, []string{"Go"}, nil)
 		if err != nil {
 			t.Fatalf("CreatePreset(%q) error = %v", tc.name, err)
 		}
@@ -336,7 +336,7 @@ func TestPresetTimestampsIntegration(t *testing.T) {
 	
 	// Create a preset
 	presetName := "Timestamp Test"
-	err := CreatePreset(presetName, []string{"Go"})
+	err := CreatePreset(presetName, []string{"Go"}, nil)
 	if err != nil {
 		t.Fatalf("CreatePreset() error = %v", err)
 	}
@@ -374,7 +374,7 @@ func TestPresetTimestampsIntegration(t *testing.T) {
 	time.Sleep(time.Second)
 	
 	// Edit the preset
-	err = EditPreset(presetName, []string{"Go", "Python"})
+	err = EditPreset(presetName, []string{"Go", "Python"}, nil)
 	if err != nil {
 		t.Fatalf("EditPreset() error = %v", err)
 	}
@@ -1,12 +1,18 @@
 package presets
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/adrg/xdg"
+	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/templates"
 )
 
 // setupPresetTest sets up a temporary config directory for testing presets
@@ -161,7 +167,7 @@ func TestCreatePreset(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := CreatePreset(tt.presetName, tt.templates)
+			err := CreatePreset(tt.presetName, tt.templates, nil)
 			
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreatePreset() error = %v, wantErr %v", err, tt.wantErr)
@@ -196,17 +202,33 @@ func TestCreatePreset(t *testing.T) {
 	}
 }
 
+func TestCreatePresetWithDescription(t *testing.T) {
+	cleanup := setupPresetTest(t)
+	defer cleanup()
+
+	if err := CreatePresetWithDescription("Web", "  Frontend and API ", []string{"Node"}, nil); err != nil {
+		t.Fatalf("CreatePresetWithDescription() error = %v", err)
+	}
+	preset, found, err := FindPreset("web")
+	if err != nil || !found {
+		t.Fatalf("FindPreset() = %v, %v; want the new preset", found, err)
+	}
+	if preset.Description != "Frontend and API" || preset.Created != preset.Updated {
+		t.Errorf("preset = %+v, want the trimmed description set when it was created", preset)
+	}
+}
+
 func TestCreatePresetDuplicateKey(t *testing.T) {
 	cleanup := setupPresetTest(t)
 	defer cleanup()
 
 	// Create first preset
-	if err := CreatePreset("My Project", []string{"Go"}); err != nil {
+	if err := CreatePreset("My Project", []string{"Go"}, nil); err != nil {
 		t.Fatalf("CreatePreset() error = %v", err)
 	}
 
 	// Try to create duplicate
-	err := CreatePreset("My Project", []string{"Python"})
+	err := CreatePreset("My Project", []string{"Python"}, nil)
 	if err == nil {
 		t.Error("CreatePreset() expected error for duplicate key, got nil")
 		return
@@ -223,13 +245,13 @@ func TestEditPreset(t *testing.T) {
 
 	// Create a preset first
 	presetName := "My Project"
-	if err := CreatePreset(presetName, []string{"Go"}); err != nil {
+	if err := CreatePreset(presetName, []string{"Go"}, nil); err != nil {
 		t.Fatalf("CreatePreset() error = %v", err)
 	}
 
 	// Edit the preset
 	newTemplates := []string{"Go", "Python", "Node"}
-	err := EditPreset(presetName, newTemplates)
+	err := EditPreset(presetName, newTemplates, nil)
 	if err != nil {
 		t.Fatalf("EditPreset() error = %v", err)
 	}
@@ -251,7 +273,7 @@ func TestEditPresetNotFound(t *testing.T) {
 	cleanup := setupPresetTest(t)
 	defer cleanup()
 
-	err := EditPreset("Nonexistent", []string{"Go"})
+	err := EditPreset("Nonexistent", []string{"Go"}, nil)
 	if err == nil {
 		t.Error("EditPreset() expected error for nonexistent preset, got nil")
 		return
@@ -262,13 +284,168 @@ func TestEditPresetNotFound(t *testing.T) {
 	}
 }
 
+func TestPresetDescriptionOptional(t *testing.T) {
+	cleanup := setupPresetTest(t)
+	defer cleanup()
+
+	path, err := config.GetPresetsPath()
+	if err != nil {
+		t.Fatalf("GetPresetsPath() error = %v", err)
+	}
+	legacy := "presets:\n  - key: web\n    name: Web\n    templates: [Node]\n"
+	if err := os.WriteFile(path, []byte(legacy), 0o644); err != nil {
+		t.Fatalf("failed to write presets: %v", err)
+	}
+	if issues := LintPresets([]byte(legacy)); len(issues) != 0 {
+		t.Errorf("LintPresets() on a preset without description = %v, want none", issues)
+	}
+
+	if err := SetDescription("web", "Frontend"); err != nil {
+		t.Fatalf("SetDescription() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read presets: %v", err)
+	}
+	if !strings.Contains(string(data), "description: Frontend") {
+		t.Errorf("presets.yaml = %q, want the description saved", data)
+	}
+
+	if err := SetDescription("web", ""); err != nil {
+		t.Fatalf("SetDescription() error = %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "description") {
+		t.Errorf("presets.yaml = %q, want an empty description omitted", data)
+	}
+}
+
+func TestRenamePreset(t *testing.T) {
+	cleanup := setupPresetTest(t)
+	defer cleanup()
+
+	if err := SavePresets(PresetStore{Presets: []Preset{
+		{Key: "web", Name: "Web", Templates: []string{"Node", "Go"}, Created: "2024-01-01T00:00:00Z", Updated: "2024-01-01T00:00:00Z"},
+		{Key: "backend", Name: "Backend", Templates: []string{"Go"}},
+	}}); err != nil {
+		t.Fatalf("SavePresets() error = %v", err)
+	}
+
+	renamed, err := RenamePreset("web", "Web Dev")
+	if err != nil {
+		t.Fatalf("RenamePreset() error = %v", err)
+	}
+	if renamed.Key != "web-dev" || renamed.Name != "Web Dev" {
+		t.Errorf("renamed preset = %+v, want key web-dev and name Web Dev", renamed)
+	}
+	if renamed.Created != "2024-01-01T00:00:00Z" || renamed.Updated == renamed.Created {
+		t.Errorf("renamed times = %s/%s, want Created kept and Updated bumped", renamed.Created, renamed.Updated)
+	}
+	if !reflect.DeepEqual(renamed.Templates, []string{"Node", "Go"}) {
+		t.Errorf("renamed templates = %v, want [Node Go]", renamed.Templates)
+	}
+	if _, ok, _ := FindPreset("web"); ok {
+		t.Error("FindPreset(web) found a preset after rename")
+	}
+
+	if _, err := RenamePreset("web-dev", "backend"); !errors.Is(err, ErrPresetExists) {
+		t.Errorf("RenamePreset() onto an existing key error = %v, want ErrPresetExists", err)
+	}
+	if _, err := RenamePreset("missing", "Other"); err == nil {
+		t.Error("RenamePreset() of a missing preset error = nil")
+	}
+	if _, err := RenamePreset("web-dev", "WEB DEV"); err != nil {
+		t.Errorf("RenamePreset() to its own key error = %v, want allowed", err)
+	}
+	for _, name := range []string{"", "   ", "!!!", " -_- "} {
+		if _, err := RenamePreset("web-dev", name); err == nil {
+			t.Errorf("RenamePreset() to %q error = nil, want the name rejected", name)
+		}
+	}
+	if kept, ok, _ := FindPreset("web-dev"); !ok || kept.Name != "WEB DEV" {
+		t.Errorf("preset after rejected renames = %+v, %v; want it unchanged", kept, ok)
+	}
+}
+
+func TestCopyPreset(t *testing.T) {
+	cleanup := setupPresetTest(t)
+	defer cleanup()
+
+	if err := SavePresets(PresetStore{Presets: []Preset{
+		{Key: "web", Name: "Web", Description: "Frontend and API", Templates: []string{"Node", "Go"}, Created: "2024-01-01T00:00:00Z", Updated: "2024-01-01T00:00:00Z"},
+	}}); err != nil {
+		t.Fatalf("SavePresets() error = %v", err)
+	}
+
+	copied, err := CopyPreset("web", "Web Staging")
+	if err != nil {
+		t.Fatalf("CopyPreset() error = %v", err)
+	}
+	if copied.Key != "web-staging" || !reflect.DeepEqual(copied.Templates, []string{"Node", "Go"}) {
+		t.Errorf("copy = %+v, want key web-staging with [Node Go]", copied)
+	}
+	if copied.Description != "Frontend and API" {
+		t.Errorf("copy description = %q, want the source's description", copied.Description)
+	}
+	if copied.Created == "2024-01-01T00:00:00Z" || copied.Created == "" {
+		t.Errorf("copy created = %q, want a fresh timestamp", copied.Created)
+	}
+	source, _, _ := FindPreset("web")
+	if source.Updated != "2024-01-01T00:00:00Z" {
+		t.Errorf("source updated = %q, want it untouched", source.Updated)
+	}
+
+	if _, err := CopyPreset("web", "web staging"); !errors.Is(err, ErrPresetExists) {
+		t.Errorf("CopyPreset() onto an existing key error = %v, want ErrPresetExists", err)
+	}
+	if _, err := CopyPreset("missing", "Other"); err == nil || !strings.Contains(err.Error(), "preset not found") {
+		t.Errorf("CopyPreset() of a missing preset error = %v, want preset not found", err)
+	}
+	for _, name := range []string{"  ", "!!!"} {
+		if _, err := CopyPreset("web", name); err == nil || !strings.Contains(err.Error(), "preset name") {
+			t.Errorf("CopyPreset() to %q error = %v, want the name rejected", name, err)
+		}
+	}
+	if _, ok, _ := FindPreset("preset"); ok {
+		t.Error("a rejected copy was saved under the fallback key preset")
+	}
+}
+
+func TestApplyTemplateChanges(t *testing.T) {
+	current := []string{"Node", "Go"}
+	tests := []struct {
+		name         string
+		add          []string
+		remove       []string
+		want         []string
+		wantNotFound []string
+	}{
+		{name: "add", add: []string{"Python", "go"}, want: []string{"Node", "Go", "Python"}},
+		{name: "remove", remove: []string{"node.gitignore"}, want: []string{"Go"}},
+		{name: "add and remove", add: []string{"Python"}, remove: []string{"Go"}, want: []string{"Node", "Python"}},
+		{name: "remove missing is a no-op", remove: []string{"Rust"}, want: []string{"Node", "Go"}, wantNotFound: []string{"Rust"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, notFound := ApplyTemplateChanges(current, tt.add, tt.remove)
+			if !reflect.DeepEqual(got, tt.want) || !reflect.DeepEqual(notFound, tt.wantNotFound) {
+				t.Errorf("ApplyTemplateChanges() = %v, %v, want %v, %v", got, notFound, tt.want, tt.wantNotFound)
+			}
+		})
+	}
+	if !reflect.DeepEqual(current, []string{"Node", "Go"}) {
+		t.Errorf("current = %v, want it unchanged", current)
+	}
+}
+
 func TestDeletePreset(t *testing.T) {
 	cleanup := setupPresetTest(t)
 	defer cleanup()
 
 	// Create a preset first
 	presetName := "My Project"
-	if err := CreatePreset(presetName, []string{"Go"}); err != nil {
+	if err := CreatePreset(presetName, []string{"Go"}, nil); err != nil {
 		t.Fatalf("CreatePreset() error = %v", err)
 	}
 
@@ -311,7 +488,7 @@ func TestFindPreset(t *testing.T) {
 	presetKey := "my-project"
 	templates := []string{"Go", "Python"}
 	
-	if err := CreatePreset(presetName, templates); err != nil {
+	if err := CreatePreset(presetName, templates, nil); err != nil {
 		t.Fatalf("CreatePreset() error = %v", err)
 	}
 
@@ -387,10 +564,10 @@ func TestListPresets(t *testing.T) {
 	preset1 := "Project 1"
 	preset2 := "Project 2"
 	
-	if err := CreatePreset(preset1, []string{"Go"}); err != nil {
+	if err := CreatePreset(preset1, []string{"Go"}, nil); err != nil {
 		t.Fatalf("CreatePreset() error = %v", err)
 	}
-	if err := CreatePreset(preset2, []string{"Python"}); err != nil {
+	if err := CreatePreset(preset2, []string{"Python"}, nil); err != nil {
 		t.Fatalf("CreatePreset() error = %v", err)
 	}
 
@@ -409,7 +586,7 @@ func TestPresetTimestampGeneration(t *testing.T) {
 	defer cleanup()
 
 	presetName := "My Project"
-	if err := CreatePreset(presetName, []string{"Go"}); err != nil {
+	if err := CreatePreset(presetName, []string{"Go"}, nil); err != nil {
 		t.Fatalf("CreatePreset() error = %v", err)
 	}
 
@@ -435,3 +612,257 @@ func TestPresetTimestampGeneration(t *testing.T) {
 		t.Errorf("CreatePreset() Created timestamp format invalid: %v", err)
 	}
 }
+
+func TestSavePresetsReadOnlyDir(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("chmod-based read-only directories are not enforced on this platform/user")
+	}
+	cleanup := setupPresetTest(t)
+	defer cleanup()
+
+	home := t.TempDir()
+	t.Setenv(config.HomeEnv, home)
+	if err := os.WriteFile(filepath.Join(home, "presets.yaml"), []byte("presets: []\n"), 0o444); err != nil {
+		t.Fatalf("failed to seed presets file: %v", err)
+	}
+	if err := os.Chmod(home, 0o555); err != nil {
+		t.Fatalf("failed to make dir read-only: %v", err)
+	}
+	defer func() {
+		_ = os.Chmod(home, 0o755)
+	}()
+
+	err := SavePresets(PresetStore{Presets: []Preset{{Name: "Web", Templates: []string{"Node"}}}})
+	if !errors.Is(err, config.ErrNotWritable) {
+		t.Fatalf("SavePresets() error = %v, want config.ErrNotWritable", err)
+	}
+}
+
+func TestCreatePresetUniqueness(t *testing.T) {
+	tests := []struct {
+		name       string
+		existing   []Preset
+		create     string
+		wantErr    bool
+		wantDupKey []string
+	}{
+		{
+			name:     "names differing by case share a key",
+			existing: []Preset{{Key: "my-project", Name: "My Project"}},
+			create:   "my project",
+			wantErr:  true,
+		},
+		{
+			name:     "names differing by separators share a key",
+			existing: []Preset{{Key: "my-project", Name: "My Project"}},
+			create:   "My_Project",
+			wantErr:  true,
+		},
+		{
+			name:     "name matching an existing key collides",
+			existing: []Preset{{Key: "web", Name: "Frontend"}},
+			create:   "Web",
+			wantErr:  true,
+		},
+		{
+			name:       "duplicate display name with different key is allowed",
+			existing:   []Preset{{Key: "web-legacy", Name: "Web"}},
+			create:     "web",
+			wantDupKey: []string{"web-legacy"},
+		},
+		{
+			name:     "distinct names and keys",
+			existing: []Preset{{Key: "web", Name: "Web"}},
+			create:   "Backend",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupPresetTest(t)
+			defer cleanup()
+
+			if err := SavePresets(PresetStore{Presets: tt.existing}); err != nil {
+				t.Fatalf("SavePresets() error = %v", err)
+			}
+
+			duplicates, err := DuplicateNames(tt.create)
+			if err != nil {
+				t.Fatalf("DuplicateNames() error = %v", err)
+			}
+			gotKeys := make([]string, 0, len(duplicates))
+			for _, preset := range duplicates {
+				gotKeys = append(gotKeys, preset.Key)
+			}
+			if strings.Join(gotKeys, ",") != strings.Join(tt.wantDupKey, ",") {
+				t.Errorf("DuplicateNames(%q) keys = %v, want %v", tt.create, gotKeys, tt.wantDupKey)
+			}
+
+			err = CreatePreset(tt.create, []string{"Go"}, nil)
+			if tt.wantErr {
+				if !errors.Is(err, ErrPresetExists) {
+					t.Errorf("CreatePreset(%q) error = %v, want ErrPresetExists", tt.create, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("CreatePreset(%q) error = %v", tt.create, err)
+			}
+		})
+	}
+}
+
+func TestFindPresetPrefersKey(t *testing.T) {
+	cleanup := setupPresetTest(t)
+	defer cleanup()
+
+	store := PresetStore{Presets: []Preset{
+		{Key: "web-legacy", Name: "Web", Templates: []string{"Node"}},
+		{Key: "web", Name: "Web", Templates: []string{"Go"}},
+	}}
+	if err := SavePresets(store); err != nil {
+		t.Fatalf("SavePresets() error = %v", err)
+	}
+
+	preset, found, err := FindPreset("web")
+	if err != nil || !found {
+		t.Fatalf("FindPreset() = %v, %v", found, err)
+	}
+	if preset.Key != "web" {
+		t.Errorf("FindPreset(%q) key = %q, want %q", "web", preset.Key, "web")
+	}
+}
+
+func TestPresetTemplateValidation(t *testing.T) {
+	index := templates.BuildIndex([]templates.Template{{Name: "Go"}, {Name: "Python"}})
+
+	tests := []struct {
+		name      string
+		templates []string
+		index     *templates.Index
+		wantErr   string
+	}{
+		{name: "known templates", templates: []string{"Go", "python"}, index: &index},
+		{name: "gitignore suffix", templates: []string{"Go.gitignore"}, index: &index},
+		{name: "unknown template", templates: []string{"Go", "Rust"}, index: &index, wantErr: "Rust"},
+		{name: "all unknown reported", templates: []string{"Rust", "Java"}, index: &index, wantErr: "Rust, Java"},
+		{name: "nil index skips validation", templates: []string{"Rust"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupPresetTest(t)
+			defer cleanup()
+
+			err := CreatePreset("Project", tt.templates, tt.index)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CreatePreset() error = %v", err)
+				}
+				if err := EditPreset("Project", tt.templates, tt.index); err != nil {
+					t.Errorf("EditPreset() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrTemplateNotFound) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CreatePreset() error = %v, want ErrTemplateNotFound naming %q", err, tt.wantErr)
+			}
+			if list, _ := ListPresets(); len(list) != 0 {
+				t.Errorf("CreatePreset() saved %d presets despite invalid templates", len(list))
+			}
+
+			if err := CreatePreset("Project", []string{"Go"}, nil); err != nil {
+				t.Fatalf("CreatePreset() error = %v", err)
+			}
+			err = EditPreset("Project", tt.templates, tt.index)
+			if !errors.Is(err, ErrTemplateNotFound) {
+				t.Errorf("EditPreset() error = %v, want ErrTemplateNotFound", err)
+			}
+			preset, _, _ := FindPreset("Project")
+			if len(preset.Templates) != 1 || preset.Templates[0] != "Go" {
+				t.Errorf("EditPreset() changed templates to %v despite invalid names", preset.Templates)
+			}
+		})
+	}
+}
+
+func TestPresetsUsingTemplate(t *testing.T) {
+	cleanup := setupPresetTest(t)
+	defer cleanup()
+
+	store := PresetStore{Presets: []Preset{
+		{Key: "web", Name: "Web", Templates: []string{"Node", "Go"}},
+		{Key: "backend", Name: "Backend", Templates: []string{"go.gitignore"}},
+		{Key: "py", Name: "Py", Templates: []string{"Python"}},
+	}}
+	if err := SavePresets(store); err != nil {
+		t.Fatalf("SavePresets() error = %v", err)
+	}
+
+	tests := []struct {
+		template string
+		want     []string
+	}{
+		{template: "Go", want: []string{"web", "backend"}},
+		{template: "NODE.gitignore", want: []string{"web"}},
+		{template: "Rust", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			using, err := PresetsUsingTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("PresetsUsingTemplate() error = %v", err)
+			}
+			var keys []string
+			for _, preset := range using {
+				keys = append(keys, preset.Key)
+			}
+			if strings.Join(keys, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("PresetsUsingTemplate(%q) = %v, want %v", tt.template, keys, tt.want)
+			}
+		})
+	}
+}
+
+func TestPresetDuplicateTemplates(t *testing.T) {
+	cleanup := setupPresetTest(t)
+	defer cleanup()
+
+	if err := CreatePreset("Project", []string{"Go", "go", "Python", "Go.gitignore", "GO"}, nil); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+	preset, _, err := FindPreset("Project")
+	if err != nil {
+		t.Fatalf("FindPreset() error = %v", err)
+	}
+	if got := strings.Join(preset.Templates, ","); got != "Go,Python" {
+		t.Errorf("CreatePreset() stored templates %q, want %q", got, "Go,Python")
+	}
+
+	if err := EditPreset("Project", []string{"Node", "Go", "node"}, nil); err != nil {
+		t.Fatalf("EditPreset() error = %v", err)
+	}
+	preset, _, err = FindPreset("Project")
+	if err != nil {
+		t.Fatalf("FindPreset() error = %v", err)
+	}
+	if got := strings.Join(preset.Templates, ","); got != "Node,Go" {
+		t.Errorf("EditPreset() stored templates %q, want %q", got, "Node,Go")
+	}
+}
+
+func TestDedupeTemplates(t *testing.T) {
+	unique, removed := DedupeTemplates([]string{"Go", "go", "Go"})
+	if strings.Join(unique, ",") != "Go" {
+		t.Errorf("DedupeTemplates() unique = %v, want [Go]", unique)
+	}
+	if strings.Join(removed, ",") != "go,Go" {
+		t.Errorf("DedupeTemplates() removed = %v, want [go Go]", removed)
+	}
+
+	unique, removed = DedupeTemplates([]string{"Go", "Python"})
+	if len(unique) != 2 || len(removed) != 0 {
+		t.Errorf("DedupeTemplates() without duplicates = %v, %v", unique, removed)
+	}
+}
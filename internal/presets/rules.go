@@ -0,0 +1,116 @@
+package presets
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go.seanlatimer.dev/ignr/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// DetectionRuleFile is the layout of detection.yaml in the config directory.
+type DetectionRuleFile struct {
+	Rules []DetectionRule `yaml:"rules"`
+}
+
+// LoadDetectionRules reads the user's detection rules from detection.yaml. A missing file
+// has no rules. Patterns and template names are trimmed and blank entries dropped. Every
+// rule needs at least one pattern and one template; every problem is listed otherwise.
+func LoadDetectionRules() ([]DetectionRule, error) {
+	path, err := config.GetDetectionRulesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read detection rules: %w", err)
+	}
+
+	var file DetectionRuleFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse detection rules %s: %w", path, err)
+	}
+	if err := ValidateDetectionRules(file.Rules); err != nil {
+		return nil, fmt.Errorf("invalid detection rules %s: %w", path, err)
+	}
+	for i, rule := range file.Rules {
+		file.Rules[i] = DetectionRule{Patterns: trimNonBlank(rule.Patterns), Templates: trimNonBlank(rule.Templates)}
+	}
+	return file.Rules, nil
+}
+
+// ValidateDetectionRules checks that every rule has a non-empty pattern and template.
+func ValidateDetectionRules(rules []DetectionRule) error {
+	var problems []string
+	for i, rule := range rules {
+		if !hasNonBlank(rule.Patterns) {
+			problems = append(problems, fmt.Sprintf("rule %d: needs at least one pattern", i+1))
+		}
+		if !hasNonBlank(rule.Templates) {
+			problems = append(problems, fmt.Sprintf("rule %d: needs at least one template", i+1))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// DetectionRules returns the user's rules merged over the built-in ones; see MergeDetectionRules.
+func DetectionRules() ([]DetectionRule, error) {
+	user, err := LoadDetectionRules()
+	if err != nil {
+		return nil, err
+	}
+	return MergeDetectionRules(defaultDetectionRules(), user), nil
+}
+
+// MergeDetectionRules puts user rules ahead of builtin ones. A pattern that a user rule
+// lists is removed from the built-in rules, so the user's templates replace the built-in
+// ones for that marker; built-in rules left without patterns are dropped.
+func MergeDetectionRules(builtin, user []DetectionRule) []DetectionRule {
+	overridden := map[string]bool{}
+	for _, rule := range user {
+		for _, pattern := range rule.Patterns {
+			overridden[strings.ToLower(strings.TrimSpace(pattern))] = true
+		}
+	}
+
+	merged := append([]DetectionRule{}, user...)
+	for _, rule := range builtin {
+		patterns := make([]string, 0, len(rule.Patterns))
+		for _, pattern := range rule.Patterns {
+			if !overridden[strings.ToLower(pattern)] {
+				patterns = append(patterns, pattern)
+			}
+		}
+		if len(patterns) > 0 {
+			merged = append(merged, DetectionRule{Patterns: patterns, Templates: rule.Templates})
+		}
+	}
+	return merged
+}
+
+func hasNonBlank(values []string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// trimNonBlank returns values with surrounding whitespace trimmed and blank entries dropped.
+func trimNonBlank(values []string) []string {
+	trimmed := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			trimmed = append(trimmed, value)
+		}
+	}
+	return trimmed
+}
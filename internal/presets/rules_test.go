@@ -0,0 +1,116 @@
+package presets
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.seanlatimer.dev/ignr/internal/config"
+)
+
+func TestLoadDetectionRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []DetectionRule
+		wantErr string
+	}{
+		{name: "missing file"},
+		{
+			name:    "rules",
+			content: "rules:\n  - patterns: [WORKSPACE, \"*.bzl\"]\n    templates: [Bazel]\n",
+			want:    []DetectionRule{{Patterns: []string{"WORKSPACE", "*.bzl"}, Templates: []string{"Bazel"}}},
+		},
+		{
+			name:    "padded entries",
+			content: "rules:\n  - patterns: [\" WORKSPACE\", \"*.bzl \", \"  \"]\n    templates: [\" Bazel\"]\n",
+			want:    []DetectionRule{{Patterns: []string{"WORKSPACE", "*.bzl"}, Templates: []string{"Bazel"}}},
+		},
+		{
+			name:    "rule without templates",
+			content: "rules:\n  - patterns: [WORKSPACE]\n  - templates: [Bazel]\n",
+			wantErr: "rule 1: needs at least one template; rule 2: needs at least one pattern",
+		},
+		{name: "not yaml", content: "rules: [", wantErr: "parse detection rules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv(config.HomeEnv, home)
+			if tt.content != "" {
+				if err := os.WriteFile(filepath.Join(home, "detection.yaml"), []byte(tt.content), 0o644); err != nil {
+					t.Fatalf("failed to write rules: %v", err)
+				}
+			}
+
+			got, err := LoadDetectionRules()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadDetectionRules() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadDetectionRules() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadDetectionRules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeDetectionRules(t *testing.T) {
+	builtin := []DetectionRule{
+		{Patterns: []string{"package.json"}, Templates: []string{"Node"}},
+		{Patterns: []string{"requirements.txt", "setup.py"}, Templates: []string{"Python"}},
+	}
+	user := []DetectionRule{
+		{Patterns: []string{"Package.json"}, Templates: []string{"Yarn"}},
+		{Patterns: []string{"setup.py"}, Templates: []string{"Pants"}},
+	}
+
+	merged := MergeDetectionRules(builtin, user)
+	want := []DetectionRule{
+		user[0],
+		user[1],
+		{Patterns: []string{"requirements.txt"}, Templates: []string{"Python"}},
+	}
+	if !reflect.DeepEqual(merged, want) {
+		t.Fatalf("MergeDetectionRules() = %v, want %v", merged, want)
+	}
+
+	tests := []struct {
+		detected []string
+		want     []string
+	}{
+		{detected: []string{"package.json"}, want: []string{"Yarn"}},
+		{detected: []string{"setup.py"}, want: []string{"Pants"}},
+		{detected: []string{"requirements.txt"}, want: []string{"Python"}},
+	}
+	for _, tt := range tests {
+		if got := MatchRules(merged, tt.detected); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MatchRules(%v) = %v, want %v", tt.detected, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestTemplatesUserRules(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(config.HomeEnv, home)
+	rules := "rules:\n  - patterns: [WORKSPACE]\n    templates: [Bazel]\n  - patterns: [\" go.mod \"]\n    templates: [GoWorkspace]\n"
+	if err := os.WriteFile(filepath.Join(home, "detection.yaml"), []byte(rules), 0o644); err != nil {
+		t.Fatalf("failed to write rules: %v", err)
+	}
+
+	got, err := SuggestTemplates([]string{"workspace", "go.mod", "package.json"})
+	if err != nil {
+		t.Fatalf("SuggestTemplates() error = %v", err)
+	}
+	if want := []string{"Bazel", "GoWorkspace", "Node"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SuggestTemplates() = %v, want %v", got, want)
+	}
+}
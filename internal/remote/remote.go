@@ -0,0 +1,133 @@
+// Package remote fetches shared preset files and template lists over HTTPS.
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Timeout bounds a whole fetch, including reading the body.
+	Timeout = 10 * time.Second
+	// MaxSize is the largest body Fetch accepts. Preset files and template lists are small text files.
+	MaxSize = 1 << 20
+)
+
+// ErrInsecureURL is returned for URLs that are not https.
+var ErrInsecureURL = errors.New("only https URLs are supported")
+
+// Client performs fetches. Tests replace it with a client that trusts their TLS server.
+var Client = &http.Client{Timeout: Timeout, CheckRedirect: httpsOnlyRedirect}
+
+// maxRedirects matches the limit of the default http.Client redirect policy.
+const maxRedirects = 10
+
+// httpsOnlyRedirect refuses redirects away from https, so an https URL cannot hand the fetch
+// to a plain http host.
+func httpsOnlyRedirect(req *http.Request, via []*http.Request) error {
+	if req.URL.Scheme != "https" {
+		return fmt.Errorf("%w: redirect to %s", ErrInsecureURL, req.URL.Redacted())
+	}
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	return nil
+}
+
+// textTypes are the content types accepted for preset files and template lists. Raw file hosts
+// usually serve text/plain; an HTML page means the URL points at a viewer, not the raw file.
+var textTypes = map[string]bool{
+	"text/plain":               true,
+	"text/yaml":                true,
+	"text/x-yaml":              true,
+	"application/yaml":         true,
+	"application/x-yaml":       true,
+	"application/octet-stream": true,
+}
+
+// Fetch downloads rawURL, which must be https, and returns its body. Responses that are not
+// 200 OK, not a text or YAML content type, or larger than MaxSize are rejected.
+func Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("parse url: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("%w: %s", ErrInsecureURL, rawURL)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", rawURL, err)
+	}
+	resp, err := Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", rawURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: %s", rawURL, resp.Status)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || !textTypes[mediaType] {
+			return nil, fmt.Errorf("fetch %s: unexpected content type %q (use the raw file URL)", rawURL, contentType)
+		}
+	}
+	if resp.ContentLength > MaxSize {
+		return nil, fmt.Errorf("fetch %s: response is larger than %d bytes", rawURL, MaxSize)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", rawURL, err)
+	}
+	if len(data) > MaxSize {
+		return nil, fmt.Errorf("fetch %s: response is larger than %d bytes", rawURL, MaxSize)
+	}
+	return data, nil
+}
+
+// ParseList returns the template names in a shared list: one or more names per line, separated
+// by whitespace or commas. Blank lines and lines starting with # are skipped.
+func ParseList(data []byte) []string {
+	entries := ParseListEntries(data)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	return names
+}
+
+// ListEntry is one template name in a list file and the 1-based line it is on.
+type ListEntry struct {
+	Name string
+	Line int
+}
+
+// ParseListEntries is ParseList that also records the line of each name, for reporting problems.
+func ParseListEntries(data []byte) []ListEntry {
+	var entries []ListEntry
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, name := range strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t' || r == '\r'
+		}) {
+			entries = append(entries, ListEntry{Name: name, Line: i + 1})
+		}
+	}
+	return entries
+}
@@ -0,0 +1,118 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/presets.yaml":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = w.Write([]byte("presets: []\n"))
+		case "/page":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html></html>"))
+		case "/large":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(strings.Repeat("a", MaxSize+1)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	original := Client
+	Client = server.Client()
+	defer func() { Client = original }()
+
+	data, err := Fetch(context.Background(), server.URL+"/presets.yaml")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if string(data) != "presets: []\n" {
+		t.Errorf("Fetch() = %q, want presets body", data)
+	}
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{name: "html page", url: server.URL + "/page", wantErr: "content type"},
+		{name: "too large", url: server.URL + "/large", wantErr: "larger than"},
+		{name: "not found", url: server.URL + "/missing", wantErr: "404"},
+		{name: "plain http", url: strings.Replace(server.URL, "https://", "http://", 1) + "/presets.yaml", wantErr: ErrInsecureURL.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Fetch(context.Background(), tt.url)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Fetch(%s) error = %v, want %q", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := Fetch(context.Background(), "file:///etc/passwd"); !errors.Is(err, ErrInsecureURL) {
+		t.Errorf("Fetch(file URL) error = %v, want ErrInsecureURL", err)
+	}
+}
+
+func TestFetchRedirects(t *testing.T) {
+	plainHit := false
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		plainHit = true
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("presets: []\n"))
+	}))
+	defer plain.Close()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/presets.yaml":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("presets: []\n"))
+		case "/moved":
+			http.Redirect(w, r, "/presets.yaml", http.StatusFound)
+		case "/downgrade":
+			http.Redirect(w, r, plain.URL+"/presets.yaml", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	original := Client
+	Client = server.Client()
+	Client.CheckRedirect = original.CheckRedirect
+	defer func() { Client = original }()
+
+	if data, err := Fetch(context.Background(), server.URL+"/moved"); err != nil || string(data) != "presets: []\n" {
+		t.Errorf("Fetch(https redirect) = %q, %v, want the presets body", data, err)
+	}
+	if _, err := Fetch(context.Background(), server.URL+"/downgrade"); !errors.Is(err, ErrInsecureURL) {
+		t.Errorf("Fetch(http redirect) error = %v, want ErrInsecureURL", err)
+	}
+	if plainHit {
+		t.Error("Fetch() followed a redirect to a plain http URL")
+	}
+}
+
+func TestParseList(t *testing.T) {
+	data := []byte("# team templates\nGo, Node\r\n\n  macOS\tVisualStudioCode\n")
+	want := []string{"Go", "Node", "macOS", "VisualStudioCode"}
+	if got := ParseList(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseList() = %v, want %v", got, want)
+	}
+}
+
+func TestParseListEntries(t *testing.T) {
+	data := []byte("# team templates\nGo, Node\r\n\n  macOS\n")
+	want := []ListEntry{{Name: "Go", Line: 2}, {Name: "Node", Line: 2}, {Name: "macOS", Line: 4}}
+	if got := ParseListEntries(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseListEntries() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+// Package templates provides line diffs between template versions.
+package templates
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is how many unchanged lines surround each change in a hunk.
+const diffContext = 3
+
+type diffOp struct {
+	kind byte // ' ', '-', or '+'
+	text string
+}
+
+// UnifiedDiff returns a unified diff turning from into to, labelled with fromName and
+// toName, or "" when the contents are identical line for line.
+func UnifiedDiff(fromName, toName, from, to string) string {
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	var changes []int
+	for i, op := range ops {
+		if op.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", fromName, toName)
+
+	for i := 0; i < len(changes); {
+		start := max(changes[i]-diffContext, 0)
+		last := changes[i]
+		// Merge changes whose context would overlap into one hunk.
+		for i++; i < len(changes) && changes[i]-last <= 2*diffContext; i++ {
+			last = changes[i]
+		}
+		end := min(last+diffContext+1, len(ops))
+		writeHunk(&builder, ops, start, end)
+	}
+	return builder.String()
+}
+
+func writeHunk(builder *strings.Builder, ops []diffOp, start, end int) {
+	fromBefore, toBefore := 0, 0
+	for _, op := range ops[:start] {
+		if op.kind != '+' {
+			fromBefore++
+		}
+		if op.kind != '-' {
+			toBefore++
+		}
+	}
+	fromCount, toCount := 0, 0
+	for _, op := range ops[start:end] {
+		if op.kind != '+' {
+			fromCount++
+		}
+		if op.kind != '-' {
+			toCount++
+		}
+	}
+
+	fmt.Fprintf(builder, "@@ -%s +%s @@\n", hunkRange(fromBefore, fromCount), hunkRange(toBefore, toCount))
+	for _, op := range ops[start:end] {
+		builder.WriteByte(op.kind)
+		builder.WriteString(op.text)
+		builder.WriteByte('\n')
+	}
+}
+
+// hunkRange formats a hunk side like diff -u: an empty side names the line before it.
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// diffLines computes a minimal line diff from the longest common subsequence.
+func diffLines(a, b []string) []diffOp {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{kind: ' ', text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{kind: '-', text: a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{kind: '-', text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{kind: '+', text: b[j]})
+	}
+	return ops
+}
+
+func splitLines(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
@@ -0,0 +1,53 @@
+package templates
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{
+			name: "identical",
+			from: "*.exe\nvendor/\n",
+			to:   "*.exe\nvendor/",
+			want: "",
+		},
+		{
+			name: "changed line",
+			from: "# Go\n*.exe\nvendor/\n",
+			to:   "# Go\n*.exe\n# vendor/\n",
+			want: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n # Go\n *.exe\n-vendor/\n+# vendor/\n",
+		},
+		{
+			name: "added to empty",
+			from: "",
+			to:   "*.log\n",
+			want: "--- a\n+++ b\n@@ -0,0 +1 @@\n+*.log\n",
+		},
+		{
+			name: "distant changes make separate hunks",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			to:   "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			want: "--- a\n+++ b\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n" +
+				"@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+		{
+			name: "nearby changes share a hunk",
+			from: "1\n2\n3\n4\n5\n",
+			to:   "1\n2b\n3\n4b\n5\n",
+			want: "--- a\n+++ b\n@@ -1,5 +1,5 @@\n 1\n-2\n+2b\n 3\n-4\n+4b\n 5\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff("a", "b", tt.from, tt.to); got != tt.want {
+				t.Errorf("UnifiedDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
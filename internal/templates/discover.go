@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io/fs"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 )
 
 type Category string
@@ -27,8 +29,58 @@ const (
 type Template struct {
 	Name     string
 	Category Category
-	Path     string
-	Source   TemplateSource
+	// Subcategory is the folder below the category folder, e.g. "JavaScript" for community/JavaScript/Vue.gitignore.
+	// It is empty for templates that sit directly in their category folder.
+	Subcategory string
+	Path        string
+	Source      TemplateSource
+}
+
+// QualifiedCategory returns the two-level category, e.g. "community/JavaScript", or just the category
+// when there is no subcategory.
+func (t Template) QualifiedCategory() string {
+	if t.Subcategory == "" {
+		return string(t.Category)
+	}
+	return string(t.Category) + "/" + t.Subcategory
+}
+
+// WithoutCategories returns items whose category or qualified category is not in hidden.
+// Matching is case-insensitive.
+func WithoutCategories(items []Template, hidden []Category) []Template {
+	if len(hidden) == 0 {
+		return items
+	}
+	visible := make([]Template, 0, len(items))
+	for _, item := range items {
+		if !inCategories(item, hidden) {
+			visible = append(visible, item)
+		}
+	}
+	return visible
+}
+
+// OnlyCategory returns items whose category or qualified category is category,
+// e.g. "Global" or "community/JavaScript". Matching is case-insensitive.
+func OnlyCategory(items []Template, category string) []Template {
+	matched := make([]Template, 0, len(items))
+	for _, item := range items {
+		if inCategories(item, []Category{Category(category)}) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+// inCategories reports whether item's category or qualified category is one of categories.
+func inCategories(item Template, categories []Category) bool {
+	for _, c := range categories {
+		category := strings.Trim(strings.TrimSpace(string(c)), "/")
+		if strings.EqualFold(category, string(item.Category)) || strings.EqualFold(category, item.QualifiedCategory()) {
+			return true
+		}
+	}
+	return false
 }
 
 type Index struct {
@@ -36,14 +88,67 @@ type Index struct {
 	List   []Template
 }
 
+// discoveryKey identifies one memoized discovery walk.
+type discoveryKey struct {
+	root   string
+	source TemplateSource
+}
+
+// discovery is the memoized result of one walk. unnamed holds the paths of files named exactly
+// ".gitignore", which have no template name and are left out of items.
+type discovery struct {
+	items   []Template
+	unnamed []string
+}
+
+var (
+	discoveryMu sync.Mutex
+	discovered  = make(map[discoveryKey]discovery)
+	// walkDir is replaced in tests to count filesystem walks.
+	walkDir = filepath.WalkDir
+)
+
+// DiscoverTemplates returns the templates under cachePath. Results are memoized for the
+// life of the process, so repeated calls within one command walk the directory once.
 func DiscoverTemplates(cachePath string) ([]Template, error) {
-	return discoverTemplates(cachePath, SourceCache, categorize)
+	result, err := discoverTemplates(cachePath, SourceCache, categorize)
+	return result.items, err
 }
 
-func discoverTemplates(rootPath string, source TemplateSource, categorizePath func(string) Category) ([]Template, error) {
+// ForgetDiscovered drops memoized discovery results for rootPath, so the next call walks
+// it again. Call it after changing the templates on disk.
+func ForgetDiscovered(rootPath string) {
+	root := filepath.Clean(rootPath)
+	discoveryMu.Lock()
+	defer discoveryMu.Unlock()
+	for key := range discovered {
+		if key.root == root {
+			delete(discovered, key)
+		}
+	}
+}
+
+func discoverTemplates(rootPath string, source TemplateSource, categorizePath func(string) Category) (discovery, error) {
+	key := discoveryKey{root: filepath.Clean(rootPath), source: source}
+	discoveryMu.Lock()
+	defer discoveryMu.Unlock()
+	if result, ok := discovered[key]; ok {
+		return discovery{items: slices.Clone(result.items), unnamed: slices.Clone(result.unnamed)}, nil
+	}
+
+	result, err := walkTemplates(rootPath, source, categorizePath)
+	if err != nil {
+		return discovery{}, err
+	}
+	discovered[key] = result
+	return discovery{items: slices.Clone(result.items), unnamed: slices.Clone(result.unnamed)}, nil
+}
+
+func walkTemplates(rootPath string, source TemplateSource, categorizePath func(string) Category) (discovery, error) {
 	var templates []Template
+	var unnamed []string
 
-	err := filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+	err := walkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -63,24 +168,35 @@ func discoverTemplates(rootPath string, source TemplateSource, categorizePath fu
 			return fmt.Errorf("rel path: %w", err)
 		}
 
-		category := categorizePath(rel)
 		name := normalizeName(d.Name())
+		if name == "" {
+			unnamed = append(unnamed, path)
+			return nil
+		}
+		category := categorizePath(rel)
+		subcategory := ""
+		if source == SourceCache {
+			subcategory = subcategorize(rel)
+		}
 
 		templates = append(templates, Template{
-			Name:     name,
-			Category: category,
-			Path:     path,
-			Source:   source,
+			Name:        name,
+			Category:    category,
+			Subcategory: subcategory,
+			Path:        path,
+			Source:      source,
 		})
 		return nil
 	})
 	if err != nil {
-		return nil, err
+		return discovery{}, err
 	}
 
-	return templates, nil
+	return discovery{items: templates, unnamed: unnamed}, nil
 }
 
+// BuildIndex indexes templates by lowercase name. When names collide a user template wins over
+// a cached one, so custom copies override upstream; otherwise the first template listed wins.
 func BuildIndex(templates []Template) Index {
 	index := Index{
 		ByName: make(map[string]Template, len(templates)),
@@ -89,7 +205,7 @@ func BuildIndex(templates []Template) Index {
 
 	for _, t := range templates {
 		key := strings.ToLower(t.Name)
-		if _, exists := index.ByName[key]; !exists {
+		if existing, exists := index.ByName[key]; !exists || (t.Source == SourceUser && existing.Source != SourceUser) {
 			index.ByName[key] = t
 		}
 	}
@@ -97,9 +213,26 @@ func BuildIndex(templates []Template) Index {
 	return index
 }
 
+// synonyms maps common ecosystem nicknames to the template they mean. A template whose own name
+// matches always wins, so a custom template named "py" shadows the built-in synonym.
+var synonyms = map[string]string{
+	"golang": "go",
+	"js":     "node",
+	"py":     "python",
+	"rb":     "ruby",
+	"ts":     "typescript",
+}
+
+// FindTemplate looks up name case-insensitively, with or without the .gitignore suffix, falling
+// back to the built-in synonyms (e.g. golang for Go).
 func FindTemplate(index Index, name string) (Template, bool) {
 	key := strings.ToLower(normalizeName(name))
 	t, ok := index.ByName[key]
+	if !ok {
+		if target, isSynonym := synonyms[key]; isSynonym {
+			t, ok = index.ByName[target]
+		}
+	}
 	return t, ok
 }
 
@@ -124,3 +257,12 @@ func categorize(relPath string) Category {
 		return CategoryRoot
 	}
 }
+
+// subcategorize returns the folder directly below a Global or community folder, if any.
+func subcategorize(relPath string) string {
+	parts := strings.Split(relPath, string(filepath.Separator))
+	if len(parts) < 3 || categorize(relPath) == CategoryRoot {
+		return ""
+	}
+	return parts[1]
+}
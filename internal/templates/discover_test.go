@@ -1,7 +1,11 @@
 package templates
 
 import (
+	"io/fs"
+	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -112,6 +116,74 @@ func TestCategorize(t *testing.T) {
 	}
 }
 
+func TestSubcategorize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "root template", input: "Go.gitignore", expected: ""},
+		{name: "flat community", input: filepath.Join("community", "Ruby.gitignore"), expected: ""},
+		{
+			name:     "community subfolder",
+			input:    filepath.Join("community", "JavaScript", "Vue.gitignore"),
+			expected: "JavaScript",
+		},
+		{
+			name:     "deep community subfolder uses first level",
+			input:    filepath.Join("community", "Linux", "Distro", "Snap.gitignore"),
+			expected: "Linux",
+		},
+		{name: "global subfolder", input: filepath.Join("Global", "Sub", "macOS.gitignore"), expected: "Sub"},
+		{name: "nested root folder", input: filepath.Join("Other", "Sub", "X.gitignore"), expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := subcategorize(tt.input); got != tt.expected {
+				t.Errorf("subcategorize(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDiscoverTemplatesSubcategory(t *testing.T) {
+	root := t.TempDir()
+	files := []string{
+		"Go.gitignore",
+		filepath.Join("community", "Ruby.gitignore"),
+		filepath.Join("community", "JavaScript", "Vue.gitignore"),
+	}
+	for _, rel := range files {
+		full := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(full, []byte("# test"), 0o644); err != nil {
+			t.Fatalf("failed to write template: %v", err)
+		}
+	}
+
+	items, err := DiscoverTemplates(root)
+	if err != nil {
+		t.Fatalf("DiscoverTemplates() error = %v", err)
+	}
+
+	want := map[string]string{
+		"Go":   "root",
+		"Ruby": "community",
+		"Vue":  "community/JavaScript",
+	}
+	for _, item := range items {
+		if got := item.QualifiedCategory(); got != want[item.Name] {
+			t.Errorf("%s QualifiedCategory() = %q, want %q", item.Name, got, want[item.Name])
+		}
+		if item.Name == "Vue" && item.Category != CategoryCommunity {
+			t.Errorf("Vue Category = %q, want %q", item.Category, CategoryCommunity)
+		}
+	}
+}
+
 func TestBuildIndex(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -166,6 +238,45 @@ func TestBuildIndex(t *testing.T) {
 	}
 }
 
+func TestFindTemplateUserOverridesCache(t *testing.T) {
+	root := t.TempDir()
+	cachePath := filepath.Join(root, "cache")
+	userPath := filepath.Join(root, "user")
+	for path, content := range map[string]string{
+		filepath.Join(cachePath, "Go.gitignore"):     "vendor/\n",
+		filepath.Join(cachePath, "Python.gitignore"): "*.pyc\n",
+		filepath.Join(userPath, "Go.gitignore"):      "*.test\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write template: %v", err)
+		}
+	}
+
+	cached, err := DiscoverTemplates(cachePath)
+	if err != nil {
+		t.Fatalf("DiscoverTemplates() error = %v", err)
+	}
+	user, err := DiscoverUserTemplates(userPath)
+	if err != nil {
+		t.Fatalf("DiscoverUserTemplates() error = %v", err)
+	}
+
+	// Callers append user templates after cached ones.
+	index := BuildIndex(append(cached, user...))
+	if got, ok := FindTemplate(index, "go"); !ok || got.Source != SourceUser || got.Path != filepath.Join(userPath, "Go.gitignore") {
+		t.Errorf("FindTemplate(go) = %+v, %v; want the user Go.gitignore", got, ok)
+	}
+	if got, ok := FindTemplate(index, "golang"); !ok || got.Source != SourceUser {
+		t.Errorf("FindTemplate(golang) = %+v, %v; want the user template through the synonym", got, ok)
+	}
+	if got, ok := FindTemplate(index, "Python"); !ok || got.Source != SourceCache {
+		t.Errorf("FindTemplate(Python) = %+v, %v; want the cached template", got, ok)
+	}
+}
+
 func TestFindTemplate(t *testing.T) {
 	index := BuildIndex([]Template{
 		{Name: "Go", Path: "/go.gitignore"},
@@ -229,6 +340,40 @@ func TestFindTemplate(t *testing.T) {
 	}
 }
 
+func TestFindTemplateSynonyms(t *testing.T) {
+	index := BuildIndex([]Template{
+		{Name: "Go", Path: "/go.gitignore"},
+		{Name: "Python", Path: "/python.gitignore"},
+		{Name: "Node", Path: "/node.gitignore"},
+		{Name: "Ruby", Path: "/ruby.gitignore"},
+		{Name: "TypeScript", Path: "/typescript.gitignore"},
+		{Name: "rb", Path: "/user/rb.gitignore", Source: SourceUser},
+	})
+
+	tests := []struct {
+		search string
+		want   string
+	}{
+		{search: "golang", want: "/go.gitignore"},
+		{search: "GoLang.gitignore", want: "/go.gitignore"},
+		{search: "py", want: "/python.gitignore"},
+		{search: "js", want: "/node.gitignore"},
+		{search: "ts", want: "/typescript.gitignore"},
+		// A template named like a synonym takes precedence over it.
+		{search: "rb", want: "/user/rb.gitignore"},
+	}
+	for _, tt := range tests {
+		got, ok := FindTemplate(index, tt.search)
+		if !ok || got.Path != tt.want {
+			t.Errorf("FindTemplate(%q) = %v, %v, want %s", tt.search, got, ok, tt.want)
+		}
+	}
+
+	if _, ok := FindTemplate(BuildIndex([]Template{{Name: "Go", Path: "/go.gitignore"}}), "py"); ok {
+		t.Error("FindTemplate(py) found a template, want no match when Python is missing")
+	}
+}
+
 func TestNormalizeNameEdgeCases(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -261,3 +406,118 @@ func TestNormalizeNameEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestWithoutCategories(t *testing.T) {
+	items := []Template{
+		{Name: "Go", Category: CategoryRoot},
+		{Name: "macOS", Category: CategoryGlobal},
+		{Name: "Vue", Category: CategoryCommunity, Subcategory: "JavaScript"},
+		{Name: "Terraform", Category: CategoryCommunity},
+	}
+
+	tests := []struct {
+		name   string
+		hidden []Category
+		want   []string
+	}{
+		{name: "nothing hidden", hidden: nil, want: []string{"Go", "macOS", "Vue", "Terraform"}},
+		{name: "whole category case-insensitive", hidden: []Category{"COMMUNITY"}, want: []string{"Go", "macOS"}},
+		{name: "subcategory only", hidden: []Category{"community/javascript/"}, want: []string{"Go", "macOS", "Terraform"}},
+		{name: "multiple", hidden: []Category{"Global", "root"}, want: []string{"Vue", "Terraform"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, item := range WithoutCategories(items, tt.hidden) {
+				got = append(got, item.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("WithoutCategories(%v) = %v, want %v", tt.hidden, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnlyCategory(t *testing.T) {
+	items := []Template{
+		{Name: "Go", Category: CategoryRoot},
+		{Name: "macOS", Category: CategoryGlobal},
+		{Name: "Go", Category: CategoryGlobal},
+		{Name: "Vue", Category: CategoryCommunity, Subcategory: "JavaScript"},
+		{Name: "Terraform", Category: CategoryCommunity},
+	}
+
+	tests := []struct {
+		name     string
+		category string
+		want     []string
+	}{
+		{name: "category case-insensitive", category: "global", want: []string{"macOS", "Go"}},
+		{name: "whole category includes subcategories", category: "community", want: []string{"Vue", "Terraform"}},
+		{name: "subcategory", category: "/community/JavaScript", want: []string{"Vue"}},
+		{name: "unknown", category: "nope", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, item := range OnlyCategory(items, tt.category) {
+				got = append(got, item.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("OnlyCategory(%q) = %v, want %v", tt.category, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverTemplatesMemoized(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "Go.gitignore"), []byte("*.exe\n"), 0o644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	walks := 0
+	originalWalk := walkDir
+	walkDir = func(path string, fn fs.WalkDirFunc) error {
+		walks++
+		return originalWalk(path, fn)
+	}
+	defer func() { walkDir = originalWalk }()
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			items, err := DiscoverTemplates(root)
+			if err != nil {
+				t.Errorf("DiscoverTemplates() error = %v", err)
+				return
+			}
+			if len(items) != 1 || items[0].Name != "Go" {
+				t.Errorf("DiscoverTemplates() = %v, want [Go]", items)
+			}
+		}()
+	}
+	wg.Wait()
+	if walks != 1 {
+		t.Errorf("filesystem walked %d times across two calls, want 1", walks)
+	}
+
+	// Callers may append to the result without affecting later calls.
+	items, _ := DiscoverTemplates(root)
+	_ = append(items, Template{Name: "Extra"})
+	if again, _ := DiscoverTemplates(root); len(again) != 1 {
+		t.Errorf("DiscoverTemplates() = %v after caller append, want 1 template", again)
+	}
+
+	ForgetDiscovered(root)
+	if _, err := DiscoverTemplates(root); err != nil {
+		t.Fatalf("DiscoverTemplates() error = %v", err)
+	}
+	if walks != 2 {
+		t.Errorf("filesystem walked %d times after ForgetDiscovered, want 2", walks)
+	}
+}
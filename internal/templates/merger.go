@@ -2,23 +2,102 @@
 package templates
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
 )
 
+// DefaultCommentPrefix starts the generator header and template section markers.
+const DefaultCommentPrefix = "#"
+
+// ErrMarkerNotFound is returned by InjectAtMarker when no line matches the marker.
+var ErrMarkerNotFound = errors.New("marker not found")
+
 type MergeOptions struct {
 	Deduplicate bool
 	AddHeader   bool
 	Generator   string
 	Version     string
 	Timestamp   time.Time
+	// CommentPrefix starts synthetic header and section lines; empty means DefaultCommentPrefix.
+	// Template content is written unchanged.
+	CommentPrefix string
+	// FooterTemplate is written after the last section, one comment line per line of text.
+	// Empty means no footer.
+	FooterTemplate string
+	// SortPatternsWithinSection sorts each template's pattern lines alphabetically, moving its
+	// comments to the top. Negations stay in place so they still follow the patterns they override.
+	SortPatternsWithinSection bool
+	// ExcludePatterns are pattern lines removed from the merged output wherever a template
+	// includes them. Lines are compared after trimming surrounding whitespace.
+	ExcludePatterns []string
+	// SortSections orders template sections, and the header's template list, by name
+	// instead of selection order.
+	SortSections bool
+	// OmitTimestamp leaves the timestamp line out of the header.
+	OmitTimestamp bool
+	// AppendBanner starts the output with a "--- Added by <Generator> on <date> ---" comment
+	// marking where appended content begins. The date is left out with OmitTimestamp.
+	AppendBanner bool
+	// NormalizeWhitespace converts CRLF to LF, trims trailing whitespace from every line,
+	// collapses runs of blank lines, and ends the output with a single newline.
+	NormalizeWhitespace bool
+	// SectionHeaders writes each template's section marker as "### <Name> ###" instead of
+	// "# --- <Name> ---", so the origin of every rule stands out when auditing the file.
+	// Deduplication keeps the marker even when the template adds no new rules, unless the
+	// same template is merged twice.
+	SectionHeaders bool
+}
+
+// Canonical returns opts with every ordering and formatting option that makes output
+// byte-stable for the same templates: sorted sections and lines, no timestamp, and
+// normalized whitespace.
+func (opts MergeOptions) Canonical() MergeOptions {
+	opts.SortSections = true
+	opts.SortPatternsWithinSection = true
+	opts.OmitTimestamp = true
+	opts.NormalizeWhitespace = true
+	return opts
+}
+
+// commentPrefixes are the prefixes ValidateCommentPrefix accepts. Only # starts a comment in
+// a gitignore file; ; and // are for ignore files of other tools, and git would read header
+// lines starting with them as ignore rules. Callers writing files git reads use #.
+var commentPrefixes = []string{"#", ";", "//"}
+
+// ValidateCommentPrefix checks that prefix is one of commentPrefixes.
+func ValidateCommentPrefix(prefix string) error {
+	if !slices.Contains(commentPrefixes, prefix) {
+		return fmt.Errorf("invalid comment prefix %q: must be one of %s", prefix, strings.Join(commentPrefixes, ", "))
+	}
+	return nil
 }
 
 func MergeTemplates(loaded []LoadedTemplate, opts MergeOptions) string {
+	prefix := opts.CommentPrefix
+	if prefix == "" {
+		prefix = DefaultCommentPrefix
+	}
+
+	if opts.SortSections {
+		loaded = append([]LoadedTemplate(nil), loaded...)
+		sort.SliceStable(loaded, func(i, j int) bool {
+			a, b := loaded[i].Template.Name, loaded[j].Template.Name
+			if !strings.EqualFold(a, b) {
+				return strings.ToLower(a) < strings.ToLower(b)
+			}
+			return a < b
+		})
+	}
+
 	var builder strings.Builder
 
 	if opts.AddHeader {
-		header := BuildHeader(loaded, opts.Generator, opts.Version, opts.Timestamp)
+		header := buildHeader(loaded, opts.Generator, opts.Version, opts.Timestamp, !opts.OmitTimestamp, prefix)
 		builder.WriteString(header)
 	}
 
@@ -26,19 +105,166 @@ func MergeTemplates(loaded []LoadedTemplate, opts MergeOptions) string {
 		if i > 0 {
 			builder.WriteString("\n\n")
 		}
-		builder.WriteString("# --- ")
-		builder.WriteString(t.Template.Name)
-		builder.WriteString(" ---\n")
-		builder.WriteString(strings.TrimRight(t.Content, "\n"))
+		builder.WriteString(sectionMarkerLine(prefix, t.Template.Name, opts.SectionHeaders))
+		builder.WriteString("\n")
+		content := t.Content
+		if opts.SortPatternsWithinSection {
+			content = sortSectionPatterns(content)
+		}
+		builder.WriteString(strings.TrimRight(content, "\n"))
 		builder.WriteString("\n")
 	}
 
 	merged := builder.String()
-	if !opts.Deduplicate {
-		return merged
+	if opts.Deduplicate {
+		merged = deduplicateBlocks(merged, prefix)
+	}
+	if len(opts.ExcludePatterns) > 0 {
+		merged = RemovePatterns(merged, opts.ExcludePatterns)
+	}
+
+	// The footer is added after deduplication so its lines are never dropped.
+	if footer := buildFooter(opts.FooterTemplate, prefix); footer != "" {
+		merged = strings.TrimRight(merged, "\n") + "\n\n" + footer
+	}
+	if opts.AppendBanner {
+		merged = buildAppendBanner(opts.Generator, opts.Timestamp, !opts.OmitTimestamp, prefix) + merged
+	}
+	if opts.NormalizeWhitespace {
+		merged = normalizeWhitespace(merged)
+	}
+	return merged
+}
+
+// normalizeWhitespace converts CRLF to LF, trims trailing whitespace, collapses runs of
+// blank lines into one, and drops leading and trailing blank lines.
+func normalizeWhitespace(content string) string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if line == "" && (len(out) == 0 || out[len(out)-1] == "") {
+			continue
+		}
+		out = append(out, line)
+	}
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	if len(out) == 0 {
+		return ""
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// sortSectionPatterns returns content with comments first, in their original order,
+// followed by the patterns sorted alphabetically. Blank lines are dropped. A negation
+// ("!pattern") only re-includes paths excluded by lines above it, so negations act as
+// barriers: patterns are sorted within the runs between them and never cross one.
+func sortSectionPatterns(content string) string {
+	var comments, patterns []string
+	run := []string{}
+	flush := func() {
+		sort.Strings(run)
+		patterns = append(patterns, run...)
+		run = run[:0]
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+		case strings.HasPrefix(trimmed, "#"):
+			comments = append(comments, line)
+		case strings.HasPrefix(trimmed, "!"):
+			flush()
+			patterns = append(patterns, line)
+		default:
+			run = append(run, line)
+		}
+	}
+	flush()
+
+	lines := append(comments, patterns...)
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// sectionMarkerLine is the line written before a template's rules. With headers it is the
+// MergeOptions.SectionHeaders style, "### Name ###" for the default prefix.
+func sectionMarkerLine(prefix, name string, headers bool) string {
+	if headers {
+		return prefix + "## " + name + " ##" + prefix
+	}
+	return prefix + " --- " + name + " ---"
+}
+
+func buildFooter(text, prefix string) string {
+	text = strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if strings.TrimSpace(text) == "" {
+		return ""
+	}
+
+	var builder strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		builder.WriteString(prefix)
+		if line != "" {
+			builder.WriteString(" ")
+			builder.WriteString(line)
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// RemovePatterns drops lines of content that equal one of patterns. Comments and
+// blank lines are never removed.
+func RemovePatterns(content string, patterns []string) string {
+	exclude := make(map[string]struct{}, len(patterns))
+	for _, pattern := range patterns {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			exclude[pattern] = struct{}{}
+		}
 	}
 
-	return DeduplicateLines(merged)
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if _, ok := exclude[trimmed]; ok && !strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// InjectAtMarker replaces the first line of existing that equals marker (ignoring
+// surrounding whitespace) with content, leaving the rest of the file untouched.
+func InjectAtMarker(existing, marker, content string) (string, error) {
+	marker = strings.TrimSpace(marker)
+	if marker == "" {
+		return "", fmt.Errorf("marker must not be empty")
+	}
+
+	lines := strings.SplitAfter(existing, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != marker {
+			continue
+		}
+		var builder strings.Builder
+		builder.WriteString(strings.Join(lines[:i], ""))
+		builder.WriteString(content)
+		rest := strings.Join(lines[i+1:], "")
+		if rest != "" && !strings.HasSuffix(content, "\n") {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(rest)
+		return builder.String(), nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrMarkerNotFound, marker)
 }
 
 func DeduplicateLines(content string) string {
@@ -57,8 +283,100 @@ func DeduplicateLines(content string) string {
 	return strings.Join(out, "\n")
 }
 
+// deduplicateBlocks drops pattern lines already seen earlier in content. Content is read as
+// blocks of comment lines followed by the patterns they describe; when a block adds no new
+// patterns, its comments that were seen before are dropped too, so a template merged twice
+// adds nothing. Runs of blank lines collapse into one, keeping a blank line between template
+// sections. Lines starting with prefix count as comments.
+func deduplicateBlocks(content, prefix string) string {
+	return deduplicateBlocksAfter(content, prefix, map[string]struct{}{})
+}
+
+// deduplicateBlocksAfter is deduplicateBlocks with seen holding lines that count as already
+// written. Lines are compared with surrounding whitespace trimmed, and seen is updated with
+// every line of content.
+func deduplicateBlocksAfter(content, prefix string, seen map[string]struct{}) string {
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines))
+
+	isComment := func(line string) bool {
+		trimmed := strings.TrimSpace(line)
+		return strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, prefix)
+	}
+	var comments, newComments, patterns []string
+	inPatterns := false
+	flush := func() {
+		if len(patterns) > 0 {
+			out = append(out, comments...)
+			out = append(out, patterns...)
+		} else {
+			out = append(out, newComments...)
+		}
+		comments, newComments, patterns = nil, nil, nil
+		inPatterns = false
+	}
+
+	for _, line := range lines {
+		key := strings.TrimSpace(line)
+		_, dup := seen[key]
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+			if len(out) == 0 || strings.TrimSpace(out[len(out)-1]) != "" {
+				out = append(out, line)
+			}
+			continue
+		case isComment(line):
+			if inPatterns {
+				flush()
+			}
+			comments = append(comments, line)
+			if !dup {
+				newComments = append(newComments, line)
+			}
+		default:
+			inPatterns = true
+			if !dup {
+				patterns = append(patterns, line)
+			}
+		}
+		seen[key] = struct{}{}
+	}
+	flush()
+
+	return strings.Join(out, "\n")
+}
+
+// AppendNew returns the part of addition worth appending to existing: addition deduplicated
+// like MergeTemplates output, treating every line of existing as already written. It is empty
+// when addition has no pattern that existing lacks, so merging the same templates twice
+// appends nothing. Lines match regardless of surrounding whitespace or CRLF endings. Lines
+// starting with prefix (or #) count as comments.
+func AppendNew(existing, addition, prefix string) string {
+	if prefix == "" {
+		prefix = DefaultCommentPrefix
+	}
+	seen := map[string]struct{}{}
+	for _, line := range strings.Split(existing, "\n") {
+		seen[strings.TrimSpace(line)] = struct{}{}
+	}
+
+	deduped := deduplicateBlocksAfter(addition, prefix, seen)
+	for _, line := range strings.Split(deduped, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") && !strings.HasPrefix(trimmed, prefix) {
+			return strings.TrimLeft(deduped, "\n")
+		}
+	}
+	return ""
+}
+
 func BuildHeader(loaded []LoadedTemplate, generator, version string, timestamp time.Time) string {
-	if timestamp.IsZero() {
+	return buildHeader(loaded, generator, version, timestamp, true, DefaultCommentPrefix)
+}
+
+func buildHeader(loaded []LoadedTemplate, generator, version string, timestamp time.Time, withTimestamp bool, prefix string) string {
+	if withTimestamp && timestamp.IsZero() {
 		timestamp = time.Now()
 	}
 
@@ -68,19 +386,37 @@ func BuildHeader(loaded []LoadedTemplate, generator, version string, timestamp t
 	}
 
 	var builder strings.Builder
-	builder.WriteString("# Generated by ")
+	builder.WriteString(prefix)
+	builder.WriteString(" Generated by ")
 	builder.WriteString(generator)
 	if version != "" {
 		builder.WriteString(" ")
 		builder.WriteString(version)
 	}
 	builder.WriteString("\n")
-	builder.WriteString("# Timestamp: ")
-	builder.WriteString(timestamp.Format(time.RFC3339))
-	builder.WriteString("\n")
-	builder.WriteString("# Templates: ")
+	if withTimestamp {
+		builder.WriteString(prefix)
+		builder.WriteString(" Timestamp: ")
+		builder.WriteString(timestamp.Format(time.RFC3339))
+		builder.WriteString("\n")
+	}
+	builder.WriteString(prefix)
+	builder.WriteString(" Templates: ")
 	builder.WriteString(strings.Join(templateNames, ", "))
 	builder.WriteString("\n\n")
 
 	return builder.String()
 }
+
+func buildAppendBanner(generator string, timestamp time.Time, withDate bool, prefix string) string {
+	if generator == "" {
+		generator = "ignr"
+	}
+	if !withDate {
+		return fmt.Sprintf("%s --- Added by %s ---\n", prefix, generator)
+	}
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	return fmt.Sprintf("%s --- Added by %s on %s ---\n", prefix, generator, timestamp.Format(time.DateOnly))
+}
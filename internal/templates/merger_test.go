@@ -1,6 +1,7 @@
 package templates
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -162,6 +163,56 @@ func TestDeduplicateLines(t *testing.T) {
 	}
 }
 
+func TestMergeTemplatesDeduplicateBlocks(t *testing.T) {
+	goTemplate := LoadedTemplate{
+		Template: Template{Name: "Go"},
+		Content:  "# Binaries\n*.exe\n*.dll\n\n\n# Test binary\n*.test\n\n# Dependency directories\nvendor/\n",
+	}
+	opts := MergeOptions{Deduplicate: true}
+
+	once := MergeTemplates([]LoadedTemplate{goTemplate}, opts)
+	if twice := MergeTemplates([]LoadedTemplate{goTemplate, goTemplate}, opts); twice != once {
+		t.Errorf("merging Go twice = %q, want the same as once %q", twice, once)
+	}
+	want := "# --- Go ---\n# Binaries\n*.exe\n*.dll\n\n# Test binary\n*.test\n\n# Dependency directories\nvendor/\n"
+	if once != want {
+		t.Errorf("merging Go once = %q, want %q", once, want)
+	}
+
+	// A repeated comment header stays when it introduces new patterns; one over only
+	// duplicates is skipped, and the sections keep a blank line between them.
+	loaded := []LoadedTemplate{
+		goTemplate,
+		{
+			Template: Template{Name: "Python"},
+			Content:  "# Binaries\n*.exe\n\n# Dependency directories\nvendor/\n.venv/\n",
+		},
+	}
+	want = "# --- Go ---\n# Binaries\n*.exe\n*.dll\n\n# Test binary\n*.test\n\n# Dependency directories\nvendor/\n\n" +
+		"# --- Python ---\n\n# Dependency directories\n.venv/\n"
+	if got := MergeTemplates(loaded, opts); got != want {
+		t.Errorf("MergeTemplates() = %q, want %q", got, want)
+	}
+}
+
+func TestMergeTemplatesSectionHeaders(t *testing.T) {
+	loaded := []LoadedTemplate{
+		{Template: Template{Name: "Go"}, Content: "# Binaries\n*.exe\nvendor/\n"},
+		{Template: Template{Name: "Node"}, Content: "*.log\nnode_modules/\n"},
+		{Template: Template{Name: "Vendor"}, Content: "vendor/\n"},
+	}
+
+	want := "### Go ###\n# Binaries\n*.exe\nvendor/\n\n" +
+		"### Node ###\n*.log\nnode_modules/\n\n" +
+		"### Vendor ###\n"
+	if got := MergeTemplates(loaded, MergeOptions{Deduplicate: true, SectionHeaders: true}); got != want {
+		t.Errorf("MergeTemplates() = %q, want %q", got, want)
+	}
+	if got := MergeTemplates(loaded, MergeOptions{Deduplicate: true}); strings.Contains(got, "###") {
+		t.Errorf("MergeTemplates() without SectionHeaders = %q, want no ### headers", got)
+	}
+}
+
 func TestBuildHeader(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -230,3 +281,322 @@ func TestBuildHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeTemplatesCommentPrefix(t *testing.T) {
+	loaded := []LoadedTemplate{
+		{Template: Template{Name: "Go"}, Content: "# Go binaries\n*.exe\n"},
+		{Template: Template{Name: "Node"}, Content: "node_modules/\n"},
+	}
+	opts := MergeOptions{
+		AddHeader:     true,
+		Generator:     "ignr",
+		Version:       "1.0.0",
+		Timestamp:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		CommentPrefix: "//",
+	}
+
+	want := "// Generated by ignr 1.0.0\n" +
+		"// Timestamp: 2024-01-01T00:00:00Z\n" +
+		"// Templates: Go, Node\n\n" +
+		"// --- Go ---\n# Go binaries\n*.exe\n\n\n" +
+		"// --- Node ---\nnode_modules/\n"
+	if got := MergeTemplates(loaded, opts); got != want {
+		t.Errorf("MergeTemplates() = %q, want %q", got, want)
+	}
+}
+
+func TestValidateCommentPrefix(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		wantErr bool
+	}{
+		{prefix: "#"},
+		{prefix: ";"},
+		{prefix: "//"},
+		{prefix: "", wantErr: true},
+		{prefix: "####", wantErr: true},
+		{prefix: "# ", wantErr: true},
+		{prefix: "a", wantErr: true},
+		{prefix: "#\n", wantErr: true},
+		{prefix: "*", wantErr: true},
+		{prefix: "!", wantErr: true},
+		{prefix: "/", wantErr: true},
+		{prefix: "[", wantErr: true},
+		{prefix: "?", wantErr: true},
+		{prefix: "%", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			err := ValidateCommentPrefix(tt.prefix)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCommentPrefix(%q) error = %v, wantErr %v", tt.prefix, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMergeTemplatesFooter(t *testing.T) {
+	loaded := []LoadedTemplate{
+		{Template: Template{Name: "Go"}, Content: "*.exe\n\n\n"},
+	}
+
+	tests := []struct {
+		name string
+		opts MergeOptions
+		want string
+	}{
+		{
+			name: "no footer",
+			opts: MergeOptions{},
+			want: "# --- Go ---\n*.exe\n",
+		},
+		{
+			name: "single line footer",
+			opts: MergeOptions{FooterTemplate: "end of generated rules"},
+			want: "# --- Go ---\n*.exe\n\n# end of generated rules\n",
+		},
+		{
+			name: "multi-line footer keeps blank lines",
+			opts: MergeOptions{FooterTemplate: "local rules below\n\nkeep this\n"},
+			want: "# --- Go ---\n*.exe\n\n# local rules below\n#\n# keep this\n",
+		},
+		{
+			name: "footer survives deduplication",
+			opts: MergeOptions{Deduplicate: true, FooterTemplate: "--- Go ---"},
+			want: "# --- Go ---\n*.exe\n\n# --- Go ---\n",
+		},
+		{
+			name: "footer uses comment prefix",
+			opts: MergeOptions{CommentPrefix: ";", FooterTemplate: "done"},
+			want: "; --- Go ---\n*.exe\n\n; done\n",
+		},
+		{
+			name: "whitespace-only footer is ignored",
+			opts: MergeOptions{FooterTemplate: "  \n"},
+			want: "# --- Go ---\n*.exe\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MergeTemplates(loaded, tt.opts); got != tt.want {
+				t.Errorf("MergeTemplates() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortSectionPatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "sorts patterns",
+			content: "vendor/\n*.log\nbuild/\n",
+			want:    "*.log\nbuild/\nvendor/\n",
+		},
+		{
+			name:    "comments move to the top in order",
+			content: "*.tmp\n# Logs\n*.log\n# Build output\nbuild/\n",
+			want:    "# Logs\n# Build output\n*.log\n*.tmp\nbuild/\n",
+		},
+		{
+			name:    "blank lines are dropped",
+			content: "b\n\n\na\n",
+			want:    "a\nb\n",
+		},
+		{
+			name:    "negation stays after the pattern it overrides",
+			content: "logs/*\n!logs/.gitkeep\n*.bak\n",
+			want:    "logs/*\n!logs/.gitkeep\n*.bak\n",
+		},
+		{
+			name:    "patterns sort within runs between negations",
+			content: "z/*\na/*\n!z/keep\n*.tmp\n*.bak\n!a/keep\n",
+			want:    "a/*\nz/*\n!z/keep\n*.bak\n*.tmp\n!a/keep\n",
+		},
+		{
+			name:    "escaped hash is a pattern",
+			content: "\\#notes\n*.a\n",
+			want:    "*.a\n\\#notes\n",
+		},
+		{
+			name:    "comments only",
+			content: "# nothing here\n",
+			want:    "# nothing here\n",
+		},
+		{
+			name:    "empty",
+			content: "\n\n",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sortSectionPatterns(tt.content)
+			if got != tt.want {
+				t.Errorf("sortSectionPatterns() = %q, want %q", got, tt.want)
+			}
+			if again := sortSectionPatterns(got); again != got {
+				t.Errorf("sortSectionPatterns() not stable: %q then %q", got, again)
+			}
+		})
+	}
+}
+
+func TestMergeTemplatesSortPatterns(t *testing.T) {
+	loaded := []LoadedTemplate{
+		{Template: Template{Name: "Go"}, Content: "*.test\n# Binaries\n*.exe\n"},
+		{Template: Template{Name: "Node"}, Content: "node_modules/\n.env\n!.env.example\n"},
+	}
+
+	want := "# --- Go ---\n# Binaries\n*.exe\n*.test\n\n\n" +
+		"# --- Node ---\n.env\nnode_modules/\n!.env.example\n"
+	if got := MergeTemplates(loaded, MergeOptions{SortPatternsWithinSection: true}); got != want {
+		t.Errorf("MergeTemplates() = %q, want %q", got, want)
+	}
+}
+
+func TestMergeTemplatesExcludePatterns(t *testing.T) {
+	loaded := []LoadedTemplate{
+		{Template: Template{Name: "Node"}, Content: "# dotenv environment variables file\n.env\nnode_modules/\n  .env.local  \n!.env\n"},
+	}
+	opts := MergeOptions{
+		ExcludePatterns: []string{".env", " .env.local", "# dotenv environment variables file", ""},
+		FooterTemplate:  ".env",
+	}
+
+	want := "# --- Node ---\n# dotenv environment variables file\nnode_modules/\n!.env\n\n# .env\n"
+	if got := MergeTemplates(loaded, opts); got != want {
+		t.Errorf("MergeTemplates() = %q, want %q", got, want)
+	}
+}
+
+func TestInjectAtMarker(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		marker   string
+		content  string
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "replaces marker line",
+			existing: "# manual\n.env\n# ignr:here\n# more manual\nlocal/\n",
+			marker:   "# ignr:here",
+			content:  "# Go\n*.exe\n",
+			want:     "# manual\n.env\n# Go\n*.exe\n# more manual\nlocal/\n",
+		},
+		{
+			name:     "marker with surrounding whitespace",
+			existing: "a\n  # ignr:here  \r\nb\r\n",
+			marker:   "# ignr:here",
+			content:  "*.exe\n",
+			want:     "a\n*.exe\nb\r\n",
+		},
+		{
+			name:     "marker on last line without newline",
+			existing: "a\n# ignr:here",
+			marker:   "# ignr:here",
+			content:  "*.exe\n",
+			want:     "a\n*.exe\n",
+		},
+		{
+			name:     "only first marker is replaced",
+			existing: "# ignr:here\n# ignr:here\n",
+			marker:   "# ignr:here",
+			content:  "*.exe",
+			want:     "*.exe\n# ignr:here\n",
+		},
+		{
+			name:     "marker must match the whole line",
+			existing: "# ignr:here please\n",
+			marker:   "# ignr:here",
+			wantErr:  ErrMarkerNotFound,
+		},
+		{
+			name:     "missing marker",
+			existing: "a\nb\n",
+			marker:   "# ignr:here",
+			wantErr:  ErrMarkerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := InjectAtMarker(tt.existing, tt.marker, tt.content)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("InjectAtMarker() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InjectAtMarker() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("InjectAtMarker() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeTemplatesCanonical(t *testing.T) {
+	goTemplate := LoadedTemplate{Template: Template{Name: "Go"}, Content: "# Binaries\nvendor/  \r\n*.exe\n\n\n*.dll\n"}
+	nodeTemplate := LoadedTemplate{Template: Template{Name: "node"}, Content: "node_modules/\n*.log\t\n"}
+
+	first := MergeTemplates([]LoadedTemplate{nodeTemplate, goTemplate}, MergeOptions{
+		Deduplicate: true,
+		AddHeader:   true,
+		Generator:   "ignr",
+		Version:     "1.0.0",
+		Timestamp:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}.Canonical())
+	second := MergeTemplates([]LoadedTemplate{goTemplate, nodeTemplate}, MergeOptions{
+		Deduplicate: true,
+		AddHeader:   true,
+		Generator:   "ignr",
+		Version:     "1.0.0",
+		Timestamp:   time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+	}.Canonical())
+
+	if first != second {
+		t.Fatalf("canonical output differs between runs:\n%q\n%q", first, second)
+	}
+	want := "# Generated by ignr 1.0.0\n# Templates: Go, node\n\n" +
+		"# --- Go ---\n# Binaries\n*.dll\n*.exe\nvendor/\n\n" +
+		"# --- node ---\n*.log\nnode_modules/\n"
+	if first != want {
+		t.Errorf("canonical output = %q, want %q", first, want)
+	}
+}
+
+func TestAppendNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		addition string
+		prefix   string
+		want     string
+	}{
+		{name: "empty file", existing: "", addition: "# --- Go ---\n*.exe\nvendor/\n", want: "# --- Go ---\n*.exe\nvendor/\n"},
+		{name: "only new rules", existing: "*.exe\n", addition: "# --- Go ---\n*.exe\nvendor/\n", want: "# --- Go ---\nvendor/\n"},
+		{name: "nothing new", existing: "# --- Go ---\n*.exe\nvendor/\n", addition: "# Generated by ignr\n\n# --- Go ---\n*.exe\nvendor/\n", want: ""},
+		{name: "crlf file", existing: "*.exe\r\nvendor/\r\n", addition: "*.exe\nvendor/\n", want: ""},
+		{name: "trailing whitespace", existing: "*.exe  \n\tvendor/\n", addition: "*.exe\nvendor/ \n", want: ""},
+		{name: "custom prefix", existing: "a\n", addition: "; --- X ---\na\nb\n", prefix: ";", want: "; --- X ---\nb\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AppendNew(tt.existing, tt.addition, tt.prefix); got != tt.want {
+				t.Errorf("AppendNew() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
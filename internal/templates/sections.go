@@ -0,0 +1,63 @@
+package templates
+
+import (
+	"regexp"
+	"strings"
+)
+
+// sectionMarker matches the line MergeTemplates writes before each template: "<prefix> --- Name ---",
+// or "<prefix>## Name ##<prefix>" with MergeOptions.SectionHeaders.
+var sectionMarker = regexp.MustCompile(`^(\S{1,3}?)(?: --- (.+) ---|## (.+) ##\S{1,3})$`)
+
+// appendBanner matches the line MergeOptions.AppendBanner writes, which is not a template section.
+var appendBanner = regexp.MustCompile(`^\S{1,3} --- Added by \S+( on \S+)? ---$`)
+
+// Section is one template's block in a generated file.
+type Section struct {
+	Name  string
+	Lines []string
+}
+
+// GeneratedFile is a file written by MergeTemplates, split back into its sections.
+type GeneratedFile struct {
+	// CommentPrefix is the prefix used by the section markers, "#" unless --comment-style was set.
+	CommentPrefix string
+	// Outside holds lines before the first section marker (including any header).
+	Outside  []string
+	Sections []Section
+}
+
+// ParseGeneratedFile splits content into the sections MergeTemplates wrote. Each section runs
+// until the next marker, so lines added after the last one belong to the last section.
+func ParseGeneratedFile(content string) GeneratedFile {
+	file := GeneratedFile{CommentPrefix: DefaultCommentPrefix}
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(content, "\r\n", "\n"), "\n"), "\n")
+
+	for _, line := range lines {
+		trimmed := strings.TrimRight(line, " \t")
+		if match := sectionMarker.FindStringSubmatch(trimmed); match != nil && !appendBanner.MatchString(trimmed) {
+			if len(file.Sections) == 0 {
+				file.CommentPrefix = match[1]
+			}
+			file.Sections = append(file.Sections, Section{Name: match[2] + match[3]})
+			continue
+		}
+		if len(file.Sections) == 0 {
+			file.Outside = append(file.Outside, line)
+			continue
+		}
+		last := &file.Sections[len(file.Sections)-1]
+		last.Lines = append(last.Lines, line)
+	}
+	return file
+}
+
+// IsPattern reports whether line is an ignore pattern rather than a blank line, a comment, or
+// a header or footer line written with the file's comment prefix.
+func (f GeneratedFile) IsPattern(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return false
+	}
+	return f.CommentPrefix == DefaultCommentPrefix || !strings.HasPrefix(trimmed, f.CommentPrefix)
+}
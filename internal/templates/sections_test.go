@@ -0,0 +1,106 @@
+package templates
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseGeneratedFile(t *testing.T) {
+	merged := MergeTemplates([]LoadedTemplate{
+		{Template: Template{Name: "Go"}, Content: "# Binaries\n*.exe\nvendor/\n"},
+		{Template: Template{Name: "Node"}, Content: "node_modules/\n"},
+	}, MergeOptions{AddHeader: true, Generator: "ignr", Timestamp: time.Now()})
+	content := "local.txt\n" + merged + "\n# mine\n.env\n"
+
+	file := ParseGeneratedFile(content)
+
+	if file.CommentPrefix != "#" {
+		t.Errorf("CommentPrefix = %q, want #", file.CommentPrefix)
+	}
+	var names []string
+	for _, section := range file.Sections {
+		names = append(names, section.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"Go", "Node"}) {
+		t.Fatalf("section names = %v, want [Go Node]", names)
+	}
+	if got := patterns(file, file.Outside); !reflect.DeepEqual(got, []string{"local.txt"}) {
+		t.Errorf("patterns outside sections = %v, want [local.txt]", got)
+	}
+	if got := patterns(file, file.Sections[0].Lines); !reflect.DeepEqual(got, []string{"*.exe", "vendor/"}) {
+		t.Errorf("Go patterns = %v, want [*.exe vendor/]", got)
+	}
+	// Lines after the last marker stay in the last section.
+	if got := patterns(file, file.Sections[1].Lines); !reflect.DeepEqual(got, []string{"node_modules/", ".env"}) {
+		t.Errorf("Node patterns = %v, want [node_modules/ .env]", got)
+	}
+}
+
+func TestParseGeneratedFileAppendBanner(t *testing.T) {
+	appended := MergeTemplates([]LoadedTemplate{
+		{Template: Template{Name: "Node"}, Content: "node_modules/\n"},
+	}, MergeOptions{Generator: "ignr", Timestamp: time.Now(), AppendBanner: true})
+	content := "# --- Go ---\n*.exe\n" + appended
+
+	file := ParseGeneratedFile(content)
+
+	var names []string
+	for _, section := range file.Sections {
+		names = append(names, section.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"Go", "Node"}) {
+		t.Errorf("section names = %v, want [Go Node] without the append banner", names)
+	}
+}
+
+func TestParseGeneratedFileSectionHeaders(t *testing.T) {
+	content := MergeTemplates([]LoadedTemplate{
+		{Template: Template{Name: "Go"}, Content: "*.exe\n"},
+		{Template: Template{Name: "Node"}, Content: "node_modules/\n"},
+	}, MergeOptions{AddHeader: true, Generator: "ignr", Timestamp: time.Now(), SectionHeaders: true})
+
+	file := ParseGeneratedFile(content)
+
+	var names []string
+	for _, section := range file.Sections {
+		names = append(names, section.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"Go", "Node"}) {
+		t.Fatalf("section names = %v, want [Go Node]", names)
+	}
+	if got := patterns(file, file.Sections[0].Lines); !reflect.DeepEqual(got, []string{"*.exe"}) {
+		t.Errorf("Go patterns = %v, want [*.exe]", got)
+	}
+}
+
+func TestParseGeneratedFileCommentPrefix(t *testing.T) {
+	content := MergeTemplates([]LoadedTemplate{
+		{Template: Template{Name: "Go"}, Content: "*.exe\n"},
+	}, MergeOptions{AddHeader: true, Generator: "ignr", Timestamp: time.Now(), CommentPrefix: ";", FooterTemplate: "managed by ignr"})
+
+	file := ParseGeneratedFile(content)
+
+	if file.CommentPrefix != ";" {
+		t.Errorf("CommentPrefix = %q, want ;", file.CommentPrefix)
+	}
+	if got := patterns(file, file.Outside); len(got) != 0 {
+		t.Errorf("header lines counted as patterns: %v", got)
+	}
+	if len(file.Sections) != 1 {
+		t.Fatalf("sections = %d, want 1", len(file.Sections))
+	}
+	if got := patterns(file, file.Sections[0].Lines); !reflect.DeepEqual(got, []string{"*.exe"}) {
+		t.Errorf("Go patterns = %v, want [*.exe] without the footer", got)
+	}
+}
+
+func patterns(file GeneratedFile, lines []string) []string {
+	var out []string
+	for _, line := range lines {
+		if file.IsPattern(line) {
+			out = append(out, line)
+		}
+	}
+	return out
+}
@@ -2,8 +2,10 @@
 package templates
 
 import (
+	"fmt"
 	"os"
 	"strings"
+	"unicode"
 )
 
 func DiscoverUserTemplates(userPath string) ([]Template, error) {
@@ -18,7 +20,48 @@ func DiscoverUserTemplates(userPath string) ([]Template, error) {
 		return nil, err
 	}
 
+	result, err := discoverUser(userPath)
+	return result.items, err
+}
+
+// UnnamedUserTemplates returns the paths of files in userPath named exactly ".gitignore".
+// They have no template name, so DiscoverUserTemplates skips them; callers can warn instead.
+func UnnamedUserTemplates(userPath string) ([]string, error) {
+	if strings.TrimSpace(userPath) == "" {
+		return nil, nil
+	}
+	if _, err := os.Stat(userPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	result, err := discoverUser(userPath)
+	return result.unnamed, err
+}
+
+// discoverUser walks userPath once per process, shared by DiscoverUserTemplates and UnnamedUserTemplates.
+func discoverUser(userPath string) (discovery, error) {
 	return discoverTemplates(userPath, SourceUser, func(string) Category {
 		return CategoryUser
 	})
 }
+
+// UserTemplateName checks that name, with or without the .gitignore suffix, is safe to use as
+// a user template file name and returns it without the suffix. Names may hold letters, digits,
+// and "+-_." but must not start with a dot, so they never leave the user template directory.
+func UserTemplateName(name string) (string, error) {
+	name = normalizeName(strings.TrimSpace(name))
+	if name == "" {
+		return "", fmt.Errorf("template name is empty")
+	}
+	if strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid template name %q: must not start with a dot", name)
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("+-_.", r) {
+			return "", fmt.Errorf("invalid template name %q: use letters, digits, and +-_. only", name)
+		}
+	}
+	return name, nil
+}
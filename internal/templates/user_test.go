@@ -3,6 +3,7 @@ package templates
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -162,3 +163,69 @@ func TestDiscoverUserTemplatesExcludesNonGitignoreFiles(t *testing.T) {
 		t.Errorf("DiscoverUserTemplates() = %q, want %q", templates[0].Name, "Custom")
 	}
 }
+
+func TestDiscoverUserTemplatesSkipsBareGitignore(t *testing.T) {
+	userPath := filepath.Join(t.TempDir(), "user-templates")
+	if err := os.MkdirAll(filepath.Join(userPath, "nested"), 0o755); err != nil {
+		t.Fatalf("failed to create user template dir: %v", err)
+	}
+	files := map[string]string{
+		"Custom.gitignore":  "# Custom",
+		".gitignore":        "*.tmp",
+		"nested/.gitignore": "*.bak",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(userPath, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	templates, err := DiscoverUserTemplates(userPath)
+	if err != nil {
+		t.Fatalf("DiscoverUserTemplates() error = %v", err)
+	}
+	if len(templates) != 1 || templates[0].Name != "Custom" {
+		t.Errorf("DiscoverUserTemplates() = %+v, want only Custom", templates)
+	}
+
+	unnamed, err := UnnamedUserTemplates(userPath)
+	if err != nil {
+		t.Fatalf("UnnamedUserTemplates() error = %v", err)
+	}
+	want := []string{filepath.Join(userPath, ".gitignore"), filepath.Join(userPath, "nested", ".gitignore")}
+	if !reflect.DeepEqual(unnamed, want) {
+		t.Errorf("UnnamedUserTemplates() = %v, want %v", unnamed, want)
+	}
+}
+
+func TestUserTemplateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain", input: "Mine", want: "Mine"},
+		{name: "suffix stripped", input: "Mine.gitignore", want: "Mine"},
+		{name: "symbols", input: "C++_v2.local-x", want: "C++_v2.local-x"},
+		{name: "empty", input: "  ", wantErr: true},
+		{name: "only suffix", input: ".gitignore", wantErr: true},
+		{name: "separator", input: "a/b", wantErr: true},
+		{name: "backslash", input: `a\b`, wantErr: true},
+		{name: "parent", input: "..", wantErr: true},
+		{name: "hidden", input: ".env", wantErr: true},
+		{name: "space", input: "my template", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UserTemplateName(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UserTemplateName(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("UserTemplateName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
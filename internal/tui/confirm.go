@@ -55,8 +55,8 @@ func (m confirmModel) Init() tea.Cmd {
 func (m confirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.BackgroundColorMsg:
-		// Initialize global styles instance (compat package handles adaptation)
-		appStyles = newStyles()
+		// Pick the color set that contrasts with the terminal's background.
+		appStyles = newStylesForBackground(msg.Color)
 		return m, nil
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -83,14 +83,23 @@ func (m confirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m confirmModel) View() tea.View {
-	contentWidth := m.width - 4
-	if contentWidth < 40 {
-		contentWidth = 40
-	}
-	if contentWidth > 80 {
-		contentWidth = 80
+	content := m.Content()
+
+	// Center content
+	if m.width > 0 && m.height > 0 {
+		content = lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
 	}
 
+	v := tea.NewView("")
+	v.SetContent(content)
+	v.AltScreen = m.useAltScreen
+	v.WindowTitle = "Confirm Overwrite"
+	return v
+}
+
+func (m confirmModel) Content() string {
+	contentWidth := boxContentWidth(m.width, 80)
+
 	fixedWidth := lipgloss.NewStyle().Width(contentWidth)
 
 	var lines []string
@@ -125,25 +134,14 @@ func (m confirmModel) View() tea.View {
 	// Footer
 	lines = append(lines, fixedWidth.Render(getStyles().FooterStyle.Render("Y confirm • N cancel • Esc cancel")))
 
-	// Wrap in border with AltScreen
+	// Wrap in border
 	containerStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(getStyles().Border).
 		BorderForeground(getStyles().Subtle).
 		Width(contentWidth + 4).
 		Padding(0, 1)
 
-	content := containerStyle.Render(strings.Join(lines, "\n"))
-
-	// Center content
-	if m.width > 0 && m.height > 0 {
-		content = lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
-	}
-
-	v := tea.NewView("")
-	v.SetContent(content)
-	v.AltScreen = m.useAltScreen
-	v.WindowTitle = "Confirm Overwrite"
-	return v
+	return containerStyle.Render(strings.Join(lines, "\n"))
 }
 
 // wrapText wraps text to fit within the specified width, breaking at commas when possible
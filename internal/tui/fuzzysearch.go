@@ -1,11 +1,16 @@
 package tui
 
 import (
+	"strings"
+	"unicode"
+
 	"github.com/sahilm/fuzzy"
+	"go.seanlatimer.dev/ignr/internal/config"
 	"go.seanlatimer.dev/ignr/internal/templates"
+	"golang.org/x/text/unicode/norm"
 )
 
-func FilterTemplates(query string, items []templates.Template) []templates.Template {
+func FilterTemplates(query string, items []templates.Template, mode config.SearchMode) []templates.Template {
 	if query == "" {
 		return items
 	}
@@ -15,15 +20,122 @@ func FilterTemplates(query string, items []templates.Template) []templates.Templ
 		names = append(names, t.Name)
 	}
 
-	matches := fuzzy.FindFrom(query, stringSource(names))
+	matches := matchNames(query, names, mode)
 	filtered := make([]templates.Template, 0, len(matches))
-	for _, match := range matches {
-		filtered = append(filtered, items[match.Index])
+	for _, i := range matches {
+		filtered = append(filtered, items[i])
 	}
 
 	return filtered
 }
 
+// TemplateMatch is a template found by SearchTemplates. Score is the fuzzy match score,
+// higher for closer matches; it is always 0 in substring and prefix modes.
+type TemplateMatch struct {
+	Template templates.Template
+	Score    int
+}
+
+// SearchTemplates is FilterTemplates that also returns each match's score, for scripts.
+func SearchTemplates(query string, items []templates.Template, mode config.SearchMode) []TemplateMatch {
+	names := make([]string, 0, len(items))
+	for _, t := range items {
+		names = append(names, t.Name)
+	}
+
+	ranked := rankNames(query, names, mode)
+	matches := make([]TemplateMatch, 0, len(ranked))
+	for _, r := range ranked {
+		matches = append(matches, TemplateMatch{Template: items[r.index], Score: r.score})
+	}
+	return matches
+}
+
+// rankedName is one match from rankNames.
+type rankedName struct {
+	index int
+	score int
+}
+
+// matchNames returns the indices of names matching query under the given mode.
+// Fuzzy matches are ordered by score; substring and prefix matches keep input order.
+// Unknown modes fall back to fuzzy matching.
+//
+// Query and names are compared after foldForSearch, so accented and unaccented
+// spellings match each other. Indices refer to the original names slice.
+func matchNames(query string, names []string, mode config.SearchMode) []int {
+	ranked := rankNames(query, names, mode)
+	indices := make([]int, 0, len(ranked))
+	for _, r := range ranked {
+		indices = append(indices, r.index)
+	}
+	return indices
+}
+
+// rankNames is matchNames that keeps the fuzzy score of each match; see matchNames.
+func rankNames(query string, names []string, mode config.SearchMode) []rankedName {
+	query = foldForSearch(query)
+	folded := make([]string, len(names))
+	for i, name := range names {
+		folded[i] = foldForSearch(name)
+	}
+	names = folded
+
+	switch mode {
+	case config.SearchModeSubstring, config.SearchModePrefix:
+		needle := strings.ToLower(query)
+		ranked := make([]rankedName, 0, len(names))
+		for i, name := range names {
+			haystack := strings.ToLower(name)
+			if mode == config.SearchModePrefix && strings.HasPrefix(haystack, needle) {
+				ranked = append(ranked, rankedName{index: i})
+			}
+			if mode == config.SearchModeSubstring && strings.Contains(haystack, needle) {
+				ranked = append(ranked, rankedName{index: i})
+			}
+		}
+		return ranked
+	default:
+		matches := fuzzy.FindFrom(query, stringSource(names))
+		ranked := make([]rankedName, 0, len(matches))
+		for _, match := range matches {
+			ranked = append(ranked, rankedName{index: match.Index, score: match.Score})
+		}
+		return ranked
+	}
+}
+
+// foldForSearch decomposes s, drops combining marks such as accents, and recomposes it,
+// so "Café" (precomposed or not) and "Cafe" compare equal. Byte-oriented matchers then see
+// one canonical form instead of several.
+func foldForSearch(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return norm.NFC.String(b.String())
+}
+
+// loadSearchMode reads the configured search mode, defaulting to fuzzy.
+func loadSearchMode() config.SearchMode {
+	cfg, err := config.LoadConfig()
+	if err != nil || cfg.SearchMode == "" {
+		return config.SearchModeFuzzy
+	}
+	return cfg.SearchMode
+}
+
+// loadHiddenCategories returns hidden_categories from config; none when it cannot be read.
+func loadHiddenCategories() []templates.Category {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil
+	}
+	return cfg.HiddenCategories
+}
+
 type stringSource []string
 
 func (s stringSource) Len() int {
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/presets"
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+func searchFixture() []templates.Template {
+	return []templates.Template{
+		{Name: "Go", Path: "/Go.gitignore"},
+		{Name: "Godot", Path: "/Godot.gitignore"},
+		{Name: "Python", Path: "/Python.gitignore"},
+		{Name: "JetBrains", Path: "/Global/JetBrains.gitignore"},
+		{Name: "Node", Path: "/Node.gitignore"},
+	}
+}
+
+func templateNames(items []templates.Template) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+func TestFilterTemplatesModes(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		mode  config.SearchMode
+		want  []string
+	}{
+		{
+			name:  "fuzzy matches scattered characters",
+			query: "pyn",
+			mode:  config.SearchModeFuzzy,
+			want:  []string{"Python"},
+		},
+		{
+			name:  "default mode is fuzzy",
+			query: "pyn",
+			mode:  "",
+			want:  []string{"Python"},
+		},
+		{
+			name:  "substring rejects scattered characters",
+			query: "pyn",
+			mode:  config.SearchModeSubstring,
+			want:  []string{},
+		},
+		{
+			name:  "substring matches inside name",
+			query: "BRAIN",
+			mode:  config.SearchModeSubstring,
+			want:  []string{"JetBrains"},
+		},
+		{
+			name:  "substring keeps input order",
+			query: "o",
+			mode:  config.SearchModeSubstring,
+			want:  []string{"Go", "Godot", "Python", "Node"},
+		},
+		{
+			name:  "prefix matches start of name",
+			query: "go",
+			mode:  config.SearchModePrefix,
+			want:  []string{"Go", "Godot"},
+		},
+		{
+			name:  "prefix rejects inner match",
+			query: "brains",
+			mode:  config.SearchModePrefix,
+			want:  []string{},
+		},
+		{
+			name:  "empty query returns everything",
+			query: "",
+			mode:  config.SearchModePrefix,
+			want:  []string{"Go", "Godot", "Python", "JetBrains", "Node"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := templateNames(FilterTemplates(tt.query, searchFixture(), tt.mode))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterTemplates(%q, %q) = %v, want %v", tt.query, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterTemplatesUnicode(t *testing.T) {
+	// "Résumé" is precomposed (NFC); "Café" uses a combining acute accent (NFD).
+	items := []templates.Template{
+		{Name: "Go"},
+		{Name: "R\u00e9sum\u00e9"},
+		{Name: "Cafe\u0301"},
+		{Name: "Resources"},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		mode  config.SearchMode
+		want  []string
+	}{
+		{name: "accented query, precomposed name", query: "r\u00e9sum\u00e9", mode: config.SearchModeFuzzy, want: []string{"R\u00e9sum\u00e9"}},
+		{name: "unaccented query, precomposed name", query: "resume", mode: config.SearchModeFuzzy, want: []string{"R\u00e9sum\u00e9"}},
+		{name: "precomposed query, decomposed name", query: "caf\u00e9", mode: config.SearchModeFuzzy, want: []string{"Cafe\u0301"}},
+		{name: "unaccented query, decomposed name", query: "cafe", mode: config.SearchModeSubstring, want: []string{"Cafe\u0301"}},
+		{name: "prefix matches accented and plain names", query: "res", mode: config.SearchModePrefix, want: []string{"R\u00e9sum\u00e9", "Resources"}},
+		{name: "fuzzy matches accented and plain names", query: "r\u00e9s", mode: config.SearchModeFuzzy, want: []string{"R\u00e9sum\u00e9", "Resources"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := templateNames(FilterTemplates(tt.query, items, tt.mode))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterTemplates(%q, %q) = %q, want %q", tt.query, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterPresetsModes(t *testing.T) {
+	items := []presets.Preset{
+		{Key: "web-app", Name: "Web App"},
+		{Key: "go-service", Name: "Go Service"},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		mode  config.SearchMode
+		want  []string
+	}{
+		{name: "fuzzy", query: "wbp", mode: config.SearchModeFuzzy, want: []string{"Web App"}},
+		{name: "substring by key", query: "service", mode: config.SearchModeSubstring, want: []string{"Go Service"}},
+		{name: "prefix", query: "web", mode: config.SearchModePrefix, want: []string{"Web App"}},
+		{name: "prefix rejects inner match", query: "app", mode: config.SearchModePrefix, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := filterPresets(tt.query, items, tt.mode)
+			got := make([]string, 0, len(filtered))
+			for _, preset := range filtered {
+				got = append(got, preset.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterPresets(%q, %q) = %v, want %v", tt.query, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
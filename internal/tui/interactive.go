@@ -5,18 +5,29 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/bubbles/v2/list"
 	"charm.land/bubbles/v2/textinput"
 	"charm.land/lipgloss/v2"
+	"go.seanlatimer.dev/ignr/internal/config"
 	"go.seanlatimer.dev/ignr/internal/presets"
 	"go.seanlatimer.dev/ignr/internal/templates"
 )
 
 var ErrCancelled = errors.New("selection cancelled")
 
+// selectorView is the screen the selector is showing.
+type selectorView int
+
+const (
+	selectionView selectorView = iota
+	// confirmSelectionView summarises the selection and target path before returning.
+	confirmSelectionView
+)
+
 type selectorModel struct {
 	all            []templates.Template
 	filtered       []templates.Template
@@ -36,9 +47,58 @@ type selectorModel struct {
 	showingPresets bool
 	index          templates.Index
 	suggested      map[string]bool
+	searchMode     config.SearchMode
+	targetPath     string
+	view           selectorView
+	preview        PreviewFunc
+	previewLines   []string
+	previewOffset  int
+	// showTemplatePreview adds a pane below the list with the highlighted template's content.
+	// templateContent caches what the pane has loaded, keyed by template Path.
+	showTemplatePreview bool
+	templateContent     map[string]string
+	// searchHistory holds this session's committed queries, oldest first. historyPos is the
+	// entry shown by ctrl+p/ctrl+n; len(searchHistory) means none.
+	searchHistory []string
+	historyPos    int
+}
+
+// PreviewFunc renders what confirming selected would write, such as the proposed file
+// or a diff against the existing one. It is shown on the confirmation screen.
+type PreviewFunc func(selected []templates.Template) (string, error)
+
+// SelectorOptions configures ShowInteractiveSelectorWithOptions.
+type SelectorOptions struct {
+	// Preview, when set with a target path, adds the rendered output to the confirmation
+	// screen so it can be reviewed and applied in one session.
+	Preview PreviewFunc
+}
+
+// ShowInteractiveSelector lets the user pick templates. When targetPath is set, confirming
+// shows a summary of the selection and target before returning.
+func ShowInteractiveSelector(items []templates.Template, presetList []presets.Preset, preselectedNames []string, suggestedNames []string, targetPath string) ([]templates.Template, error) {
+	return ShowInteractiveSelectorWithOptions(items, presetList, preselectedNames, suggestedNames, targetPath, SelectorOptions{})
 }
 
-func ShowInteractiveSelector(items []templates.Template, presetList []presets.Preset, preselectedNames []string, suggestedNames []string) ([]templates.Template, error) {
+// ShowInteractiveSelectorWithOptions is ShowInteractiveSelector with a preview of the output
+// on the confirmation screen.
+func ShowInteractiveSelectorWithOptions(items []templates.Template, presetList []presets.Preset, preselectedNames []string, suggestedNames []string, targetPath string, opts SelectorOptions) ([]templates.Template, error) {
+	model := newSelectorModel(items, presetList, preselectedNames, suggestedNames, targetPath)
+	model.preview = opts.Preview
+	program := tea.NewProgram(model)
+	result, err := program.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	final := result.(selectorModel)
+	if final.cancelled {
+		return nil, ErrCancelled
+	}
+	return final.selectedOrder, nil
+}
+
+func newSelectorModel(items []templates.Template, presetList []presets.Preset, preselectedNames []string, suggestedNames []string, targetPath string) selectorModel {
 	presetItems, presetLookup := buildPresetItems(presetList)
 	index := templates.BuildIndex(items)
 	selected, selectedOrder, suggested := buildSelections(index, preselectedNames, suggestedNames)
@@ -56,7 +116,7 @@ func ShowInteractiveSelector(items []templates.Template, presetList []presets.Pr
 	l.SetFilteringEnabled(false)
 	l.SetShowPagination(false)
 
-	model := selectorModel{
+	return selectorModel{
 		all:           items,
 		filtered:      append(presetItems, items...),
 		searchInput:   input,
@@ -68,19 +128,11 @@ func ShowInteractiveSelector(items []templates.Template, presetList []presets.Pr
 		presetLookup:  presetLookup,
 		index:         index,
 		suggested:     suggested,
-	}
-
-	program := tea.NewProgram(model)
-	result, err := program.Run()
-	if err != nil {
-		return nil, err
-	}
+		searchMode:    loadSearchMode(),
+		targetPath:    targetPath,
 
-	final := result.(selectorModel)
-	if final.cancelled {
-		return nil, ErrCancelled
+		templateContent: map[string]string{},
 	}
-	return final.selectedOrder, nil
 }
 
 func (m selectorModel) Init() tea.Cmd {
@@ -88,36 +140,33 @@ func (m selectorModel) Init() tea.Cmd {
 }
 
 func (m selectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	// Any key may move the highlight, so the preview pane loads after every update.
+	if next, ok := model.(selectorModel); ok && next.showTemplatePreview {
+		next.loadHighlighted()
+		return next, cmd
+	}
+	return model, cmd
+}
+
+func (m selectorModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.BackgroundColorMsg:
-		// Initialize global styles instance (compat package handles adaptation)
-		appStyles = newStyles()
+		// Pick the color set that contrasts with the terminal's background.
+		appStyles = newStylesForBackground(msg.Color)
 		return m, nil
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 
-		// Calculate dimensions
-		contentWidth := msg.Width - 4
-		if contentWidth < 40 {
-			contentWidth = 40
-		}
-		if contentWidth > 80 {
-			contentWidth = 80
-		}
-
-		listHeight := msg.Height - 10
-		if listHeight < 5 {
-			listHeight = 5
-		}
-		if listHeight > 20 {
-			listHeight = 20
-		}
-
-		m.searchInput.SetWidth(contentWidth - 4)
-		m.list.SetSize(contentWidth, listHeight)
+		contentWidth := boxContentWidth(msg.Width, 80)
+		m.searchInput.SetWidth(max(contentWidth-4, 1))
+		m.resizeList()
 
 	case tea.KeyMsg:
+		if m.view == confirmSelectionView {
+			return m.updateConfirm(msg)
+		}
 		keyStr := msg.String()
 		key := msg.Key()
 
@@ -137,6 +186,7 @@ func (m selectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "esc":
 			// Layered escape: unfocus -> clear -> cancel
 			if m.searchInput.Focused() {
+				m.rememberQuery()
 				m.searchInput.Blur()
 				return m, nil
 			}
@@ -148,17 +198,52 @@ func (m selectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.cancelled = true
 			return m, tea.Quit
 		case "tab", "ctrl+enter", "ctrl+j":
+			if m.targetPath != "" {
+				if len(m.selectedOrder) == 0 {
+					m.errMessage = "Select at least one template"
+					return m, nil
+				}
+				if m.preview != nil {
+					rendered, err := m.preview(m.selectedOrder)
+					if err != nil {
+						m.errMessage = err.Error()
+						return m, nil
+					}
+					m.previewLines = strings.Split(strings.TrimSuffix(rendered, "\n"), "\n")
+					m.previewOffset = 0
+				}
+				m.errMessage = ""
+				m.rememberQuery()
+				m.searchInput.Blur()
+				m.view = confirmSelectionView
+				return m, nil
+			}
 			m.done = true
 			return m, tea.Quit
 		case "/":
+			m.historyPos = len(m.searchHistory)
 			m.searchInput.Focus()
 			return m, nil
+		case "ctrl+p", "ctrl+n":
+			if m.searchInput.Focused() {
+				m.recallQuery(keyStr == "ctrl+p")
+				return m, nil
+			}
 		case "p":
 			if len(m.presetItems) > 0 && !m.searchInput.Focused() {
 				m.showingPresets = !m.showingPresets
 				m.applyFilter()
 				return m, nil
 			}
+		case "ctrl+a":
+			m.toggleFiltered()
+			return m, nil
+		case "v":
+			if !m.searchInput.Focused() {
+				m.showTemplatePreview = !m.showTemplatePreview
+				m.resizeList()
+				return m, nil
+			}
 		case "enter":
 			if !m.searchInput.Focused() {
 				m.toggleSelection()
@@ -194,6 +279,74 @@ func (m selectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// updateConfirm handles keys on the summary screen: confirm returns the selection,
+// going back restores the selection screen as it was.
+func (m selectorModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.cancelled = true
+		return m, tea.Quit
+	case "enter", "y", "tab", "ctrl+enter", "ctrl+j":
+		m.done = true
+		return m, tea.Quit
+	case "esc", "backspace", "n", "b":
+		m.view = selectionView
+		return m, nil
+	case "up", "k":
+		m.previewOffset = max(m.previewOffset-1, 0)
+	case "down", "j":
+		m.previewOffset = min(m.previewOffset+1, max(len(m.previewLines)-m.previewHeight(), 0))
+	}
+	return m, nil
+}
+
+// templatePreviewHeight is how many content lines the template preview pane shows. The pane
+// always takes this many lines, so moving between short and long templates keeps the layout.
+const templatePreviewHeight = 8
+
+// resizeList fits the list to the window, leaving room for the template preview pane when shown.
+func (m *selectorModel) resizeList() {
+	width := m.list.Width()
+	height := defaultListHeight + 2
+	if m.width > 0 {
+		width = boxContentWidth(m.width, 80)
+		height = min(max(m.height-10, 5), 20)
+	}
+	if m.showTemplatePreview {
+		height = max(height-templatePreviewHeight-2, 3)
+	}
+	m.list.SetSize(width, height)
+}
+
+// loadHighlighted reads the highlighted template into templateContent the first time the
+// preview pane shows it. A file that cannot be read is cached as its error message.
+func (m *selectorModel) loadHighlighted() {
+	current := m.list.SelectedItem()
+	if current == nil {
+		return
+	}
+	item := current.(templateListItem).template
+	if _, isPreset := m.presetLookup[item.Path]; isPreset {
+		return
+	}
+	if _, loaded := m.templateContent[item.Path]; loaded {
+		return
+	}
+	content, err := templates.LoadTemplate(item.Path)
+	if err != nil {
+		content = "Cannot preview: " + err.Error()
+	}
+	m.templateContent[item.Path] = strings.ReplaceAll(content, "\r\n", "\n")
+}
+
+// previewHeight is how many preview lines the confirmation screen shows at once.
+func (m selectorModel) previewHeight() int {
+	if m.height == 0 {
+		return defaultListHeight
+	}
+	return min(max(m.height-14, 3), 20)
+}
+
 func (m selectorModel) View() tea.View {
 	v := tea.NewView("")
 	v.SetContent(m.Content())
@@ -202,16 +355,66 @@ func (m selectorModel) View() tea.View {
 
 func (m selectorModel) Content() string {
 	// Calculate content width
-	contentWidth := m.width - 4
-	if contentWidth < 40 {
-		contentWidth = 40
+	contentWidth := boxContentWidth(m.width, 80)
+
+	fixedWidth := lipgloss.NewStyle().Width(contentWidth)
+
+	var lines []string
+	if m.view == confirmSelectionView {
+		lines = m.confirmLines(fixedWidth)
+	} else {
+		lines = m.selectionLines(fixedWidth, contentWidth)
 	}
-	if contentWidth > 80 {
-		contentWidth = 80
+
+	// Wrap in border
+	containerStyle := lipgloss.NewStyle().
+		Border(getStyles().Border).
+		BorderForeground(getStyles().Subtle).
+		Width(contentWidth + 4).
+		Padding(0, 1)
+
+	return containerStyle.Render(strings.Join(lines, "\n"))
+}
+
+// targetNote marks a target that already exists, since confirming the summary replaces it
+// without a second prompt.
+func targetNote(path string) string {
+	if _, err := os.Stat(path); err != nil {
+		return ""
 	}
+	return " (exists, will be changed)"
+}
 
-	fixedWidth := lipgloss.NewStyle().Width(contentWidth)
+func (m selectorModel) confirmLines(fixedWidth lipgloss.Style) []string {
+	lines := []string{
+		fixedWidth.Render(getStyles().SelectedStyle.Render("Confirm Generation")),
+		"",
+		fixedWidth.Render("Target: " + m.targetPath + targetNote(m.targetPath)),
+		"",
+		fixedWidth.Render(fmt.Sprintf("Templates (%d):", len(m.selectedOrder))),
+	}
+	for _, tmpl := range m.selectedOrder {
+		lines = append(lines, fixedWidth.Render(fmt.Sprintf("  • %s %s", tmpl.Name, getStyles().SubtleStyle.Render("("+tmpl.QualifiedCategory()+")"))))
+	}
+	lines = append(lines, "")
+	if m.preview == nil {
+		lines = append(lines, fixedWidth.Render(getStyles().FooterStyle.Render("Enter confirm • Esc back • Ctrl+C cancel")))
+		return lines
+	}
+
+	// Preview lines are cut rather than wrapped so scrolling moves one file line at a time.
+	contentWidth := boxContentWidth(m.width, 80)
+	end := min(m.previewOffset+m.previewHeight(), len(m.previewLines))
+	for _, line := range m.previewLines[m.previewOffset:end] {
+		lines = append(lines, truncateToWidth(line, contentWidth))
+	}
+	lines = append(lines, fixedWidth.Render(getStyles().SubtleStyle.Render(fmt.Sprintf("Lines %d-%d of %d", m.previewOffset+1, end, len(m.previewLines)))))
+	lines = append(lines, "")
+	lines = append(lines, fixedWidth.Render(getStyles().FooterStyle.Render("↑↓ scroll • Enter apply • Esc back • Ctrl+C cancel")))
+	return lines
+}
 
+func (m selectorModel) selectionLines(fixedWidth lipgloss.Style, contentWidth int) []string {
 	var lines []string
 
 	// Title
@@ -243,6 +446,10 @@ func (m selectorModel) Content() string {
 	// List
 	lines = append(lines, m.list.View())
 	lines = append(lines, "")
+	if m.showTemplatePreview {
+		lines = append(lines, m.templatePreviewLines(fixedWidth, contentWidth)...)
+		lines = append(lines, "")
+	}
 
 	// Error message
 	if m.errMessage != "" {
@@ -251,23 +458,49 @@ func (m selectorModel) Content() string {
 
 	// Footer
 	var footer string
-	if m.searchInput.Focused() {
-		footer = "Type to filter • ↑↓ navigate • Esc done"
+	if m.searchInput.Focused() && len(m.searchHistory) > 0 {
+		footer = "Type to filter • ↑↓ navigate • Ctrl+A toggle all • Ctrl+P/N history • Esc done"
+	} else if m.searchInput.Focused() {
+		footer = "Type to filter • ↑↓ navigate • Ctrl+A toggle all • Esc done"
 	} else if m.searchInput.Value() != "" {
-		footer = "Enter/Space toggle • Tab confirm • / edit search • Esc clear"
+		footer = "Enter/Space toggle • Ctrl+A all • v preview • Tab confirm • / edit search • Esc clear"
 	} else {
-		footer = "Enter/Space toggle • Tab confirm • / search • Esc cancel"
+		footer = "Enter/Space toggle • Ctrl+A all • v preview • Tab confirm • / search • Esc cancel"
 	}
 	lines = append(lines, fixedWidth.Render(getStyles().FooterStyle.Render(footer)))
+	return lines
+}
 
-	// Wrap in border
-	containerStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(getStyles().Subtle).
-		Width(contentWidth + 4).
-		Padding(0, 1)
+// templatePreviewLines renders the preview pane: a title naming the highlighted template, then
+// exactly templatePreviewHeight lines of its content, cut to contentWidth. A preset shows the
+// templates it selects instead.
+func (m selectorModel) templatePreviewLines(fixedWidth lipgloss.Style, contentWidth int) []string {
+	title := "Preview"
+	body := []string{"No template highlighted"}
+	if current := m.list.SelectedItem(); current != nil {
+		item := current.(templateListItem).template
+		if preset, ok := m.presetLookup[item.Path]; ok {
+			title = "Preview: " + preset.Name
+			body = []string{"Templates: " + strings.Join(preset.Templates, ", ")}
+		} else {
+			title = fmt.Sprintf("Preview: %s (%s)", item.Name, item.QualifiedCategory())
+			body = strings.Split(strings.TrimSuffix(m.templateContent[item.Path], "\n"), "\n")
+		}
+	}
+	if len(body) > templatePreviewHeight {
+		hidden := len(body) - templatePreviewHeight + 1
+		body = append(body[:templatePreviewHeight-1:templatePreviewHeight-1], fmt.Sprintf("… %d more lines", hidden))
+	}
 
-	return containerStyle.Render(strings.Join(lines, "\n"))
+	lines := []string{fixedWidth.Render(getStyles().SelectedStyle.Render(truncateToWidth(title, contentWidth)))}
+	for i := range templatePreviewHeight {
+		line := ""
+		if i < len(body) {
+			line = truncateToWidth(body[i], contentWidth)
+		}
+		lines = append(lines, fixedWidth.Render(getStyles().SubtleStyle.Render(line)))
+	}
+	return lines
 }
 
 func (m *selectorModel) toggleSelection() {
@@ -292,15 +525,85 @@ func (m *selectorModel) toggleSelection() {
 	m.list.SetItems(templateListItemsWithPresets(m.filtered, m.selected, m.suggested, m.presetLookup, m.index))
 }
 
+// toggleFiltered selects every template in the filtered list, skipping preset entries. When all
+// of them are already selected it deselects them instead, the way toggling a preset does.
+func (m *selectorModel) toggleFiltered() {
+	visible := make([]templates.Template, 0, len(m.filtered))
+	allSelected := true
+	for _, item := range m.filtered {
+		if _, isPreset := m.presetLookup[item.Path]; isPreset {
+			continue
+		}
+		visible = append(visible, item)
+		if _, exists := m.selected[item.Path]; !exists {
+			allSelected = false
+		}
+	}
+	if len(visible) == 0 {
+		return
+	}
+
+	for _, item := range visible {
+		if allSelected {
+			delete(m.selected, item.Path)
+			m.selectedOrder = removeSelected(m.selectedOrder, item.Path)
+			continue
+		}
+		if _, exists := m.selected[item.Path]; !exists {
+			m.selected[item.Path] = item
+			m.selectedOrder = append(m.selectedOrder, item)
+		}
+	}
+	m.list.SetItems(templateListItemsWithPresets(m.filtered, m.selected, m.suggested, m.presetLookup, m.index))
+}
+
+// rememberQuery adds the current search query to the session history, moving a repeated
+// query to the end instead of storing it twice.
+func (m *selectorModel) rememberQuery() {
+	query := strings.TrimSpace(m.searchInput.Value())
+	if query == "" {
+		return
+	}
+	for i, prev := range m.searchHistory {
+		if prev == query {
+			m.searchHistory = append(m.searchHistory[:i], m.searchHistory[i+1:]...)
+			break
+		}
+	}
+	m.searchHistory = append(m.searchHistory, query)
+	m.historyPos = len(m.searchHistory)
+}
+
+// recallQuery replaces the search input with the previous (older) or next history entry.
+// Moving past the newest entry clears the input.
+func (m *selectorModel) recallQuery(older bool) {
+	if len(m.searchHistory) == 0 {
+		return
+	}
+	if older {
+		m.historyPos = max(m.historyPos-1, 0)
+	} else {
+		m.historyPos = min(m.historyPos+1, len(m.searchHistory))
+	}
+	query := ""
+	if m.historyPos < len(m.searchHistory) {
+		query = m.searchHistory[m.historyPos]
+	}
+	m.searchInput.SetValue(query)
+	m.searchInput.CursorEnd()
+	m.lastQuery = query
+	m.applyFilter()
+}
+
 func (m *selectorModel) applyFilter() {
 	query := m.searchInput.Value()
-	presetFiltered := FilterTemplates(query, m.presetItems)
+	presetFiltered := FilterTemplates(query, m.presetItems, m.searchMode)
 	if m.showingPresets {
 		m.filtered = presetFiltered
 		m.list.SetItems(templateListItemsWithPresets(m.filtered, m.selected, m.suggested, m.presetLookup, m.index))
 		return
 	}
-	templateFiltered := FilterTemplates(query, m.all)
+	templateFiltered := FilterTemplates(query, m.all, m.searchMode)
 	m.filtered = append(presetFiltered, templateFiltered...)
 	m.list.SetItems(templateListItemsWithPresets(m.filtered, m.selected, m.suggested, m.presetLookup, m.index))
 }
@@ -351,6 +654,8 @@ func buildPresetItems(presetList []presets.Preset) ([]templates.Template, map[st
 	return items, lookup
 }
 
+// buildSelections resolves preselected then suggested names against index. The returned order
+// follows preselectedNames, not index order, so editing a preset keeps its stored template order.
 func buildSelections(index templates.Index, preselectedNames []string, suggestedNames []string) (map[string]templates.Template, []templates.Template, map[string]bool) {
 	selected := make(map[string]templates.Template)
 	selectedOrder := make([]templates.Template, 0, len(preselectedNames))
@@ -20,7 +20,7 @@ func TestShowInteractiveSelectorEmpty(t *testing.T) {
 	}
 	
 	// Test with empty template list
-	selected, err := ShowInteractiveSelector([]templates.Template{}, nil, nil, nil)
+	selected, err := ShowInteractiveSelector([]templates.Template{}, nil, nil, nil, "")
 	
 	// Should not error with empty list
 	if err != nil {
@@ -46,7 +46,7 @@ func TestShowInteractiveSelectorBasic(t *testing.T) {
 	
 	// Note: This will fail in non-interactive environments, which is expected
 	// In a full implementation, we'd use teatest to mock the TUI
-	_, err := ShowInteractiveSelector(testTemplates, nil, nil, nil)
+	_, err := ShowInteractiveSelector(testTemplates, nil, nil, nil, "")
 	
 	// In non-interactive environments, this will fail
 	// This is expected behavior
@@ -1,7 +1,9 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
@@ -16,17 +18,35 @@ type viewModel interface {
 	Title() string
 }
 
+// cacheUpdateStatus is the result of the startup check against the remote template repository.
+type cacheUpdateStatus int
+
+const (
+	cacheUpdateUnknown cacheUpdateStatus = iota
+	cacheUpToDate
+	cacheUpdateAvailable
+)
+
+// cacheCheckTimeout bounds the startup remote check so a slow network never delays the app.
+const cacheCheckTimeout = 3 * time.Second
+
 type presetAppState struct {
-	presets   []presets.Preset
-	templates []templates.Template
-	index     templates.Index
+	presets     []presets.Preset
+	templates   []templates.Template
+	visible     []templates.Template // templates without hidden_categories, for selectors
+	index       templates.Index
+	searchMode  config.SearchMode
+	baseDir     string
+	cacheStatus cacheUpdateStatus
 }
 
 type presetAppModel struct {
-	stack     []viewModel
-	width     int
-	height    int
-	state     *presetAppState
+	stack  []viewModel
+	width  int
+	height int
+	state  *presetAppState
+	// checkUpdate queries the remote for cache updates; nil skips the check (offline).
+	checkUpdate func(context.Context) (bool, error)
 }
 
 type pushViewMsg struct {
@@ -41,17 +61,26 @@ type refreshPresetsMsg struct {
 
 type quitAppMsg struct{}
 
-func ShowPresetApp() error {
-	app, err := newPresetAppModel()
+type cacheUpdateMsg struct {
+	status cacheUpdateStatus
+}
+
+// ShowPresetApp runs the preset management TUI. Output paths are resolved relative to baseDir.
+// Unless offline, it checks in the background whether the template cache is behind the remote.
+func ShowPresetApp(baseDir string, offline bool) error {
+	app, err := newPresetAppModel(baseDir)
 	if err != nil {
 		return err
 	}
+	if !offline {
+		app.checkUpdate = cache.CheckForUpdate
+	}
 	program := tea.NewProgram(app)
 	_, err = program.Run()
 	return err
 }
 
-func newPresetAppModel() (presetAppModel, error) {
+func newPresetAppModel(baseDir string) (presetAppModel, error) {
 	presetList, err := presets.ListPresets()
 	if err != nil {
 		return presetAppModel{}, err
@@ -64,9 +93,12 @@ func newPresetAppModel() (presetAppModel, error) {
 
 	index := templates.BuildIndex(items)
 	state := &presetAppState{
-		presets:   presetList,
-		templates: items,
-		index:     index,
+		presets:    presetList,
+		templates:  items,
+		visible:    templates.WithoutCategories(items, loadHiddenCategories()),
+		index:      index,
+		searchMode: loadSearchMode(),
+		baseDir:    baseDir,
 	}
 	root := newUnifiedPresetListView(state)
 
@@ -77,7 +109,7 @@ func newPresetAppModel() (presetAppModel, error) {
 }
 
 func loadAllTemplates() ([]templates.Template, error) {
-	cachePath, err := cache.InitializeCache()
+	cachePath, err := cache.InitializeCache(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -100,17 +132,50 @@ func loadAllTemplates() ([]templates.Template, error) {
 }
 
 func (m presetAppModel) Init() tea.Cmd {
-	return tea.RequestBackgroundColor
+	return tea.Batch(tea.RequestBackgroundColor, checkCacheUpdate(m.checkUpdate))
+}
+
+// checkCacheUpdate runs check off the UI loop with a short timeout. Errors leave the status unknown.
+func checkCacheUpdate(check func(context.Context) (bool, error)) tea.Cmd {
+	if check == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), cacheCheckTimeout)
+		defer cancel()
+		available, err := check(ctx)
+		switch {
+		case err != nil:
+			return cacheUpdateMsg{status: cacheUpdateUnknown}
+		case available:
+			return cacheUpdateMsg{status: cacheUpdateAvailable}
+		default:
+			return cacheUpdateMsg{status: cacheUpToDate}
+		}
+	}
+}
+
+// cacheNotice renders the cache status header, or "" when the status is unknown.
+func (s *presetAppState) cacheNotice() string {
+	switch s.cacheStatus {
+	case cacheUpToDate:
+		return getStyles().SubtleStyle.Render("templates up to date")
+	case cacheUpdateAvailable:
+		return getStyles().WarningStyle.Render("update available (run ignr update)")
+	default:
+		return ""
+	}
 }
 
 func (m presetAppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.BackgroundColorMsg:
-		// Initialize global styles instance (compat package handles adaptation)
-		appStyles = newStyles()
+		// Pick the color set that contrasts with the terminal's background.
+		appStyles = newStylesForBackground(msg.Color)
 		return m, nil
 	case pushViewMsg:
-		m.stack = append(m.stack, msg.view)
+		// Views only see WindowSizeMsg on resize, so size a new view on push.
+		m.stack = append(m.stack, m.sizeView(msg.view))
 		return m, nil
 	case popViewMsg:
 		if len(m.stack) > 1 {
@@ -121,9 +186,16 @@ func (m presetAppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 	case quitAppMsg:
 		return m, tea.Quit
+	case cacheUpdateMsg:
+		m.state.cacheStatus = msg.status
+		return m, nil
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		// Resize views below the current one too, so popping back never shows a stale layout.
+		for i := 0; i < len(m.stack)-1; i++ {
+			m.stack[i] = m.sizeView(m.stack[i])
+		}
 	}
 
 	current := m.currentView()
@@ -149,6 +221,10 @@ func (m presetAppModel) View() tea.View {
 	if provider, ok := current.(interface{ Content() string }); ok {
 		content = provider.Content()
 	}
+	if notice := m.state.cacheNotice(); notice != "" {
+		notice = fitBox(lipgloss.NewStyle(), notice, m.width)
+		content = lipgloss.JoinVertical(lipgloss.Center, notice, content)
+	}
 	if m.width > 0 && m.height > 0 {
 		content = lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
 	}
@@ -159,6 +235,18 @@ func (m presetAppModel) View() tea.View {
 	return v
 }
 
+// sizeView sends the current terminal size to view, if the size is known.
+func (m presetAppModel) sizeView(view viewModel) viewModel {
+	if m.width == 0 && m.height == 0 {
+		return view
+	}
+	updated, _ := view.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	if sized, ok := updated.(viewModel); ok {
+		return sized
+	}
+	return view
+}
+
 func (m presetAppModel) currentView() viewModel {
 	if len(m.stack) == 0 {
 		return nil
@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/presets"
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+func TestCheckCacheUpdate(t *testing.T) {
+	tests := []struct {
+		name      string
+		available bool
+		err       error
+		want      cacheUpdateStatus
+	}{
+		{name: "remote head differs", available: true, want: cacheUpdateAvailable},
+		{name: "remote head matches", available: false, want: cacheUpToDate},
+		{name: "check fails", err: errors.New("network unreachable"), want: cacheUpdateUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := func(ctx context.Context) (bool, error) {
+				if _, ok := ctx.Deadline(); !ok {
+					t.Error("check called without a deadline")
+				}
+				return tt.available, tt.err
+			}
+
+			state := &presetAppState{}
+			m := presetAppModel{state: state}
+			msg := checkCacheUpdate(check)()
+			updated, _ := m.Update(msg)
+
+			app := updated.(presetAppModel)
+			if app.state.cacheStatus != tt.want {
+				t.Errorf("cacheStatus = %v, want %v", app.state.cacheStatus, tt.want)
+			}
+			notice := app.state.cacheNotice()
+			if tt.want == cacheUpdateAvailable && !strings.Contains(notice, "update available") {
+				t.Errorf("cacheNotice() = %q, want update notice", notice)
+			}
+			if tt.want == cacheUpdateUnknown && notice != "" {
+				t.Errorf("cacheNotice() = %q, want no notice when status is unknown", notice)
+			}
+		})
+	}
+}
+
+func TestCheckCacheUpdateOffline(t *testing.T) {
+	if cmd := checkCacheUpdate(nil); cmd != nil {
+		t.Error("checkCacheUpdate(nil) returned a command, want nil when offline")
+	}
+}
+
+func TestEditTemplatesPreservesPresetOrder(t *testing.T) {
+	t.Setenv(config.HomeEnv, t.TempDir())
+
+	items := []templates.Template{
+		{Name: "Go", Category: templates.CategoryRoot, Path: "/Go.gitignore"},
+		{Name: "Node", Category: templates.CategoryRoot, Path: "/Node.gitignore"},
+		{Name: "Python", Category: templates.CategoryRoot, Path: "/Python.gitignore"},
+	}
+	index := templates.BuildIndex(items)
+	stored := []string{"Python", "Go", "Node"}
+	if err := presets.CreatePreset("stack", stored, &index); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+	preset, _, err := presets.FindPreset("stack")
+	if err != nil {
+		t.Fatalf("FindPreset() error = %v", err)
+	}
+
+	state := &presetAppState{templates: items, index: index}
+	view := newEditTemplatesView(state, preset)
+	if _, cmd := view.Update(tea.KeyPressMsg{Code: tea.KeyTab}); cmd == nil {
+		t.Fatal("saving the edit view returned no command")
+	}
+
+	saved, _, err := presets.FindPreset("stack")
+	if err != nil {
+		t.Fatalf("FindPreset() error = %v", err)
+	}
+	if !slices.Equal(saved.Templates, stored) {
+		t.Errorf("templates after unchanged edit = %v, want %v", saved.Templates, stored)
+	}
+}
+
+func TestTemplateSelectViewHidesCategories(t *testing.T) {
+	items := []templates.Template{
+		{Name: "Go", Category: templates.CategoryRoot, Path: "/Go.gitignore"},
+		{Name: "macOS", Category: templates.CategoryGlobal, Path: "/Global/macOS.gitignore"},
+		{Name: "Windows", Category: templates.CategoryGlobal, Path: "/Global/Windows.gitignore"},
+	}
+	state := &presetAppState{
+		templates: items,
+		visible:   templates.WithoutCategories(items, []templates.Category{templates.CategoryGlobal}),
+		index:     templates.BuildIndex(items),
+	}
+	names := func(view templateSelectView) []string {
+		var got []string
+		for _, item := range view.selector.all {
+			got = append(got, item.Name)
+		}
+		return got
+	}
+
+	if got := names(newTemplateSelectView(state, "new", nil)); !slices.Equal(got, []string{"Go"}) {
+		t.Errorf("new preset selector lists %v, want [Go]", got)
+	}
+	preset := &presets.Preset{Key: "mac", Name: "Mac", Templates: []string{"macOS"}}
+	if got := names(newTemplateSelectView(state, "Mac", preset)); !slices.Equal(got, []string{"Go", "macOS"}) {
+		t.Errorf("edit selector lists %v, want [Go macOS] with the preset's hidden template kept", got)
+	}
+}
+
+func TestUsePresetAppliesDefaultsAndLineEnding(t *testing.T) {
+	t.Setenv(config.HomeEnv, t.TempDir())
+	if err := config.SaveConfig(config.Config{DefaultTemplates: []string{"macOS"}, LineEnding: "crlf"}); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	var items []templates.Template
+	for name, content := range map[string]string{"Go": "*.exe\n", "macOS": ".DS_Store\n"} {
+		path := filepath.Join(dir, name+".gitignore")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write template: %v", err)
+		}
+		items = append(items, templates.Template{Name: name, Category: templates.CategoryRoot, Path: path})
+	}
+
+	u := unifiedPresetListView{state: &presetAppState{templates: items, index: templates.BuildIndex(items), baseDir: t.TempDir()}}
+	done, target := u.checkAndUsePreset(presets.Preset{Key: "go", Name: "Go", Templates: []string{"Go"}})
+	if !done {
+		t.Fatalf("checkAndUsePreset() not done: %s", u.errMessage)
+	}
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	content := string(data)
+	if !strings.Contains(content, ".DS_Store\r\n") || !strings.Contains(content, "*.exe\r\n") {
+		t.Errorf("output = %q, want the default macOS template and the preset's Go with CRLF line endings", content)
+	}
+	if strings.Count(content, "\n") != strings.Count(content, "\r\n") {
+		t.Errorf("output = %q, want every line break as CRLF", content)
+	}
+}
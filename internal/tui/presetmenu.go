@@ -18,10 +18,29 @@ func (i presetMenuItem) FilterValue() string { return i.label }
 
 type presetMenuModel struct {
 	list      list.Model
+	width     int
 	cancelled bool
 }
 
 func ShowPresetMenu() (string, error) {
+	program := tea.NewProgram(newPresetMenuModel())
+	result, err := program.Run()
+	if err != nil {
+		return "", err
+	}
+
+	final := result.(presetMenuModel)
+	if final.cancelled {
+		return "", ErrCancelled
+	}
+	selected, ok := final.list.SelectedItem().(presetMenuItem)
+	if !ok {
+		return "", ErrCancelled
+	}
+	return selected.label, nil
+}
+
+func newPresetMenuModel() presetMenuModel {
 	items := []list.Item{
 		presetMenuItem{label: "Create new preset"},
 		presetMenuItem{label: "Edit existing preset"},
@@ -40,22 +59,7 @@ func ShowPresetMenu() (string, error) {
 	l.SetShowPagination(false)
 	l.SetShowHelp(false)
 
-	model := presetMenuModel{list: l}
-	program := tea.NewProgram(model)
-	result, err := program.Run()
-	if err != nil {
-		return "", err
-	}
-
-	final := result.(presetMenuModel)
-	if final.cancelled {
-		return "", ErrCancelled
-	}
-	selected, ok := final.list.SelectedItem().(presetMenuItem)
-	if !ok {
-		return "", ErrCancelled
-	}
-	return selected.label, nil
+	return presetMenuModel{list: l}
 }
 
 func (m presetMenuModel) Init() tea.Cmd {
@@ -65,8 +69,8 @@ func (m presetMenuModel) Init() tea.Cmd {
 func (m presetMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.BackgroundColorMsg:
-		// Initialize global styles instance (compat package handles adaptation)
-		appStyles = newStyles()
+		// Pick the color set that contrasts with the terminal's background.
+		appStyles = newStylesForBackground(msg.Color)
 		// Update list styles now that styles are available
 		m.list.Styles.Title = getStyles().SelectedStyle
 		return m, nil
@@ -79,11 +83,10 @@ func (m presetMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 	case tea.WindowSizeMsg:
-		width := msg.Width - 2
+		m.width = msg.Width
+		// The border takes one column on each side.
+		width := max(msg.Width-2, 1)
 		height := msg.Height - 2
-		if width < 10 {
-			width = 10
-		}
 		if height < len(m.list.Items())+2 {
 			height = len(m.list.Items()) + 2
 		}
@@ -97,10 +100,14 @@ func (m presetMenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m presetMenuModel) View() tea.View {
 	v := tea.NewView("")
-	v.SetContent(getStyles().BorderStyle.Render(m.list.View()))
+	v.SetContent(m.Content())
 	return v
 }
 
+func (m presetMenuModel) Content() string {
+	return fitBox(getStyles().BorderStyle, m.list.View(), m.width)
+}
+
 type presetMenuDelegate struct{}
 
 func (d presetMenuDelegate) Height() int { return 1 }
@@ -15,6 +15,7 @@ type presetNameModel struct {
 	existingKeys map[string]struct{}
 	allowExisting bool
 	errMessage   string
+	width        int
 	done         bool
 	cancelled    bool
 }
@@ -56,8 +57,12 @@ func (m presetNameModel) Init() tea.Cmd {
 func (m presetNameModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.BackgroundColorMsg:
-		// Initialize global styles instance (compat package handles adaptation)
-		appStyles = newStyles()
+		// Pick the color set that contrasts with the terminal's background.
+		appStyles = newStylesForBackground(msg.Color)
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.input.SetWidth(max(msg.Width-boxChrome, 1))
 		return m, nil
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -81,6 +86,12 @@ func (m presetNameModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m presetNameModel) View() tea.View {
+	v := tea.NewView("")
+	v.SetContent(m.Content())
+	return v
+}
+
+func (m presetNameModel) Content() string {
 	value := strings.TrimSpace(m.input.Value())
 	keyPreview := presets.SluggifyName(value)
 	lines := []string{
@@ -92,9 +103,7 @@ func (m presetNameModel) View() tea.View {
 		lines = append(lines, getStyles().ErrorStyle.Render(fmt.Sprintf("Error: %s", m.errMessage)))
 	}
 	lines = append(lines, getStyles().FooterStyle.Render("Enter confirm • Esc cancel"))
-	v := tea.NewView("")
-	v.SetContent(getStyles().BorderStyle.Render(strings.Join(lines, "\n")))
-	return v
+	return fitBox(getStyles().BorderStyle, strings.Join(lines, "\n"), m.width)
 }
 
 func (m presetNameModel) validate(value string) error {
@@ -9,7 +9,7 @@ import (
 	"charm.land/bubbles/v2/list"
 	"charm.land/bubbles/v2/textinput"
 	"charm.land/lipgloss/v2"
-	"github.com/sahilm/fuzzy"
+	"go.seanlatimer.dev/ignr/internal/config"
 	"go.seanlatimer.dev/ignr/internal/presets"
 )
 
@@ -34,6 +34,7 @@ type presetSelectorModel struct {
 	errMessage string
 	width      int
 	height     int
+	searchMode config.SearchMode
 }
 
 func ShowPresetSelector(items []presets.Preset) (presets.Preset, error) {
@@ -52,9 +53,10 @@ func ShowPresetSelector(items []presets.Preset) (presets.Preset, error) {
 	l.SetShowPagination(false)
 
 	model := presetSelectorModel{
-		all:   items,
-		list:  l,
-		input: input,
+		all:        items,
+		list:       l,
+		input:      input,
+		searchMode: loadSearchMode(),
 	}
 
 	program := tea.NewProgram(model)
@@ -81,20 +83,14 @@ func (m presetSelectorModel) Init() tea.Cmd {
 func (m presetSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.BackgroundColorMsg:
-		// Initialize global styles instance (compat package handles adaptation)
-		appStyles = newStyles()
+		// Pick the color set that contrasts with the terminal's background.
+		appStyles = newStylesForBackground(msg.Color)
 		return m, nil
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 
-		contentWidth := msg.Width - 4
-		if contentWidth < 40 {
-			contentWidth = 40
-		}
-		if contentWidth > 60 {
-			contentWidth = 60
-		}
+		contentWidth := boxContentWidth(msg.Width, 60)
 
 		listHeight := msg.Height - 8
 		if listHeight < 5 {
@@ -104,7 +100,7 @@ func (m presetSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			listHeight = 15
 		}
 
-		m.input.SetWidth(contentWidth - 4)
+		m.input.SetWidth(max(contentWidth-4, 1))
 		m.list.SetSize(contentWidth, listHeight)
 
 	case tea.KeyMsg:
@@ -153,7 +149,7 @@ func (m presetSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		query := m.input.Value()
 		if query != m.lastQuery {
 			m.lastQuery = query
-			m.list.SetItems(presetItems(filterPresets(query, m.all)))
+			m.list.SetItems(presetItems(filterPresets(query, m.all, m.searchMode)))
 		}
 	}
 
@@ -169,13 +165,7 @@ func (m presetSelectorModel) View() tea.View {
 }
 
 func (m presetSelectorModel) Content() string {
-	contentWidth := m.width - 4
-	if contentWidth < 40 {
-		contentWidth = 40
-	}
-	if contentWidth > 60 {
-		contentWidth = 60
-	}
+	contentWidth := boxContentWidth(m.width, 60)
 
 	fixedWidth := lipgloss.NewStyle().Width(contentWidth)
 
@@ -219,7 +209,7 @@ func (m presetSelectorModel) Content() string {
 
 	// Wrap in border
 	containerStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(getStyles().Border).
 		BorderForeground(getStyles().Subtle).
 		Width(contentWidth + 4).
 		Padding(0, 1)
@@ -256,7 +246,7 @@ func presetItems(items []presets.Preset) []list.Item {
 	return results
 }
 
-func filterPresets(query string, items []presets.Preset) []presets.Preset {
+func filterPresets(query string, items []presets.Preset, mode config.SearchMode) []presets.Preset {
 	if query == "" {
 		return items
 	}
@@ -266,10 +256,10 @@ func filterPresets(query string, items []presets.Preset) []presets.Preset {
 		entries = append(entries, preset.Name+" "+preset.Key)
 	}
 
-	matches := fuzzy.FindFrom(query, stringSource(entries))
+	matches := matchNames(query, entries, mode)
 	filtered := make([]presets.Preset, 0, len(matches))
-	for _, match := range matches {
-		filtered = append(filtered, items[match.Index])
+	for _, i := range matches {
+		filtered = append(filtered, items[i])
 	}
 	return filtered
 }
@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
 	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/ignorefile"
 	"go.seanlatimer.dev/ignr/internal/presets"
 	"go.seanlatimer.dev/ignr/internal/templates"
 )
@@ -56,7 +58,15 @@ func newTemplateSelectView(state *presetAppState, name string, preset *presets.P
 	input.SetWidth(60)
 	input.Blur() // Start unfocused so navigation works immediately
 
-	l := list.New(templateListItems(state.templates, selected, suggested), templateListDelegate{}, 0, 0)
+	// The preset's own templates stay listed even when their category is hidden.
+	items := slices.Clone(state.visible)
+	for _, tmpl := range selectedOrder {
+		if !slices.ContainsFunc(items, func(item templates.Template) bool { return item.Path == tmpl.Path }) {
+			items = append(items, tmpl)
+		}
+	}
+
+	l := list.New(templateListItems(items, selected, suggested), templateListDelegate{}, 0, 0)
 	l.SetShowTitle(false)
 	l.SetShowStatusBar(false)
 	l.SetShowHelp(false)
@@ -65,8 +75,8 @@ func newTemplateSelectView(state *presetAppState, name string, preset *presets.P
 	l.SetSize(60, defaultListHeight+2)
 
 	selector := selectorModel{
-		all:           state.templates,
-		filtered:      append(presetItems, state.templates...),
+		all:           items,
+		filtered:      append(presetItems, items...),
 		searchInput:   input,
 		list:          l,
 		selected:      selected,
@@ -75,6 +85,7 @@ func newTemplateSelectView(state *presetAppState, name string, preset *presets.P
 		presetLookup:  presetLookup,
 		index:         state.index,
 		suggested:     suggested,
+		searchMode:    state.searchMode,
 	}
 
 	return templateSelectView{
@@ -123,8 +134,15 @@ func (t templateSelectView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			for _, tmpl := range t.selector.selectedOrder {
 				templateNames = append(templateNames, tmpl.Name)
 			}
+			var duplicates []presets.Preset
 			if t.preset == nil {
-				if err := presets.CreatePreset(t.name, templateNames); err != nil {
+				var err error
+				duplicates, err = presets.DuplicateNames(t.name)
+				if err != nil {
+					t.err = err.Error()
+					return t, nil
+				}
+				if err := presets.CreatePreset(t.name, templateNames, &t.state.index); err != nil {
 					t.err = err.Error()
 					return t, nil
 				}
@@ -133,7 +151,7 @@ func (t templateSelectView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if strings.TrimSpace(key) == "" {
 					key = t.preset.Name
 				}
-				if err := presets.EditPreset(key, templateNames); err != nil {
+				if err := presets.EditPreset(key, templateNames, &t.state.index); err != nil {
 					t.err = err.Error()
 					return t, nil
 				}
@@ -147,6 +165,9 @@ func (t templateSelectView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			var successMsg string
 			if t.preset == nil {
 				successMsg = fmt.Sprintf("Created preset %q", t.name)
+				if len(duplicates) > 0 {
+					successMsg += fmt.Sprintf(" (name also used by %q; refer to it by key)", duplicates[0].Key)
+				}
 				// Creating new: pop twice to get back to list view
 				return t, tea.Batch(popView(), popView(), func() tea.Msg {
 					return refreshPresetsMsg{successMessage: successMsg}
@@ -184,6 +205,7 @@ type createNameView struct {
 	state *presetAppState
 	input textinput.Model
 	err   string
+	width int
 }
 
 func (c createNameView) Title() string { return "Create" }
@@ -191,6 +213,10 @@ func (c createNameView) Init() tea.Cmd { return nil }
 
 func (c createNameView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.width = msg.Width
+		c.input.SetWidth(max(msg.Width-boxChrome, 1))
+		return c, nil
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc", "ctrl+c":
@@ -232,7 +258,7 @@ func (c createNameView) Content() string {
 		lines = append(lines, getStyles().ErrorStyle.Render(c.err))
 	}
 	lines = append(lines, getStyles().FooterStyle.Render("Enter continue • Esc back"))
-	return getStyles().BorderStyle.Render(strings.Join(lines, "\n"))
+	return fitBox(getStyles().BorderStyle, strings.Join(lines, "\n"), c.width)
 }
 
 func stateKeys(state *presetAppState) []string {
@@ -268,8 +294,16 @@ func (u *unifiedPresetListView) checkAndUsePreset(preset presets.Preset) (bool,
 		}
 		selected = append(selected, t)
 	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		u.errMessage = err.Error()
+		return false, ""
+	}
+	// Like preset use, default_templates that match nothing are skipped.
+	defaults, _ := ignorefile.ResolveDefaults(cfg.DefaultTemplates, u.state.templates)
+	selected = ignorefile.WithDefaults(defaults, selected)
 
-	target, err := resolveOutputPath()
+	target, err := resolveOutputPath(u.state.baseDir)
 	if err != nil {
 		u.errMessage = err.Error()
 		return false, ""
@@ -291,20 +325,26 @@ func (u *unifiedPresetListView) checkAndUsePreset(preset presets.Preset) (bool,
 }
 
 func (u *unifiedPresetListView) executePreset(target string, selected []templates.Template, presetName string) bool {
-	loaded, err := templates.LoadTemplates(selected)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		u.errMessage = err.Error()
+		return false
+	}
+	ending, err := ignorefile.ResolveLineEnding("", cfg)
 	if err != nil {
 		u.errMessage = err.Error()
 		return false
 	}
 
-	content := templates.MergeTemplates(loaded, templates.MergeOptions{
-		Deduplicate: true,
-		AddHeader:   true,
-		Generator:   "ignr",
-		Version:     "dev",
-		Timestamp:   time.Now(),
-	})
-	if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+	err = ignorefile.Generate(target, selected, templates.MergeOptions{
+		Deduplicate:     true,
+		AddHeader:       true,
+		Generator:       "ignr",
+		Version:         "dev",
+		Timestamp:       time.Now(),
+		ExcludePatterns: cfg.AlwaysExclude,
+	}, ending)
+	if err != nil {
 		u.errMessage = err.Error()
 		return false
 	}
@@ -314,12 +354,22 @@ func (u *unifiedPresetListView) executePreset(target string, selected []template
 	return true
 }
 
-func resolveOutputPath() (string, error) {
+func resolveOutputPath(baseDir string) (string, error) {
+	if baseDir == "" {
+		baseDir = "."
+	}
+	target := filepath.Join(baseDir, ".gitignore")
 	cfg, err := config.LoadConfig()
 	if err == nil && strings.TrimSpace(cfg.DefaultOutput) != "" {
-		return cfg.DefaultOutput, nil
+		target = cfg.DefaultOutput
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(baseDir, target)
+		}
 	}
-	return filepath.Join(".", ".gitignore"), nil
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		return "", fmt.Errorf("output path is a directory: %s", target)
+	}
+	return target, nil
 }
 
 // --- Unified Preset List View ---
@@ -369,13 +419,7 @@ func (v presetTemplatesView) View() tea.View {
 }
 
 func (v presetTemplatesView) Content() string {
-	contentWidth := v.width - 4
-	if contentWidth < 40 {
-		contentWidth = 40
-	}
-	if contentWidth > 80 {
-		contentWidth = 80
-	}
+	contentWidth := boxContentWidth(v.width, 80)
 
 	fixedWidth := lipgloss.NewStyle().Width(contentWidth)
 
@@ -413,7 +457,7 @@ func (v presetTemplatesView) Content() string {
 
 	// Wrap in border
 	containerStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(getStyles().Border).
 		BorderForeground(getStyles().Subtle).
 		Width(contentWidth+4).
 		Padding(0, 1)
@@ -434,9 +478,23 @@ type presetListItem struct {
 }
 
 func (i presetListItem) Title() string { return i.preset.Name }
+
+// Description returns the preset's description with its template count, or just the count.
 func (i presetListItem) Description() string {
-	return fmt.Sprintf("%d templates", len(i.preset.Templates))
+	if i.preset.Description == "" {
+		return fmt.Sprintf("%d templates", len(i.preset.Templates))
+	}
+	return fmt.Sprintf("%s (%d templates)", i.preset.Description, len(i.preset.Templates))
+}
+// label is the list row text: name and template count, then the description if any.
+func (i presetListItem) label() string {
+	label := fmt.Sprintf("%s (%d templates)", i.preset.Name, len(i.preset.Templates))
+	if i.preset.Description != "" {
+		label += " - " + i.preset.Description
+	}
+	return label
 }
+
 func (i presetListItem) FilterValue() string {
 	return i.preset.Name + " " + i.preset.Key
 }
@@ -526,16 +584,10 @@ func (u unifiedPresetListView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		u.height = msg.Height
 
 		// Calculate content width (matching Content() logic)
-		contentWidth := msg.Width - 4
-		if contentWidth < 40 {
-			contentWidth = 40
-		}
-		if contentWidth > 80 {
-			contentWidth = 80
-		}
+		contentWidth := boxContentWidth(msg.Width, 80)
 
 		// Update search input width
-		u.searchInput.SetWidth(contentWidth - 4) // Account for "/ " prefix
+		u.searchInput.SetWidth(max(contentWidth-4, 1)) // Account for "/ " prefix
 
 		// Update list size
 		listHeight := msg.Height - 9
@@ -748,7 +800,7 @@ func (u unifiedPresetListView) handleDeleteConfirmation(msg tea.KeyMsg) (tea.Mod
 
 func (u *unifiedPresetListView) applyFilter() {
 	query := u.searchInput.Value()
-	filtered := filterPresets(query, u.allPresets)
+	filtered := filterPresets(query, u.allPresets, u.state.searchMode)
 	items := buildUnifiedListItems(filtered)
 	u.list.SetItems(items)
 
@@ -760,7 +812,7 @@ func (u *unifiedPresetListView) applyFilter() {
 	if listHeight < 3 {
 		listHeight = 3
 	}
-	u.list.SetSize(50, listHeight)
+	u.list.SetSize(boxContentWidth(u.width, 80), listHeight)
 }
 
 func (u unifiedPresetListView) selectedPreset() *presets.Preset {
@@ -794,13 +846,7 @@ func (u unifiedPresetListView) Content() string {
 		height = 24
 	}
 
-	contentWidth := width - 4 // Account for border and padding
-	if contentWidth < 40 {
-		contentWidth = 40
-	}
-	if contentWidth > 80 {
-		contentWidth = 80 // Cap width for readability
-	}
+	contentWidth := boxContentWidth(width, 80) // Cap width for readability
 
 	// Calculate list height based on terminal height
 	// Reserve: title(1) + blank(1) + search(1) + blank(1) + blank(1) + status(1) + footer(1) + border(2) = 9 lines
@@ -860,7 +906,7 @@ func (u unifiedPresetListView) Content() string {
 				line = getStyles().SubtleStyle.Render(line)
 			}
 		case presetListItem:
-			line = fmt.Sprintf("%s%s (%d templates)", cursor, it.preset.Name, len(it.preset.Templates))
+			line = cursor + it.label()
 			if i == selectedIdx {
 				line = getStyles().SelectedStyle.Render(line)
 			}
@@ -905,7 +951,7 @@ func (u unifiedPresetListView) Content() string {
 
 	// Wrap in border with calculated width
 	containerStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(getStyles().Border).
 		BorderForeground(getStyles().Subtle).
 		Width(contentWidth+4). // Account for border + padding
 		Padding(0, 1)
@@ -955,7 +1001,7 @@ func (d unifiedPresetDelegate) Render(w io.Writer, m list.Model, index int, list
 			line = getStyles().SubtleStyle.Render(line)
 		}
 	case presetListItem:
-		line = fmt.Sprintf("%s%s (%d templates)", cursor, item.preset.Name, len(item.preset.Templates))
+		line = cursor + item.label()
 		if index == m.Index() {
 			line = getStyles().SelectedStyle.Render(line)
 	}
@@ -0,0 +1,285 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/presets"
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+func selectorFixture(targetPath string) selectorModel {
+	items := []templates.Template{
+		{Name: "Go", Category: templates.CategoryRoot, Path: "/Go.gitignore"},
+		{Name: "Node", Category: templates.CategoryRoot, Path: "/Node.gitignore"},
+	}
+	return newSelectorModel(items, nil, []string{"Go"}, nil, targetPath)
+}
+
+func pressKey(t *testing.T, m selectorModel, key tea.KeyPressMsg) (selectorModel, tea.Cmd) {
+	t.Helper()
+	updated, cmd := m.Update(key)
+	return updated.(selectorModel), cmd
+}
+
+func TestSelectorConfirmSummary(t *testing.T) {
+	m := selectorFixture(".gitignore")
+
+	m, cmd := pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyTab})
+	if m.view != confirmSelectionView {
+		t.Fatalf("view after tab = %v, want confirmSelectionView", m.view)
+	}
+	if m.done || cmd != nil {
+		t.Fatal("tab returned before the summary was confirmed")
+	}
+
+	m, _ = pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.view != selectionView {
+		t.Fatalf("view after esc = %v, want selectionView", m.view)
+	}
+	if m.cancelled {
+		t.Fatal("esc on the summary cancelled the selector, want back to selection")
+	}
+	if len(m.selectedOrder) != 1 || m.selectedOrder[0].Name != "Go" {
+		t.Errorf("selection after going back = %v, want [Go]", m.selectedOrder)
+	}
+
+	m, _ = pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyTab})
+	m, cmd = pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	if !m.done || cmd == nil {
+		t.Errorf("enter on summary done = %v, want selector to finish", m.done)
+	}
+}
+
+func TestSelectorConfirmSummaryMarksExistingTarget(t *testing.T) {
+	target := filepath.Join(t.TempDir(), ".gitignore")
+	m := selectorFixture(target)
+	m, _ = pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyTab})
+	if strings.Contains(m.Content(), "exists") {
+		t.Errorf("summary for a new file marks it as existing:\n%s", m.Content())
+	}
+
+	if err := os.WriteFile(target, []byte("old\n"), 0o644); err != nil {
+		t.Fatalf("failed to write target: %v", err)
+	}
+	if !strings.Contains(m.Content(), "(exists, will be changed)") {
+		t.Errorf("summary for an existing file does not say so:\n%s", m.Content())
+	}
+}
+
+func TestSelectorConfirmRequiresSelection(t *testing.T) {
+	items := []templates.Template{{Name: "Go", Path: "/Go.gitignore"}}
+	m := newSelectorModel(items, nil, nil, nil, ".gitignore")
+
+	m, _ = pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyTab})
+	if m.view != selectionView || m.errMessage == "" {
+		t.Errorf("tab with nothing selected view = %v, err = %q; want to stay with an error", m.view, m.errMessage)
+	}
+}
+
+func TestSelectorWithoutTargetSkipsSummary(t *testing.T) {
+	m := selectorFixture("")
+
+	m, cmd := pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyTab})
+	if m.view != selectionView || !m.done || cmd == nil {
+		t.Errorf("tab without target view = %v, done = %v; want immediate return", m.view, m.done)
+	}
+}
+
+func TestSelectorPreviewConfirmApply(t *testing.T) {
+	var previewed []string
+	m := selectorFixture(".gitignore")
+	m.preview = func(selected []templates.Template) (string, error) {
+		previewed = nil
+		for _, tmpl := range selected {
+			previewed = append(previewed, tmpl.Name)
+		}
+		return "--- .gitignore\n+++ .gitignore\n@@ -1 +1 @@\n-old\n+# Go\n", nil
+	}
+
+	m, _ = pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyTab})
+	if m.view != confirmSelectionView {
+		t.Fatalf("view after tab = %v, want confirmSelectionView", m.view)
+	}
+	if len(previewed) != 1 || previewed[0] != "Go" {
+		t.Errorf("preview called with %v, want [Go]", previewed)
+	}
+	content := m.Content()
+	if !strings.Contains(content, "+# Go") || !strings.Contains(content, "Enter apply") {
+		t.Errorf("confirm screen does not show the preview:\n%s", content)
+	}
+
+	m, cmd := pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	if !m.done || m.cancelled || cmd == nil {
+		t.Errorf("enter on preview done = %v, cancelled = %v; want the selection applied", m.done, m.cancelled)
+	}
+}
+
+func TestSelectorPreviewScroll(t *testing.T) {
+	m := selectorFixture(".gitignore")
+	m.preview = func([]templates.Template) (string, error) {
+		return strings.Repeat("line\n", 30), nil
+	}
+	m, _ = pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyTab})
+
+	for range 50 {
+		m, _ = pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyDown})
+	}
+	if want := 30 - m.previewHeight(); m.previewOffset != want {
+		t.Errorf("previewOffset after scrolling past the end = %d, want %d", m.previewOffset, want)
+	}
+	m, _ = pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyUp})
+	if want := 29 - m.previewHeight(); m.previewOffset != want {
+		t.Errorf("previewOffset after scrolling up = %d, want %d", m.previewOffset, want)
+	}
+}
+
+func TestSelectorPreviewError(t *testing.T) {
+	m := selectorFixture(".gitignore")
+	m.preview = func([]templates.Template) (string, error) {
+		return "", errors.New("marker not found")
+	}
+
+	m, _ = pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyTab})
+	if m.view != selectionView || m.errMessage != "marker not found" {
+		t.Errorf("view = %v, err = %q; want to stay on selection with the preview error", m.view, m.errMessage)
+	}
+}
+
+func TestSelectorSearchHistoryRecall(t *testing.T) {
+	m := selectorFixture("")
+	search := func(query string) {
+		m, _ = pressKey(t, m, tea.KeyPressMsg{Code: '/', Text: "/"})
+		m.searchInput.SetValue(query)
+		m, _ = pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyEscape})
+	}
+	search("go")
+	search("node")
+	search("go")
+
+	ctrlP := tea.KeyPressMsg{Code: 'p', Mod: tea.ModCtrl}
+	ctrlN := tea.KeyPressMsg{Code: 'n', Mod: tea.ModCtrl}
+	m, _ = pressKey(t, m, tea.KeyPressMsg{Code: '/', Text: "/"})
+	steps := []struct {
+		key  tea.KeyPressMsg
+		want string
+	}{
+		{ctrlP, "go"},
+		{ctrlP, "node"},
+		{ctrlP, "node"},
+		{ctrlN, "go"},
+		{ctrlN, ""},
+	}
+	for i, step := range steps {
+		m, _ = pressKey(t, m, step.key)
+		if got := m.searchInput.Value(); got != step.want {
+			t.Fatalf("step %d: search input = %q, want %q (history %v)", i, got, step.want, m.searchHistory)
+		}
+	}
+
+	m, _ = pressKey(t, m, ctrlP)
+	if len(m.filtered) != 1 || m.filtered[0].Name != "Go" {
+		t.Errorf("filtered after recalling %q = %v, want [Go]", m.searchInput.Value(), m.filtered)
+	}
+}
+
+func TestSelectorTemplatePreviewPane(t *testing.T) {
+	dir := t.TempDir()
+	goPath := filepath.Join(dir, "Go.gitignore")
+	nodePath := filepath.Join(dir, "Node.gitignore")
+	if err := os.WriteFile(goPath, []byte("# Go\r\n*.exe\r\n"), 0o644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	if err := os.WriteFile(nodePath, []byte(strings.Repeat("node_modules/\n", 20)), 0o644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	items := []templates.Template{
+		{Name: "Go", Category: templates.CategoryRoot, Path: goPath},
+		{Name: "Node", Category: templates.CategoryRoot, Path: nodePath},
+	}
+	m := newSelectorModel(items, nil, nil, nil, "")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	m = updated.(selectorModel)
+	listHeight := m.list.Height()
+
+	v := tea.KeyPressMsg{Code: 'v', Text: "v"}
+	m, _ = pressKey(t, m, v)
+	if !m.showTemplatePreview || m.list.Height() >= listHeight {
+		t.Fatalf("after v showTemplatePreview = %v, list height %d (was %d); want the pane shown and the list shorter", m.showTemplatePreview, m.list.Height(), listHeight)
+	}
+	if _, loaded := m.templateContent[nodePath]; len(m.templateContent) != 1 || loaded {
+		t.Errorf("templateContent = %v, want only the highlighted Go template loaded", m.templateContent)
+	}
+	content := m.Content()
+	if !strings.Contains(content, "Preview: Go (root)") || !strings.Contains(content, "*.exe") {
+		t.Errorf("preview pane does not show the Go template:\n%s", content)
+	}
+	lineCount := strings.Count(content, "\n")
+
+	m, _ = pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyDown})
+	content = m.Content()
+	if !strings.Contains(content, "Preview: Node (root)") || !strings.Contains(content, "… 13 more lines") {
+		t.Errorf("preview pane does not show the cut Node template:\n%s", content)
+	}
+	if got := strings.Count(content, "\n"); got != lineCount {
+		t.Errorf("content has %d lines for Node and %d for Go; want a stable layout", got, lineCount)
+	}
+
+	// Content is cached by path, so later changes to the file are not re-read.
+	if err := os.WriteFile(goPath, []byte("changed\n"), 0o644); err != nil {
+		t.Fatalf("failed to rewrite template: %v", err)
+	}
+	m, _ = pressKey(t, m, tea.KeyPressMsg{Code: tea.KeyUp})
+	if got := m.templateContent[goPath]; got != "# Go\n*.exe\n" {
+		t.Errorf("cached Go content = %q, want the first read with LF line breaks", got)
+	}
+
+	m, _ = pressKey(t, m, v)
+	if m.showTemplatePreview || m.list.Height() != listHeight || strings.Contains(m.Content(), "Preview:") {
+		t.Errorf("after a second v the preview pane is still shown")
+	}
+}
+
+func TestSelectorToggleFiltered(t *testing.T) {
+	items := []templates.Template{
+		{Name: "Go", Category: templates.CategoryRoot, Path: "/Go.gitignore"},
+		{Name: "JetBrains", Category: templates.CategoryGlobal, Path: "/Global/JetBrains.gitignore"},
+		{Name: "JetBrainsRider", Category: templates.CategoryGlobal, Path: "/Global/JetBrainsRider.gitignore"},
+	}
+	presetList := []presets.Preset{{Key: "jetbrains-go", Name: "JetBrains Go", Templates: []string{"Go", "JetBrains"}}}
+	m := newSelectorModel(items, presetList, []string{"JetBrains"}, nil, "")
+	m.searchMode = config.SearchModeSubstring
+	if content := m.Content(); !strings.Contains(content, "Ctrl+A all") {
+		t.Errorf("default footer does not mention Ctrl+A:\n%s", content)
+	}
+	m, _ = pressKey(t, m, tea.KeyPressMsg{Code: '/', Text: "/"})
+	m.searchInput.SetValue("jetbrains")
+	m.applyFilter()
+
+	names := func() []string {
+		var got []string
+		for _, tmpl := range m.selectedOrder {
+			got = append(got, tmpl.Name)
+		}
+		return got
+	}
+	ctrlA := tea.KeyPressMsg{Code: 'a', Mod: tea.ModCtrl}
+	m, _ = pressKey(t, m, ctrlA)
+	if got := names(); !slices.Equal(got, []string{"JetBrains", "JetBrainsRider"}) {
+		t.Errorf("selection after ctrl+a = %v, want the filtered templates without the preset's Go", got)
+	}
+	if item := m.list.Items()[len(m.list.Items())-1].(templateListItem); !item.selected {
+		t.Errorf("list item %s not marked selected after ctrl+a", item.template.Name)
+	}
+
+	m, _ = pressKey(t, m, ctrlA)
+	if got := names(); len(got) != 0 {
+		t.Errorf("selection after a second ctrl+a = %v, want the filtered templates deselected", got)
+	}
+}
@@ -9,6 +9,16 @@ import (
 // Package-level styles instance (nil until initialized)
 var appStyles *Styles
 
+// plainMode draws borders with ASCII characters instead of unicode box drawing.
+var plainMode bool
+
+// SetPlain switches interactive views to ASCII-only borders, for terminals that
+// render box-drawing characters poorly.
+func SetPlain(plain bool) {
+	plainMode = plain
+	appStyles = nil
+}
+
 // Styles holds all application styles using terminal default colors
 type Styles struct {
 	Primary   color.Color
@@ -18,6 +28,9 @@ type Styles struct {
 	Error     color.Color
 	Subtle    color.Color
 
+	// Border is the border drawn around views.
+	Border lipgloss.Border
+
 	BorderStyle      lipgloss.Style
 	SelectedStyle    lipgloss.Style
 	SearchInputStyle lipgloss.Style
@@ -31,59 +44,116 @@ type Styles struct {
 	SuccessStyle     lipgloss.Style
 }
 
-// newStyles creates a new Styles instance using terminal default colors (NoColor)
-// All colors use NoColor{} which means "use terminal's default colors"
-func newStyles() *Styles {
+// palette is the set of colors a Styles is built from.
+type palette struct {
+	primary, secondary, success, warning, error, subtle color.Color
+}
+
+// Palettes for light and dark terminal backgrounds. Each keeps enough contrast with its
+// background for subtle text to stay readable.
+var (
+	lightPalette = palette{
+		primary:   lipgloss.Color("#5A3FC0"),
+		secondary: lipgloss.Color("#0B6E7A"),
+		success:   lipgloss.Color("#1A7F37"),
+		warning:   lipgloss.Color("#9A6700"),
+		error:     lipgloss.Color("#CF222E"),
+		subtle:    lipgloss.Color("#6E7781"),
+	}
+	darkPalette = palette{
+		primary:   lipgloss.Color("#B4A2FF"),
+		secondary: lipgloss.Color("#5FD7E6"),
+		success:   lipgloss.Color("#56D364"),
+		warning:   lipgloss.Color("#E3B341"),
+		error:     lipgloss.Color("#FF7B72"),
+		subtle:    lipgloss.Color("#8B949E"),
+	}
+)
+
+// newStyles creates the startup Styles, used until the terminal reports its background color.
+// Every color is NoColor{}, the terminal's default; once the background is known,
+// newStylesForBackground replaces these with the light or dark palette. Borders are rounded,
+// or ASCII-only when plain is set (see SetPlain).
+func newStyles(plain bool) *Styles {
 	// Use NoColor{} everywhere - this tells lipgloss to use the terminal's default colors
 	// The terminal itself will provide the colors based on its theme configuration
 	noColor := lipgloss.NoColor{}
+	return buildStyles(palette{noColor, noColor, noColor, noColor, noColor, noColor}, plain)
+}
+
+// newStylesForBackground creates styles with colors chosen for contrast against bg, the
+// background color the terminal reported. A nil bg is treated as dark. Borders follow plainMode.
+func newStylesForBackground(bg color.Color) *Styles {
+	if isDarkBackground(bg) {
+		return buildStyles(darkPalette, plainMode)
+	}
+	return buildStyles(lightPalette, plainMode)
+}
+
+// isDarkBackground reports whether bg has an HSL lightness below one half.
+func isDarkBackground(bg color.Color) bool {
+	if bg == nil {
+		return true
+	}
+	r, g, b, _ := bg.RGBA()
+	return max(r, g, b)+min(r, g, b) < 0xffff
+}
+
+// buildStyles creates the Styles for colors p. When plain is set, borders use ASCII characters only.
+func buildStyles(p palette, plain bool) *Styles {
+	border := lipgloss.RoundedBorder()
+	if plain {
+		border = lipgloss.ASCIIBorder()
+	}
 
 	return &Styles{
-		Primary:   noColor,
-		Secondary: noColor,
-		Success:   noColor,
-		Warning:   noColor,
-		Error:     noColor,
-		Subtle:    noColor,
+		Primary:   p.primary,
+		Secondary: p.secondary,
+		Success:   p.success,
+		Warning:   p.warning,
+		Error:     p.error,
+		Subtle:    p.subtle,
+
+		Border: border,
 
 		BorderStyle: lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(noColor),
+			Border(border).
+			BorderForeground(p.subtle),
 
 		SelectedStyle: lipgloss.NewStyle().
-			Foreground(noColor).
+			Foreground(p.primary).
 			Bold(true),
 
 		SearchInputStyle: lipgloss.NewStyle().
-			Foreground(noColor),
+			Foreground(p.primary),
 
 		FooterStyle: lipgloss.NewStyle().
-			Foreground(noColor).
+			Foreground(p.subtle).
 			Italic(true),
 
 		SubtleStyle: lipgloss.NewStyle().
-			Foreground(noColor),
+			Foreground(p.subtle),
 
 		PresetBadgeStyle: lipgloss.NewStyle().
-			Foreground(noColor).
+			Foreground(p.secondary).
 			Bold(true),
 
 		UserBadgeStyle: lipgloss.NewStyle().
-			Foreground(noColor),
+			Foreground(p.secondary),
 
 		SuggestedStyle: lipgloss.NewStyle().
-			Foreground(noColor),
+			Foreground(p.success),
 
 		ErrorStyle: lipgloss.NewStyle().
-			Foreground(noColor).
+			Foreground(p.error).
 			Bold(true),
 
 		WarningStyle: lipgloss.NewStyle().
-			Foreground(noColor).
+			Foreground(p.warning).
 			Bold(true),
 
 		SuccessStyle: lipgloss.NewStyle().
-			Foreground(noColor),
+			Foreground(p.success),
 	}
 }
 
@@ -91,7 +161,31 @@ func newStyles() *Styles {
 func getStyles() *Styles {
 	if appStyles == nil {
 		// Initialize styles (compat package will handle background detection)
-		return newStyles()
+		return newStyles(plainMode)
 	}
 	return appStyles
 }
+
+// boxChrome is the columns a rounded border with horizontal padding of 1 adds around content.
+const boxChrome = 4
+
+// defaultContentWidth is the content width used before the terminal size is known.
+const defaultContentWidth = 40
+
+// boxContentWidth returns the content width of a bordered view in a terminal termWidth
+// columns wide, capped at maxWidth. Narrow terminals shrink the box instead of overflowing it.
+func boxContentWidth(termWidth, maxWidth int) int {
+	if termWidth <= 0 {
+		return min(defaultContentWidth, maxWidth)
+	}
+	return max(min(termWidth-boxChrome, maxWidth), 1)
+}
+
+// fitBox renders content with style, wrapping it to termWidth when the box would be wider.
+func fitBox(style lipgloss.Style, content string, termWidth int) string {
+	rendered := style.Render(content)
+	if termWidth > 0 && lipgloss.Width(rendered) > termWidth {
+		rendered = style.Width(termWidth).Render(content)
+	}
+	return rendered
+}
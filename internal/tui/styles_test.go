@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"charm.land/bubbles/v2/list"
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"go.seanlatimer.dev/ignr/internal/presets"
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+func TestBoxContentWidth(t *testing.T) {
+	tests := []struct {
+		termWidth int
+		maxWidth  int
+		want      int
+	}{
+		{termWidth: 0, maxWidth: 80, want: defaultContentWidth},
+		{termWidth: 0, maxWidth: 30, want: 30},
+		{termWidth: 20, maxWidth: 80, want: 16},
+		{termWidth: 300, maxWidth: 80, want: 80},
+		{termWidth: 3, maxWidth: 80, want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := boxContentWidth(tt.termWidth, tt.maxWidth); got != tt.want {
+			t.Errorf("boxContentWidth(%d, %d) = %d, want %d", tt.termWidth, tt.maxWidth, got, tt.want)
+		}
+	}
+}
+
+func TestNewStylesForBackground(t *testing.T) {
+	light := newStylesForBackground(lipgloss.Color("#FAFAFA"))
+	dark := newStylesForBackground(lipgloss.Color("#1E1E2E"))
+
+	if light.SubtleStyle.GetForeground() == dark.SubtleStyle.GetForeground() {
+		t.Errorf("subtle foreground = %v on both backgrounds, want different colors", light.SubtleStyle.GetForeground())
+	}
+	if light.SelectedStyle.GetForeground() == dark.SelectedStyle.GetForeground() {
+		t.Errorf("selected foreground = %v on both backgrounds, want different colors", light.SelectedStyle.GetForeground())
+	}
+	if _, ok := dark.SelectedStyle.GetForeground().(lipgloss.NoColor); ok {
+		t.Error("selected foreground is NoColor, want a color once the background is known")
+	}
+	if got := newStylesForBackground(nil).Subtle; got != dark.Subtle {
+		t.Errorf("nil background subtle = %v, want the dark palette's %v", got, dark.Subtle)
+	}
+}
+
+func resizeFixtureState() *presetAppState {
+	items := []templates.Template{
+		{Name: "Go", Category: templates.CategoryRoot},
+		{Name: "Node", Category: templates.CategoryRoot},
+		{Name: "VisualStudioCode", Category: templates.CategoryGlobal},
+	}
+	return &presetAppState{
+		presets: []presets.Preset{
+			{Name: "A preset with a rather long descriptive name", Key: "long", Templates: []string{"Go", "Node", "VisualStudioCode"}},
+		},
+		templates: items,
+		index:     templates.BuildIndex(items),
+	}
+}
+
+// contentOf sends a resize to model and returns its rendered content.
+func contentOf(t *testing.T, model tea.Model, width, height int) string {
+	t.Helper()
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	provider, ok := updated.(interface{ Content() string })
+	if !ok {
+		t.Fatalf("%T does not provide Content()", updated)
+	}
+	return provider.Content()
+}
+
+func TestViewsFitTerminalWidth(t *testing.T) {
+	state := resizeFixtureState()
+	preset := state.presets[0]
+	longPath := "/home/user/projects/" + strings.Repeat("nested/", 10) + ".gitignore"
+
+	withDeleteConfirm := newUnifiedPresetListView(state)
+	withDeleteConfirm.deleteConfirmPreset = &preset
+
+	models := map[string]tea.Model{
+		"preset list":       newUnifiedPresetListView(state),
+		"delete confirm":    withDeleteConfirm,
+		"preset templates":  newPresetTemplatesView(state, preset),
+		"create name":       newCreateNameView(state),
+		"create templates":  newCreateTemplatesView(state, "web"),
+		"edit templates":    newEditTemplatesView(state, preset),
+		"template selector": newSelectorModel(state.templates, state.presets, []string{"Go"}, nil, longPath),
+		"overwrite confirm": confirmModel{path: longPath, templates: state.templates},
+		"preset menu":       newPresetMenuModel(),
+		"preset name":       presetNameModel{prompt: "Preset name:", input: textinput.New()},
+		"preset selector":   presetSelectorModel{all: state.presets, list: list.New(presetItems(state.presets), presetSelectorDelegate{}, 50, 10)},
+	}
+	sizes := []struct{ width, height int }{{20, 5}, {40, 10}, {80, 24}, {250, 80}}
+
+	for name, model := range models {
+		for _, size := range sizes {
+			t.Run(fmt.Sprintf("%s %dx%d", name, size.width, size.height), func(t *testing.T) {
+				content := contentOf(t, model, size.width, size.height)
+				if got := lipgloss.Width(content); got > size.width {
+					t.Errorf("rendered width = %d, want at most %d\n%s", got, size.width, content)
+				}
+			})
+		}
+	}
+}
+
+func TestPresetAppSizesPushedViews(t *testing.T) {
+	state := resizeFixtureState()
+	var m tea.Model = presetAppModel{stack: []viewModel{newUnifiedPresetListView(state)}, state: state}
+
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 30, Height: 12})
+	m, _ = m.Update(pushViewMsg{view: newPresetTemplatesView(state, state.presets[0])})
+
+	app := m.(presetAppModel)
+	view, ok := app.currentView().(presetTemplatesView)
+	if !ok {
+		t.Fatalf("current view = %T, want presetTemplatesView", app.currentView())
+	}
+	if view.width != 30 || view.height != 12 {
+		t.Errorf("pushed view size = %dx%d, want 30x12", view.width, view.height)
+	}
+	if got := lipgloss.Width(view.Content()); got > 30 {
+		t.Errorf("pushed view rendered width = %d, want at most 30", got)
+	}
+}
+
+func TestPlainModeUsesASCIIBorders(t *testing.T) {
+	SetPlain(true)
+	defer SetPlain(false)
+
+	state := resizeFixtureState()
+	views := map[string]interface{ Content() string }{
+		"preset list":       newUnifiedPresetListView(state),
+		"preset templates":  newPresetTemplatesView(state, state.presets[0]).(presetTemplatesView),
+		"create name":       newCreateNameView(state).(createNameView),
+		"template selector": newSelectorModel(state.templates, nil, nil, nil, ""),
+		"overwrite confirm": confirmModel{path: ".gitignore", templates: state.templates},
+	}
+
+	for name, view := range views {
+		t.Run(name, func(t *testing.T) {
+			content := view.Content()
+			for _, r := range content {
+				// U+2500–U+257F is the Box Drawing block, which includes rounded corners.
+				if r >= 0x2500 && r <= 0x257F {
+					t.Fatalf("plain content contains box-drawing character %q:\n%s", r, content)
+				}
+			}
+			if !strings.HasPrefix(content, "+-") {
+				t.Errorf("plain content does not start with an ASCII border:\n%s", content)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.seanlatimer.dev/ignr/internal/cache"
+)
+
+func newCacheCommand(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and manage the template cache",
+	}
+
+	cmd.AddCommand(newCacheStatusCommand(opts), newCacheClearCommand(opts))
+	return cmd
+}
+
+func newCacheStatusCommand(opts *Options) *cobra.Command {
+	var jsonOutput bool
+	var compact bool
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show whether the template cache is initialized, where it lives, and how old it is",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := cache.GetStatus()
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOutput {
+				return writeJSON(out, status, compact)
+			}
+
+			if !status.Initialized {
+				_, _ = fmt.Fprintf(out, "Cache not initialized (run ignr init)\nPath: %s\n", status.Path)
+				return nil
+			}
+			_, _ = fmt.Fprintf(out, "Cache initialized\nPath: %s\n", status.Path)
+			_, _ = fmt.Fprintf(out, "HEAD: %s\n", status.HeadCommit)
+			_, _ = fmt.Fprintf(out, "Last modified: %s\n", status.LastModified.Local().Format(time.RFC3339))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the status as JSON")
+	cmd.Flags().BoolVar(&compact, "compact", false, "With --json, print the JSON on one line")
+
+	return cmd
+}
+
+func newCacheClearCommand(opts *Options) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Delete the cloned template repository",
+		Long:  "Delete the cloned template repository. Config, presets, and user templates are kept.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cachePath, err := cache.GetCachePath(cache.DefaultSource)
+			if err != nil {
+				return err
+			}
+
+			if !force {
+				confirm, err := confirmPrompt(cmd, fmt.Sprintf("Delete template cache at %s?", cachePath))
+				if err != nil {
+					return err
+				}
+				if !confirm {
+					infof(cmd, opts, "Cancelled.")
+					return nil
+				}
+			}
+
+			cachePath, existed, err := cache.ClearCache()
+			if err != nil {
+				return err
+			}
+			if !existed {
+				infof(cmd, opts, "Cache not initialized; nothing to clear at %s", cachePath)
+				return nil
+			}
+			infof(cmd, opts, "Cleared cache at %s", cachePath)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Skip the confirmation prompt")
+
+	return cmd
+}
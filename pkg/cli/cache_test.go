@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.seanlatimer.dev/ignr/internal/cache"
+)
+
+func TestCacheStatusNotInitialized(t *testing.T) {
+	cleanup := setupUpdateTest(t)
+	defer cleanup()
+
+	wantPath, err := cache.GetCachePath(cache.DefaultSource)
+	if err != nil {
+		t.Fatalf("GetCachePath() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		args  []string
+		check func(t *testing.T, out string)
+	}{
+		{
+			name: "text",
+			args: []string{"cache", "status"},
+			check: func(t *testing.T, out string) {
+				if !strings.Contains(out, "Cache not initialized") || !strings.Contains(out, "Path: "+wantPath) {
+					t.Errorf("output = %q, want not-initialized message with path %s", out, wantPath)
+				}
+			},
+		},
+		{
+			name: "json",
+			args: []string{"cache", "status", "--json"},
+			check: func(t *testing.T, out string) {
+				var got map[string]any
+				if err := json.Unmarshal([]byte(out), &got); err != nil {
+					t.Fatalf("output is not JSON: %v\n%s", err, out)
+				}
+				if got["initialized"] != false || got["path"] != wantPath {
+					t.Errorf("status = %v, want initialized=false path=%s", got, wantPath)
+				}
+				if _, ok := got["last_modified"]; ok {
+					t.Errorf("status = %v, want no last_modified for a missing cache", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := NewRootCommand(&Options{})
+			root.SetArgs(tt.args)
+			var stdout bytes.Buffer
+			root.SetOut(&stdout)
+			root.SetErr(&stdout)
+			if err := root.Execute(); err != nil {
+				t.Fatalf("cache status error = %v", err)
+			}
+			tt.check(t, stdout.String())
+		})
+	}
+}
+
+func TestCacheClear(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		input     string
+		wantClear bool
+	}{
+		{name: "confirmed", args: []string{"cache", "clear"}, input: "y\n", wantClear: true},
+		{name: "declined", args: []string{"cache", "clear"}, input: "n\n", wantClear: false},
+		{name: "force", args: []string{"cache", "clear", "--force"}, wantClear: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup, cachePath := setupListTest(t)
+			defer cleanup()
+
+			configDir := filepath.Dir(filepath.Dir(cachePath))
+			kept := []string{filepath.Join(configDir, "config.json"), filepath.Join(configDir, "presets.yaml")}
+			for _, path := range kept {
+				if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+					t.Fatalf("failed to write %s: %v", path, err)
+				}
+			}
+
+			root := NewRootCommand(&Options{})
+			root.SetArgs(tt.args)
+			root.SetIn(strings.NewReader(tt.input))
+			var stdout bytes.Buffer
+			root.SetOut(&stdout)
+			root.SetErr(&stdout)
+			if err := root.Execute(); err != nil {
+				t.Fatalf("%v error = %v", tt.args, err)
+			}
+
+			_, err := os.Stat(cachePath)
+			if cleared := os.IsNotExist(err); cleared != tt.wantClear {
+				t.Errorf("cache removed = %v, want %v (output %q)", cleared, tt.wantClear, stdout.String())
+			}
+			for _, path := range kept {
+				if _, err := os.Stat(path); err != nil {
+					t.Errorf("%s was removed: %v", filepath.Base(path), err)
+				}
+			}
+		})
+	}
+}
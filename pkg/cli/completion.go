@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+func newCompletionCommand(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Print a shell completion script",
+		Long: `Print a completion script for your shell. Template names, preset keys, and config keys
+complete on Tab once it is loaded, e.g.:
+
+  bash:        source <(ignr completion bash)
+  zsh:         ignr completion zsh > "${fpath[1]}/_ignr"
+  fish:        ignr completion fish | source
+  powershell:  ignr completion powershell | Out-String | Invoke-Expression`,
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			case "powershell":
+				return root.GenPowerShellCompletionWithDesc(out)
+			}
+			return fmt.Errorf("unsupported shell: %s", args[0])
+		},
+	}
+}
+
+// completeTemplateArgs completes every positional arg with template names not already given.
+func completeTemplateArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	items, err := completionTemplates()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return remainingTemplateNames(items, args, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionTemplates returns the cached and user templates for shell completion. Unlike
+// discoverAllTemplates it never clones, so pressing Tab cannot start a download; without a
+// cache only user templates are offered.
+func completionTemplates() ([]templates.Template, error) {
+	items, err := cachedTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	userPath, err := config.GetUserTemplatePath()
+	if err != nil {
+		return nil, err
+	}
+	userItems, err := templates.DiscoverUserTemplates(userPath)
+	if err != nil {
+		return nil, err
+	}
+	return append(items, userItems...), nil
+}
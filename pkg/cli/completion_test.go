@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"github.com/spf13/cobra"
+	"go.seanlatimer.dev/ignr/internal/presets"
+)
+
+func TestCompletionCommand(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		t.Run(shell, func(t *testing.T) {
+			root := NewRootCommand(&Options{})
+			root.SetArgs([]string{"completion", shell})
+			var stdout bytes.Buffer
+			root.SetOut(&stdout)
+			if err := root.Execute(); err != nil {
+				t.Fatalf("completion %s error = %v", shell, err)
+			}
+			if !strings.Contains(stdout.String(), "ignr") {
+				t.Errorf("completion %s printed no script for ignr", shell)
+			}
+		})
+	}
+
+	root := NewRootCommand(&Options{})
+	root.SetArgs([]string{"completion", "tcsh"})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	if err := root.Execute(); err == nil {
+		t.Error("completion tcsh expected an error for an unsupported shell")
+	}
+}
+
+func TestGenerateCompletesTemplates(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	cmd := newGenerateCommand(&Options{})
+
+	tests := []struct {
+		name       string
+		args       []string
+		toComplete string
+		want       []string
+	}{
+		{name: "all templates", want: []string{"Go", "Node", "Python"}},
+		{name: "prefix", toComplete: "g", want: []string{"Go"}},
+		{name: "skips typed", args: []string{"go"}, want: []string{"Node", "Python"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, directive := cmd.ValidArgsFunction(cmd, tt.args, tt.toComplete)
+			if directive != cobra.ShellCompDirectiveNoFileComp {
+				t.Errorf("ValidArgsFunction() directive = %v, want NoFileComp", directive)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidArgsFunction(%v, %q) = %v, want %v", tt.args, tt.toComplete, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateCompletionNeverClones(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	cachePath := filepath.Join(xdg.ConfigHome, "ignr", "cache", "github-gitignore")
+	if err := os.RemoveAll(cachePath); err != nil {
+		t.Fatalf("failed to remove cache: %v", err)
+	}
+
+	cmd := newGenerateCommand(&Options{})
+	got, directive := cmd.ValidArgsFunction(cmd, nil, "")
+	if directive != cobra.ShellCompDirectiveNoFileComp || len(got) != 0 {
+		t.Errorf("ValidArgsFunction() without a cache = %v, %v; want no names", got, directive)
+	}
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Errorf("completion created the cache (stat error %v)", err)
+	}
+}
+
+func TestPresetUseCompletesKeys(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	for _, name := range []string{"web", "backend"} {
+		if err := presets.CreatePreset(name, []string{"Go"}, nil); err != nil {
+			t.Fatalf("CreatePreset(%s) error = %v", name, err)
+		}
+	}
+
+	cmd := newPresetUseCommand(&Options{})
+	got, _ := cmd.ValidArgsFunction(cmd, nil, "w")
+	if !reflect.DeepEqual(got, []string{"web"}) {
+		t.Errorf("ValidArgsFunction(w) = %v, want [web]", got)
+	}
+	if got, _ := cmd.ValidArgsFunction(cmd, []string{"web"}, ""); len(got) != 0 {
+		t.Errorf("ValidArgsFunction after a key = %v, want nothing", got)
+	}
+}
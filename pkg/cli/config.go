@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.seanlatimer.dev/ignr/internal/config"
+)
+
+func newConfigCommand(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Read and change settings in config.json",
+		Long: "Read and change settings in config.json. Keys match the file's field names: " +
+			strings.Join(config.Keys(), ", ") + ".",
+	}
+
+	cmd.AddCommand(newConfigGetCommand(opts), newConfigSetCommand(opts), newConfigPathCommand(opts))
+	return cmd
+}
+
+func newConfigGetCommand(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print a config value; lists are comma-separated and unset values print empty",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return err
+			}
+			value, err := cfg.Get(args[0])
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), value)
+			return nil
+		},
+	}
+
+	cmd.ValidArgsFunction = completeConfigKey
+	return cmd
+}
+
+func newConfigSetCommand(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Change a config value",
+		Long: "Change a config value and save config.json. Booleans take true or false, lists are " +
+			"comma-separated, and an empty value resets the key to its default.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return err
+			}
+			if err := cfg.Set(args[0], args[1]); err != nil {
+				return err
+			}
+			if err := config.SaveConfig(cfg); err != nil {
+				return err
+			}
+			value, _ := cfg.Get(args[0])
+			infof(cmd, opts, "Set %s = %s", strings.TrimSpace(args[0]), value)
+			return nil
+		},
+	}
+
+	cmd.ValidArgsFunction = completeConfigKey
+	return cmd
+}
+
+func newConfigPathCommand(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "path",
+		Short: "Print where config.json is read from",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.GetConfigPath()
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), path)
+			return nil
+		},
+	}
+}
+
+func completeConfigKey(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var matches []string
+	for _, key := range config.Keys() {
+		if strings.HasPrefix(key, toComplete) {
+			matches = append(matches, key)
+		}
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
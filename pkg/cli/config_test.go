@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.seanlatimer.dev/ignr/internal/config"
+)
+
+func runConfigCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	root := NewRootCommand(&Options{})
+	root.SetArgs(append([]string{"config"}, args...))
+	var stdout bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetErr(&bytes.Buffer{})
+	err := root.Execute()
+	return stdout.String(), err
+}
+
+func TestConfigCommand(t *testing.T) {
+	cleanup := setupUpdateTest(t)
+	defer cleanup()
+
+	out, err := runConfigCommand(t, "set", "default_output", "ignore.txt")
+	if err != nil {
+		t.Fatalf("config set error = %v", err)
+	}
+	if out != "Set default_output = ignore.txt\n" {
+		t.Errorf("config set output = %q", out)
+	}
+
+	out, err = runConfigCommand(t, "get", "default_output")
+	if err != nil {
+		t.Fatalf("config get error = %v", err)
+	}
+	if out != "ignore.txt\n" {
+		t.Errorf("config get output = %q, want ignore.txt", out)
+	}
+
+	path, err := config.GetConfigPath()
+	if err != nil {
+		t.Fatalf("GetConfigPath() error = %v", err)
+	}
+	out, err = runConfigCommand(t, "path")
+	if err != nil {
+		t.Fatalf("config path error = %v", err)
+	}
+	if out != path+"\n" {
+		t.Errorf("config path output = %q, want %q", out, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if !strings.Contains(string(data), "{\n  \"default_output\": \"ignore.txt\"") {
+		t.Errorf("config.json = %q, want indented JSON", data)
+	}
+
+	if _, err := runConfigCommand(t, "set", "default-output", "x"); err == nil || !strings.Contains(err.Error(), "valid keys: default_output") {
+		t.Errorf("config set with bad key error = %v, want unknown key listing valid keys", err)
+	}
+}
+
+func TestConfigFlagAndEnv(t *testing.T) {
+	cleanup := setupUpdateTest(t)
+	defer cleanup()
+	t.Cleanup(func() { config.SetConfigPath("") })
+
+	envPath := filepath.Join(t.TempDir(), "env.json")
+	flagPath := filepath.Join(t.TempDir(), "flag.json")
+	if err := os.WriteFile(envPath, []byte(`{"default_output": "from-env"}`), 0o644); err != nil {
+		t.Fatalf("write env config: %v", err)
+	}
+	if err := os.WriteFile(flagPath, []byte(`{"default_output": "from-flag"}`), 0o644); err != nil {
+		t.Fatalf("write flag config: %v", err)
+	}
+
+	out, err := runConfigCommand(t, "get", "default_output")
+	if err != nil || out != "\n" {
+		t.Errorf("config get without override = %q, %v; want empty", out, err)
+	}
+
+	t.Setenv(config.ConfigEnv, envPath)
+	out, err = runConfigCommand(t, "get", "default_output")
+	if err != nil || out != "from-env\n" {
+		t.Errorf("config get with %s = %q, %v; want from-env", config.ConfigEnv, out, err)
+	}
+
+	out, err = runConfigCommand(t, "--config", flagPath, "get", "default_output")
+	if err != nil || out != "from-flag\n" {
+		t.Errorf("config get with --config = %q, %v; want from-flag", out, err)
+	}
+
+	if _, err := runConfigCommand(t, "--config", flagPath, "set", "search_mode", "prefix"); err != nil {
+		t.Fatalf("config set with --config error = %v", err)
+	}
+	data, err := os.ReadFile(flagPath)
+	if err != nil || !strings.Contains(string(data), `"search_mode": "prefix"`) {
+		t.Errorf("flag config = %q, %v; want search_mode saved there", data, err)
+	}
+}
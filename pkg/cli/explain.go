@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+// sectionStats is the pattern count of one template section of a generated file.
+type sectionStats struct {
+	Name     string
+	Patterns int
+}
+
+func newExplainCommand(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "explain [file]",
+		Short: "Show pattern counts per template section of a generated file",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output := ""
+			if len(args) > 0 {
+				output = args[0]
+			}
+			target, err := resolveOutputPath(opts.BaseDir(), output)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(target)
+			if err != nil {
+				return fmt.Errorf("read %s: %w", target, err)
+			}
+
+			file := templates.ParseGeneratedFile(string(data))
+			if len(file.Sections) == 0 {
+				return fmt.Errorf("no ignr template sections found in %s", target)
+			}
+
+			items, err := explainTemplates(cmd, opts)
+			if err != nil {
+				return err
+			}
+			stats, custom := explainSections(file, items)
+
+			total := custom
+			rows := make([][]string, 0, len(stats)+2)
+			for _, s := range stats {
+				rows = append(rows, []string{s.Name, strconv.Itoa(s.Patterns)})
+				total += s.Patterns
+			}
+			rows = append(rows, []string{"custom", strconv.Itoa(custom)}, []string{"Total", strconv.Itoa(total)})
+			writeTable(cmd.OutOrStdout(), []string{"Section", "Patterns"}, rows, 0)
+			return nil
+		},
+	}
+}
+
+// explainTemplates returns the cached and user templates without touching the network.
+// Without a cache it returns only user templates, after a warning.
+func explainTemplates(cmd *cobra.Command, opts *Options) ([]templates.Template, error) {
+	var items []templates.Template
+	cachePath, err := prepareCache(cmd, opts, false, true)
+	switch {
+	case errors.Is(err, errCacheOffline):
+		if err := warn(cmd, opts, "template cache not initialized; patterns added inside template sections are counted with the template"); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	default:
+		if items, err = templates.DiscoverTemplates(cachePath); err != nil {
+			return nil, err
+		}
+	}
+
+	userPath, err := config.GetUserTemplatePath()
+	if err != nil {
+		return nil, err
+	}
+	userItems, err := templates.DiscoverUserTemplates(userPath)
+	if err != nil {
+		return nil, err
+	}
+	return append(items, userItems...), nil
+}
+
+// explainSections counts the patterns in each section of file. Patterns outside every section,
+// or not part of the section's template, are counted as custom. Sections whose template cannot
+// be found keep all of their patterns.
+func explainSections(file templates.GeneratedFile, items []templates.Template) ([]sectionStats, int) {
+	index := templates.BuildIndex(items)
+	custom := 0
+	for _, line := range file.Outside {
+		if file.IsPattern(line) {
+			custom++
+		}
+	}
+
+	stats := make([]sectionStats, 0, len(file.Sections))
+	for _, section := range file.Sections {
+		known := templatePatterns(index, section.Name)
+		count := 0
+		for _, line := range section.Lines {
+			if !file.IsPattern(line) {
+				continue
+			}
+			if _, ok := known[strings.TrimSpace(line)]; known != nil && !ok {
+				custom++
+				continue
+			}
+			count++
+		}
+		stats = append(stats, sectionStats{Name: section.Name, Patterns: count})
+	}
+	return stats, custom
+}
+
+// templatePatterns returns the trimmed lines of the named template, or nil when it is unknown.
+func templatePatterns(index templates.Index, name string) map[string]struct{} {
+	t, ok := templates.FindTemplate(index, name)
+	if !ok {
+		return nil
+	}
+	loaded, err := templates.LoadTemplates([]templates.Template{t})
+	if err != nil || len(loaded) == 0 {
+		return nil
+	}
+	lines := strings.Split(strings.ReplaceAll(loaded[0].Content, "\r\n", "\n"), "\n")
+	known := make(map[string]struct{}, len(lines))
+	for _, line := range lines {
+		known[strings.TrimSpace(line)] = struct{}{}
+	}
+	return known
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestExplainCommand(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	root := NewRootCommand(&Options{})
+	root.SetArgs([]string{"-C", dir, "generate", "--no-interactive", "Go", "Node"})
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("generate error = %v", err)
+	}
+
+	target := filepath.Join(dir, ".gitignore")
+	file, err := os.OpenFile(target, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("failed to open .gitignore: %v", err)
+	}
+	if _, err := file.WriteString("\n# my additions\nlocal/\n*.tmp\n"); err != nil {
+		t.Fatalf("failed to append custom tail: %v", err)
+	}
+	_ = file.Close()
+
+	root = NewRootCommand(&Options{})
+	root.SetArgs([]string{"-C", dir, "explain"})
+	var stdout bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetErr(&buf)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("explain error = %v", err)
+	}
+
+	got := regexp.MustCompile(` +`).ReplaceAllString(stdout.String(), " ")
+	want := "Section Patterns\nGo 2\nNode 2\ncustom 2\nTotal 6\n"
+	if got != want {
+		t.Errorf("explain output = %q, want %q", got, want)
+	}
+}
+
+func TestExplainCommandNoSections(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*.log\n"), 0o644); err != nil {
+		t.Fatalf("failed to write .gitignore: %v", err)
+	}
+
+	root := NewRootCommand(&Options{})
+	root.SetArgs([]string{"-C", dir, "explain"})
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "no ignr template sections") {
+		t.Errorf("explain error = %v, want no sections error", err)
+	}
+}
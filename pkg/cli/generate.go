@@ -3,36 +3,144 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"go.seanlatimer.dev/ignr/internal/cache"
 	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/history"
+	"go.seanlatimer.dev/ignr/internal/ignorefile"
 	"go.seanlatimer.dev/ignr/internal/presets"
+	"go.seanlatimer.dev/ignr/internal/remote"
 	"go.seanlatimer.dev/ignr/internal/templates"
 	"go.seanlatimer.dev/ignr/internal/tui"
 )
 
 func newGenerateCommand(opts *Options) *cobra.Command {
 	var output string
+	var outputDir string
 	var appendMode bool
+	var mergeMode bool
 	var noHeader bool
 	var force bool
 	var noInteractive bool
 	var suggest bool
+	var autoUpdate bool
+	var noAutoUpdate bool
+	var offline bool
+	var commentStyle string
+	var noSuggestNetwork bool
+	var footer string
+	var showHidden bool
+	var printPath bool
+	var sortLines bool
+	var only string
+	var reportPath string
+	var ignorePolicy bool
+	var canonical bool
+	var injectAt string
+	var interactiveConfirm bool
+	var appendSectionHeader bool
+	var noTimestamp bool
+	var listURL string
+	var fromStdin bool
+	var personalCategory string
+	var lineEnding string
+	var printTemplates bool
+	var excludes []string
+	var sections bool
+	var selectFrom string
+	var dryRun bool
+	var noDefaults bool
 
 	cmd := &cobra.Command{
 		Use:   "generate [template1 template2...]",
 		Short: "Generate a .gitignore from templates",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cachePath, err := cache.InitializeCache()
+			if err := templates.ValidateCommentPrefix(commentStyle); err != nil {
+				return err
+			}
+			if injectAt != "" && appendMode {
+				return fmt.Errorf("--inject-at cannot be used with --append")
+			}
+			if mergeMode && (appendMode || injectAt != "") {
+				return fmt.Errorf("--merge cannot be used with --append or --inject-at")
+			}
+			// --merge appends too; it only drops the rules the file already has.
+			appendMode = appendMode || mergeMode
+
+			if listURL != "" {
+				if offline || noSuggestNetwork {
+					return fmt.Errorf("--list-url needs the network; it cannot be used with --offline")
+				}
+				data, err := remote.Fetch(cmd.Context(), listURL)
+				if err != nil {
+					return err
+				}
+				names := remote.ParseList(data)
+				if len(names) == 0 {
+					return fmt.Errorf("no template names found at %s", listURL)
+				}
+				args = append(args, names...)
+			}
+			if dryRun && selectFrom == "" {
+				return fmt.Errorf("--dry-run requires --select-from")
+			}
+			var listEntries []remote.ListEntry
+			if selectFrom != "" {
+				if !filepath.IsAbs(selectFrom) {
+					selectFrom = filepath.Join(opts.BaseDir(), selectFrom)
+				}
+				data, err := os.ReadFile(selectFrom)
+				if err != nil {
+					return fmt.Errorf("read template list: %w", err)
+				}
+				listEntries = remote.ParseListEntries(data)
+				if len(listEntries) == 0 {
+					return fmt.Errorf("no template names found in %s", selectFrom)
+				}
+				for _, entry := range listEntries {
+					args = append(args, entry.Name)
+				}
+			}
+			if fromStdin {
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("read stdin: %w", err)
+				}
+				names := strings.Fields(string(data))
+				if len(names) == 0 {
+					return fmt.Errorf("no template names on stdin")
+				}
+				args = append(args, names...)
+				noInteractive = true
+			}
+
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return err
+			}
+			update := (autoUpdate || cfg.AutoUpdateOnGenerate) && !noAutoUpdate
+			ending, err := ignorefile.ResolveLineEnding(lineEnding, cfg)
 			if err != nil {
 				return err
 			}
 
+			cachePath, err := prepareCache(cmd, opts, update, offline || noSuggestNetwork)
+			if err != nil {
+				// Suggestions need no cache, but named templates can only be generated from one.
+				if suggest && len(args) == 0 && errors.Is(err, errCacheOffline) {
+					return printSuggestions(cmd, opts.BaseDir())
+				}
+				return err
+			}
+			debugf(cmd, opts, "template cache: %s", cachePath)
+
 			items, err := templates.DiscoverTemplates(cachePath)
 			if err != nil {
 				return err
@@ -45,7 +153,14 @@ func newGenerateCommand(opts *Options) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if err := warnUnnamedTemplates(cmd, opts, userPath); err != nil {
+				return err
+			}
+			logDiscovered(cmd, opts, items, userItems, userPath)
 			items = append(items, userItems...)
+			if dryRun {
+				return checkTemplateList(cmd, opts, selectFrom, listEntries, items)
+			}
 
 			presetList, err := presets.ListPresets()
 			if err != nil {
@@ -54,7 +169,7 @@ func newGenerateCommand(opts *Options) *cobra.Command {
 
 			suggested := []string{}
 			if suggest && len(args) == 0 && !noInteractive {
-				detected, err := presets.DetectFiles(".")
+				detected, err := presets.DetectFiles(opts.BaseDir())
 				if err != nil {
 					return err
 				}
@@ -64,61 +179,409 @@ func newGenerateCommand(opts *Options) *cobra.Command {
 				}
 			}
 
-			selected, interactiveUsed, err := selectTemplates(args, items, presetList, suggested, noInteractive)
+			visible := items
+			if strings.TrimSpace(only) != "" {
+				// An explicit category is shown even if hidden_categories lists it.
+				items = templates.OnlyCategory(items, only)
+				if len(items) == 0 {
+					return fmt.Errorf("no templates in category: %s", only)
+				}
+				visible = items
+			} else if !showHidden {
+				visible = templates.WithoutCategories(items, cfg.HiddenCategories)
+			}
+
+			if output, err = outputInDir(outputDir, output); err != nil {
+				return err
+			}
+			target, err := resolveOutputPath(opts.BaseDir(), output)
+			if err != nil {
+				return err
+			}
+			debugf(cmd, opts, "output: %s", target)
+			if target == stdoutTarget && (appendMode || injectAt != "" || reportPath != "") {
+				return fmt.Errorf("--output - cannot be used with --append, --merge, --inject-at, or --report")
+			}
+			if personalCategory != "" && (target == stdoutTarget || interactiveConfirm) {
+				return fmt.Errorf("--personal cannot be used with --output - or --interactive-confirm")
+			}
+			if commentStyle != templates.DefaultCommentPrefix && (gitReadsFile(target) || personalCategory != "") {
+				return fmt.Errorf("--comment-style %s cannot be used for .gitignore or .git/info/exclude: git only treats # as a comment", commentStyle)
+			}
+
+			mergeOptions := templates.MergeOptions{
+				Deduplicate:               true,
+				AddHeader:                 !noHeader,
+				Generator:                 "ignr",
+				Version:                   Version,
+				Timestamp:                 time.Now(),
+				CommentPrefix:             commentStyle,
+				FooterTemplate:            footer,
+				SortPatternsWithinSection: sortLines,
+				ExcludePatterns:           policyExcludes(cfg, ignorePolicy),
+				SectionHeaders:            sections,
+			}
+			if canonical {
+				mergeOptions = mergeOptions.Canonical()
+			}
+			mergeOptions.OmitTimestamp = mergeOptions.OmitTimestamp || noTimestamp
+			mergeOptions.AppendBanner = appendMode && appendSectionHeader
+			var defaults []templates.Template
+			if !noDefaults {
+				if defaults, err = resolveDefaultTemplates(cmd, opts, cfg.DefaultTemplates, items); err != nil {
+					return err
+				}
+			}
+			var preview tui.PreviewFunc
+			if interactiveConfirm {
+				preview = confirmPreview(target, defaults, excludedPaths(items, excludes), mergeOptions, appendMode, mergeMode, injectAt)
+			}
+
+			selected, interactiveUsed, err := selectTemplates(args, items, visible, presetList, suggested, noInteractive, target, preview)
 			if err != nil {
 				if errors.Is(err, tui.ErrCancelled) {
 					return nil
 				}
 				return err
 			}
+			selected = ignorefile.WithDefaults(defaults, selected)
+			if selected, err = excludeTemplates(cmd, opts, selected, items, excludes); err != nil {
+				return err
+			}
 			if len(selected) == 0 {
 				return fmt.Errorf("no templates selected")
 			}
+			if printTemplates {
+				printSelectedTemplates(cmd, selected)
+			}
+			if personalCategory != "" {
+				var personal []templates.Template
+				if selected, personal, err = splitPersonal(selected, personalCategory); err != nil {
+					return err
+				}
+				excludePath, err := writePersonal(cmd, opts.BaseDir(), personal, mergeOptions, appendMode, force, interactiveUsed)
+				if err != nil {
+					if errors.Is(err, tui.ErrCancelled) {
+						return nil
+					}
+					return err
+				}
+				reportGenerated(cmd, opts, excludePath, len(personal), printPath)
+				if len(selected) == 0 {
+					return nil
+				}
+			}
 
 			loaded, err := templates.LoadTemplates(selected)
 			if err != nil {
 				return err
 			}
-
-			target, err := resolveOutputPath(output)
-			if err != nil {
+			if mergeMode {
+				mergeOptions = mergeModeOptions(target, mergeOptions)
+			}
+			content := templates.MergeTemplates(loaded, mergeOptions)
+			if target == stdoutTarget {
+				_, err := io.WriteString(cmd.OutOrStdout(), ignorefile.ConvertLineEndings(content, ending))
 				return err
 			}
 
-			content := templates.MergeTemplates(loaded, templates.MergeOptions{
-				Deduplicate: true,
-				AddHeader:   !noHeader,
-				Generator:   "ignr",
-				Version:     Version,
-				Timestamp:   time.Now(),
-			})
+			switch {
+			case injectAt != "":
+				content, err = injectIntoFile(target, injectAt, content)
+				if err != nil {
+					return err
+				}
+			case interactiveUsed:
+				// The selector's summary screen already confirmed writing to target.
+			default:
+				if err := handleExistingOutput(cmd, target, appendMode, force, interactiveUsed, selected); err != nil {
+					if errors.Is(err, tui.ErrCancelled) {
+						return nil
+					}
+					return err
+				}
+			}
 
-			if err := handleExistingOutput(cmd, target, appendMode, force, interactiveUsed, selected); err != nil {
-				if errors.Is(err, tui.ErrCancelled) {
+			// Stop before touching the file if Ctrl-C arrived while templates were merged.
+			if err := cmd.Context().Err(); err != nil {
+				return err
+			}
+			if mergeMode {
+				if content, err = mergeNewRules(target, content, commentStyle); err != nil {
+					return err
+				}
+				if content == "" {
+					infof(cmd, opts, "%s already has every rule from the selected templates", target)
 					return nil
 				}
-				return err
 			}
-
-			if err := writeOutput(target, content, appendMode, force); err != nil {
+			before, _ := os.ReadFile(target)
+			if err := ignorefile.Write(target, content, appendMode, ending); err != nil {
 				return err
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Generated %s with %d templates\n", target, len(selected))
+			reportGenerated(cmd, opts, target, len(selected), printPath)
+			if reportPath != "" {
+				return writeReport(reportPath, target, len(selected), before, commentStyle)
+			}
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file path (default: .gitignore)")
+	cmd.ValidArgsFunction = completeTemplateArgs
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file path, or - for stdout (default: .gitignore)")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "Write the output file into this directory, creating it if needed")
 	cmd.Flags().BoolVar(&appendMode, "append", false, "Append to existing file instead of overwrite")
+	cmd.Flags().BoolVar(&mergeMode, "merge", false, "Append only the rules the existing file does not already have")
+	cmd.Flags().BoolVar(&appendSectionHeader, "append-section-header", true, "With --append, start the appended content with an \"Added by ignr on <date>\" banner")
+	cmd.Flags().StringVar(&lineEnding, "line-ending", "", "Line ending of the written file: lf or crlf (default: line_ending in config, else lf)")
+	cmd.Flags().BoolVar(&noTimestamp, "no-timestamp", false, "Leave the date out of the header and append banner")
+	cmd.Flags().StringVar(&personalCategory, "personal", "", "Write the selected templates in this category to .git/info/exclude and the rest to the output file")
+	cmd.Flags().StringVar(&injectAt, "inject-at", "", "Replace this marker line in the existing output file with the generated content (e.g. \"# ignr:here\")")
 	cmd.Flags().BoolVar(&noHeader, "no-header", false, "Skip generator header")
 	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing file without prompt")
 	cmd.Flags().BoolVar(&noInteractive, "no-interactive", false, "Disable interactive selection")
+	cmd.Flags().BoolVar(&interactiveConfirm, "interactive-confirm", false, "After interactive selection, preview the file (or a diff against the existing one) and apply it from the selector")
+	cmd.Flags().StringVar(&listURL, "list-url", "", "Fetch template names from an https URL (one or more per line) and add them to the arguments")
+	cmd.Flags().StringVar(&selectFrom, "select-from", "", "Read template names from a list file (one or more per line, # comments allowed) and add them to the arguments")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "With --select-from, only check that every name in the list resolves; nothing is written")
+	cmd.Flags().BoolVar(&fromStdin, "stdin", false, "Read template names (whitespace or newline separated) from stdin and add them to the arguments; implies --no-interactive")
 	cmd.Flags().BoolVar(&suggest, "suggest", false, "Suggest templates based on repo contents")
+	cmd.Flags().BoolVar(&autoUpdate, "auto-update", false, "Update the template cache before generating")
+	cmd.Flags().BoolVar(&noAutoUpdate, "no-auto-update", false, "Skip the configured cache auto-update")
+	cmd.Flags().BoolVar(&offline, "offline", false, "Never touch the network; fail if the cache is missing")
+	cmd.Flags().BoolVar(&noSuggestNetwork, "no-suggest-network", false, "Keep --suggest fully local; never clone or update the cache (implies --offline)")
+	cmd.Flags().BoolVar(&printPath, "print-path", false, "Print only the path of the written file")
+	cmd.Flags().BoolVar(&printTemplates, "print-templates", false, "Print the resolved template names to stderr, one per line, before writing")
+	cmd.Flags().StringArrayVar(&excludes, "exclude", nil, "Drop this template from the selection (repeatable)")
+	cmd.Flags().BoolVar(&noDefaults, "no-defaults", false, "Skip the default_templates from config for this run")
+	cmd.Flags().BoolVar(&showHidden, "show-hidden", false, "Include categories hidden by hidden_categories in config in the selector")
+	cmd.Flags().BoolVar(&canonical, "canonical", false, "Byte-stable output: sort sections and lines, omit the timestamp, and normalize whitespace")
+	cmd.Flags().BoolVar(&ignorePolicy, "ignore-policy", false, "Keep patterns listed in always_exclude in config")
+	cmd.Flags().StringVar(&reportPath, "report", "", "Append path, template_count, and changed as key=value lines to this file (e.g. $GITHUB_OUTPUT)")
+	cmd.Flags().StringVar(&only, "only", "", "Resolve templates only within this category (e.g. Global or community/JavaScript)")
+	cmd.Flags().BoolVar(&sections, "sections", false, "Mark each template's section with \"### <Name> ###\" to show where its rules came from")
+	cmd.Flags().BoolVar(&sortLines, "sort-lines", false, "Sort patterns alphabetically within each template section (comments first, negations kept in place)")
+	cmd.Flags().StringVar(&footer, "footer", "", "Comment text to append after the last template section")
+	cmd.Flags().StringVar(&commentStyle, "comment-style", templates.DefaultCommentPrefix, "Comment prefix for the generated header and section markers")
 	return cmd
 }
 
-func selectTemplates(args []string, items []templates.Template, presetList []presets.Preset, suggested []string, noInteractive bool) ([]templates.Template, bool, error) {
+// reportGenerated announces a written file. printPath prints only the target path, for scripts;
+// it is still printed under --quiet since it is the requested output.
+func reportGenerated(cmd *cobra.Command, opts *Options, target string, count int, printPath bool) {
+	switch {
+	case printPath:
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), target)
+	case opts.Quiet:
+	default:
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Generated %s with %d templates\n", target, count)
+	}
+}
+
+// policyExcludes returns the configured always_exclude patterns unless ignorePolicy is set.
+func policyExcludes(cfg config.Config, ignorePolicy bool) []string {
+	if ignorePolicy {
+		return nil
+	}
+	return cfg.AlwaysExclude
+}
+
+// writeReport appends key=value lines describing a generated file to reportPath, in the
+// format GitHub Actions reads from $GITHUB_OUTPUT. changed compares target against its
+// content before the write, ignoring the header timestamp.
+func writeReport(reportPath, target string, count int, before []byte, prefix string) error {
+	after, err := os.ReadFile(target)
+	if err != nil {
+		return fmt.Errorf("read generated file: %w", err)
+	}
+	changed := stripTimestamp(string(before), prefix) != stripTimestamp(string(after), prefix)
+
+	report := fmt.Sprintf("path=%s\ntemplate_count=%d\nchanged=%t\n", target, count, changed)
+	if err := ignorefile.AppendFile(reportPath, report); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	return nil
+}
+
+// stripTimestamp drops generator header timestamp lines, which differ on every run.
+func stripTimestamp(content, prefix string) string {
+	marker := prefix + " Timestamp: "
+	lines := strings.Split(content, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(line, marker) {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// errCacheOffline is returned by prepareCache when the cache is missing and the network is off limits.
+var errCacheOffline = errors.New("cache not initialized; run without --offline to clone it")
+
+// printSuggestions lists templates suggested for baseDir without resolving them
+// against the cache, so --suggest still works offline before the first clone.
+func printSuggestions(cmd *cobra.Command, baseDir string) error {
+	detected, err := presets.DetectFiles(baseDir)
+	if err != nil {
+		return err
+	}
+	suggested, err := presets.SuggestTemplates(detected)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if len(suggested) == 0 {
+		_, _ = fmt.Fprintln(out, "No templates suggested.")
+	} else {
+		_, _ = fmt.Fprintf(out, "Suggested templates: %s\n", strings.Join(suggested, ", "))
+	}
+	_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "note: template cache not initialized; run without --offline to generate a file")
+	return nil
+}
+
+// prepareCache returns the cache path, cloning it if missing and optionally pulling updates.
+// Auto-update failures are reported as warnings so generation can continue from the existing cache,
+// unless --strict turns them into errors.
+func prepareCache(cmd *cobra.Command, opts *Options, autoUpdate, offline bool) (string, error) {
+	initialized, err := cache.IsCacheInitialized()
+	if err != nil {
+		return "", err
+	}
+	if !initialized {
+		if offline {
+			return "", errCacheOffline
+		}
+		return cache.InitializeCache(cmd.Context())
+	}
+
+	if offline {
+		return cache.GetCachePath(cache.DefaultSource)
+	}
+	if !autoUpdate {
+		// InitializeCache still pulls a cache older than cache_ttl.
+		return cache.InitializeCache(cmd.Context())
+	}
+	if _, err := cache.UpdateCache(cmd.Context()); err != nil {
+		if err := warn(cmd, opts, "auto-update failed, using cached templates: %v", err); err != nil {
+			return "", err
+		}
+	}
+	return cache.GetCachePath(cache.DefaultSource)
+}
+
+// checkTemplateList reports each entry of the list file at path that does not resolve against
+// items, with its line number, and fails when any do.
+func checkTemplateList(cmd *cobra.Command, opts *Options, path string, entries []remote.ListEntry, items []templates.Template) error {
+	index := templates.BuildIndex(items)
+	failed := 0
+	for _, entry := range entries {
+		if _, ok := templates.FindTemplate(index, entry.Name); !ok {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s:%d: template not found: %s\n", path, entry.Line, entry.Name)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d template(s) in %s not found", failed, len(entries), path)
+	}
+	infof(cmd, opts, "%s: all %d templates found", path, len(entries))
+	return nil
+}
+
+// warnUnnamedTemplates warns about each file in userPath named just ".gitignore". Such files
+// have no template name, so discovery skips them.
+func warnUnnamedTemplates(cmd *cobra.Command, opts *Options, userPath string) error {
+	unnamed, err := templates.UnnamedUserTemplates(userPath)
+	if err != nil {
+		return err
+	}
+	for _, path := range unnamed {
+		if err := warn(cmd, opts, "skipping %s: user templates need a name, e.g. Custom.gitignore", path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logDiscovered reports under --verbose how many templates came from the cache and from userPath.
+func logDiscovered(cmd *cobra.Command, opts *Options, cached, user []templates.Template, userPath string) {
+	debugf(cmd, opts, "discovered %d cached templates and %d user templates in %s", len(cached), len(user), userPath)
+}
+
+// printSelectedTemplates writes the names of selected to stderr in merge order, one per line.
+func printSelectedTemplates(cmd *cobra.Command, selected []templates.Template) {
+	for _, tmpl := range selected {
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), tmpl.Name)
+	}
+}
+
+// excludeTemplates drops the templates named by excludes from selected. Names resolve through
+// FindTemplate over items, so "node.gitignore" and "Node" both match; one that matches nothing
+// selected is reported with warn.
+func excludeTemplates(cmd *cobra.Command, opts *Options, selected, items []templates.Template, excludes []string) ([]templates.Template, error) {
+	if len(excludes) == 0 {
+		return selected, nil
+	}
+	index := templates.BuildIndex(items)
+	for _, name := range excludes {
+		t, ok := templates.FindTemplate(index, name)
+		if !ok || !slices.ContainsFunc(selected, func(s templates.Template) bool { return s.Path == t.Path }) {
+			if err := warn(cmd, opts, "--exclude %s matches no selected template", name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return withoutTemplates(selected, excludedPaths(items, excludes)), nil
+}
+
+// excludedPaths returns the paths of the templates named by excludes, resolved like excludeTemplates
+// does. Names that match nothing are skipped.
+func excludedPaths(items []templates.Template, excludes []string) map[string]bool {
+	if len(excludes) == 0 {
+		return nil
+	}
+	index := templates.BuildIndex(items)
+	paths := make(map[string]bool, len(excludes))
+	for _, name := range excludes {
+		if t, ok := templates.FindTemplate(index, name); ok {
+			paths[t.Path] = true
+		}
+	}
+	return paths
+}
+
+// withoutTemplates returns the templates in selected whose path is not in excluded, leaving
+// selected itself unchanged.
+func withoutTemplates(selected []templates.Template, excluded map[string]bool) []templates.Template {
+	return slices.DeleteFunc(slices.Clone(selected), func(t templates.Template) bool { return excluded[t.Path] })
+}
+
+// confirmPreview returns the preview shown on the selector's confirmation summary. It adds
+// defaults and drops excluded templates the same way generate does before writing, so the
+// reviewed change matches the file written.
+func confirmPreview(target string, defaults []templates.Template, excluded map[string]bool, opts templates.MergeOptions, appendMode, mergeMode bool, injectAt string) tui.PreviewFunc {
+	return func(selected []templates.Template) (string, error) {
+		return previewOutput(target, withoutTemplates(ignorefile.WithDefaults(defaults, selected), excluded), opts, appendMode, mergeMode, injectAt)
+	}
+}
+
+// resolveDefaultTemplates looks up the configured default templates in items. A name that
+// matches nothing is warned about and skipped rather than failing the command.
+func resolveDefaultTemplates(cmd *cobra.Command, opts *Options, names []string, items []templates.Template) ([]templates.Template, error) {
+	defaults, missing := ignorefile.ResolveDefaults(names, items)
+	for _, name := range missing {
+		if err := warn(cmd, opts, "default template not found: %s", name); err != nil {
+			return nil, err
+		}
+	}
+	return defaults, nil
+}
+
+// selectTemplates resolves explicit names against all items, or opens the selector over visible items
+// with a confirmation summary for target. A non-nil preview is shown on that summary.
+func selectTemplates(args []string, items, visible []templates.Template, presetList []presets.Preset, suggested []string, noInteractive bool, target string, preview tui.PreviewFunc) ([]templates.Template, bool, error) {
 	if len(args) > 0 || noInteractive {
 		index := templates.BuildIndex(items)
 		selected := make([]templates.Template, 0, len(args))
@@ -132,21 +595,180 @@ func selectTemplates(args []string, items []templates.Template, presetList []pre
 		return selected, false, nil
 	}
 
-	selected, err := tui.ShowInteractiveSelector(items, presetList, nil, suggested)
+	selected, err := tui.ShowInteractiveSelectorWithOptions(visible, presetList, nil, suggested, target, tui.SelectorOptions{Preview: preview})
 	return selected, true, err
 }
 
-func resolveOutputPath(output string) (string, error) {
-	if strings.TrimSpace(output) != "" {
+// previewOutput renders the file generate would write to target for selected: the whole file
+// when target does not exist yet, otherwise a unified diff against its current content.
+func previewOutput(target string, selected []templates.Template, opts templates.MergeOptions, appendMode, mergeMode bool, injectAt string) (string, error) {
+	loaded, err := templates.LoadTemplates(selected)
+	if err != nil {
+		return "", err
+	}
+	if mergeMode {
+		opts = mergeModeOptions(target, opts)
+	}
+	content := templates.MergeTemplates(loaded, opts)
+
+	if injectAt != "" {
+		// injectIntoFile reports a missing file or marker before anything is shown.
+		if content, err = injectIntoFile(target, injectAt, content); err != nil {
+			return "", err
+		}
+	}
+
+	existing, err := os.ReadFile(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return content, nil
+		}
+		return "", fmt.Errorf("read %s: %w", target, err)
+	}
+	proposed := content
+	switch {
+	case mergeMode:
+		added, err := mergeNewRules(target, content, opts.CommentPrefix)
+		if err != nil {
+			return "", err
+		}
+		proposed = string(existing) + added
+	case appendMode:
+		proposed = string(existing) + content
+	}
+	diff := templates.UnifiedDiff(target+" (current)", target+" (generated)", string(existing), proposed)
+	if diff == "" {
+		return fmt.Sprintf("No changes to %s\n", target), nil
+	}
+	return diff, nil
+}
+
+// stdoutTarget as the output path writes the generated content to stdout instead of a file.
+const stdoutTarget = "-"
+
+// outputInDir places output, or the default output file when it is empty, inside dir. An empty
+// dir returns output unchanged. Missing directories are created when the file is written.
+func outputInDir(dir, output string) (string, error) {
+	if strings.TrimSpace(dir) == "" {
 		return output, nil
 	}
+	output = strings.TrimSpace(output)
+	if output == stdoutTarget || filepath.IsAbs(output) {
+		return "", fmt.Errorf("--output-dir cannot be used with --output - or an absolute --output")
+	}
+	if output == "" {
+		output = ".gitignore"
+		if cfg, err := config.LoadConfig(); err == nil && strings.TrimSpace(cfg.DefaultOutput) != "" && !filepath.IsAbs(cfg.DefaultOutput) {
+			output = cfg.DefaultOutput
+		}
+	}
+	return filepath.Join(dir, output), nil
+}
+
+// gitReadsFile reports whether git reads path as ignore rules, where only # starts a comment.
+func gitReadsFile(path string) bool {
+	return filepath.Base(path) == ".gitignore" || strings.HasSuffix(filepath.ToSlash(path), ".git/info/exclude")
+}
+
+// resolveOutputPath resolves the output file relative to baseDir.
+// Absolute paths and stdoutTarget are returned unchanged.
+func resolveOutputPath(baseDir, output string) (string, error) {
+	if strings.TrimSpace(output) == stdoutTarget {
+		return stdoutTarget, nil
+	}
+	target := filepath.Join(baseDir, ".gitignore")
+	if strings.TrimSpace(output) != "" {
+		target = joinBaseDir(baseDir, output)
+	} else if cfg, err := config.LoadConfig(); err == nil && strings.TrimSpace(cfg.DefaultOutput) != "" {
+		target = joinBaseDir(baseDir, cfg.DefaultOutput)
+	}
+
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		return "", fmt.Errorf("output path is a directory: %s", target)
+	}
+	if err := checkInternalPath(target); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// checkInternalPath refuses targets that would overwrite ignr's own config file,
+// presets file, or anything inside the template cache or undo history. Symlinks are
+// resolved first, so a link to the config directory does not get around the check.
+func checkInternalPath(target string) error {
+	abs, err := resolvePath(target)
+	if err != nil {
+		return fmt.Errorf("resolve output path: %w", err)
+	}
+
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		return err
+	}
+	presetsPath, err := config.GetPresetsPath()
+	if err != nil {
+		return err
+	}
+	historyPath, err := history.GetHistoryDir()
+	if err != nil {
+		return err
+	}
+	cachePath, err := cache.GetCachePath(cache.DefaultSource)
+	if err != nil {
+		return err
+	}
 
-	cfg, err := config.LoadConfig()
-	if err == nil && strings.TrimSpace(cfg.DefaultOutput) != "" {
-		return cfg.DefaultOutput, nil
+	internal := []struct {
+		label string
+		path  string
+		tree  bool
+	}{
+		{label: "config file", path: configPath},
+		{label: "presets file", path: presetsPath},
+		{label: "template cache", path: cachePath, tree: true},
+		{label: "undo history", path: historyPath, tree: true},
+	}
+	for _, entry := range internal {
+		path, err := resolvePath(entry.path)
+		if err != nil {
+			continue
+		}
+		if abs == path {
+			return fmt.Errorf("output path is ignr's %s: %s", entry.label, target)
+		}
+		if rel, err := filepath.Rel(path, abs); entry.tree && err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("output path is inside ignr's %s: %s", entry.label, target)
+		}
 	}
+	return nil
+}
 
-	return filepath.Join(".", ".gitignore"), nil
+// resolvePath returns the absolute form of path with symlinks resolved. Parts of the path
+// that do not exist yet are kept as they are below the deepest existing directory.
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	dir, rest := abs, ""
+	for {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(resolved, rest), nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return abs, nil
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+		dir = parent
+	}
+}
+
+func joinBaseDir(baseDir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
 }
 
 func handleExistingOutput(cmd *cobra.Command, path string, appendMode, force, interactive bool, templates []templates.Template) error {
@@ -174,31 +796,57 @@ func handleExistingOutput(cmd *cobra.Command, path string, appendMode, force, in
 	return nil
 }
 
+// injectIntoFile returns the existing file at path with its marker line replaced by content.
+func injectIntoFile(path, marker, content string) (string, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("output file does not exist: %s (--inject-at needs an existing file)", path)
+		}
+		return "", fmt.Errorf("read %s: %w", path, err)
+	}
+	injected, err := templates.InjectAtMarker(string(existing), marker, content)
+	if err != nil {
+		return "", fmt.Errorf("inject into %s: %w", path, err)
+	}
+	return injected, nil
+}
+
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
 }
 
-func writeOutput(path, content string, appendMode, force bool) error {
-	if appendMode {
-		return appendToFile(path, content)
+// mergeModeOptions leaves the header out of opts when the file at path already has content:
+// --merge adds rules below the file's own header, and AppendNew would keep the new header's
+// timestamp and template lines as stray comments.
+func mergeModeOptions(path string, opts templates.MergeOptions) templates.MergeOptions {
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		opts.AddHeader = false
 	}
-
-	return os.WriteFile(path, []byte(content), 0o644)
+	return opts
 }
 
-func appendToFile(path, content string) error {
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
-	if err != nil {
-		return err
+// mergeNewRules returns the part of content holding rules the file at path does not have yet,
+// separated from the existing rules by a blank line. It is empty when the file already has
+// every rule.
+func mergeNewRules(path, content, prefix string) (string, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("read %s: %w", path, err)
 	}
-	_, writeErr := file.WriteString(content)
-	closeErr := file.Close()
-	if writeErr != nil {
-		return writeErr
+	added := templates.AppendNew(string(existing), content, prefix)
+	if added == "" || len(existing) == 0 {
+		return added, nil
 	}
-	if closeErr != nil {
-		return closeErr
+	// Keep a blank line between the existing rules and the appended block.
+	text := strings.ReplaceAll(string(existing), "\r\n", "\n")
+	switch {
+	case !strings.HasSuffix(text, "\n"):
+		added = "\n\n" + added
+	case !strings.HasSuffix(text, "\n\n"):
+		added = "\n" + added
 	}
-	return nil
+	return added, nil
 }
+
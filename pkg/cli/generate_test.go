@@ -2,14 +2,19 @@ package cli
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/adrg/xdg"
+	"go.seanlatimer.dev/ignr/internal/presets"
+	"go.seanlatimer.dev/ignr/internal/templates"
 )
 
 func setupGenerateTest(t *testing.T) func() {
@@ -415,3 +420,1498 @@ func TestGenerateCommandFileExists(t *testing.T) {
 		t.Errorf("generate command error = %v, want error containing 'exists'", err)
 	}
 }
+
+func TestGenerateCommandChdir(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	targetDir := t.TempDir()
+
+	opts := &Options{}
+	root := NewRootCommand(opts)
+	root.SetArgs([]string{"-C", targetDir, "generate", "--no-interactive", "Go"})
+
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("generate -C error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(targetDir, ".gitignore"))
+	if err != nil {
+		t.Fatalf("generate -C did not write into target dir: %v", err)
+	}
+	if !strings.Contains(string(data), "*.exe") {
+		t.Error("generate -C output missing template content")
+	}
+}
+
+func TestGenerateCommandChdirRelativeOutput(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	targetDir := t.TempDir()
+
+	opts := &Options{Chdir: targetDir}
+	cmd := newGenerateCommand(opts)
+	cmd.SetArgs([]string{"--no-interactive", "--output", "custom.gitignore", "Go"})
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("generate error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(targetDir, "custom.gitignore")); err != nil {
+		t.Errorf("relative --output was not resolved against chdir: %v", err)
+	}
+}
+
+func TestRootCommandChdirMissingDir(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	opts := &Options{}
+	root := NewRootCommand(opts)
+	root.SetArgs([]string{"-C", filepath.Join(t.TempDir(), "missing"), "generate", "--no-interactive", "Go"})
+
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "chdir") {
+		t.Errorf("generate -C missing dir error = %v, want chdir error", err)
+	}
+}
+
+func TestGenerateCommandAutoUpdate(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantAttempt bool
+	}{
+		{name: "disabled by default", args: []string{}, wantAttempt: false},
+		{name: "enabled by flag", args: []string{"--auto-update"}, wantAttempt: true},
+		{name: "skipped offline", args: []string{"--auto-update", "--offline"}, wantAttempt: false},
+		{name: "skipped by no-auto-update", args: []string{"--auto-update", "--no-auto-update"}, wantAttempt: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupGenerateTest(t)
+			defer cleanup()
+
+			opts := &Options{Chdir: t.TempDir()}
+			cmd := newGenerateCommand(opts)
+			cmd.SetArgs(append(tt.args, "--no-interactive", "Go"))
+
+			var stdout, stderr bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stderr)
+
+			// The test cache has no remote, so an attempted pull always fails and warns.
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("generate error = %v, want auto-update failure to be non-fatal", err)
+			}
+			attempted := strings.Contains(stderr.String(), "auto-update failed")
+			if attempted != tt.wantAttempt {
+				t.Errorf("auto-update attempted = %v, want %v (stderr %q)", attempted, tt.wantAttempt, stderr.String())
+			}
+		})
+	}
+}
+
+func TestGenerateCommandAutoUpdateFromConfig(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	configPath := filepath.Join(xdg.ConfigHome, "ignr", "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"auto_update_on_generate": true}`), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	opts := &Options{Chdir: t.TempDir()}
+	cmd := newGenerateCommand(opts)
+	cmd.SetArgs([]string{"--no-interactive", "Go"})
+
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("generate error = %v", err)
+	}
+	if !strings.Contains(stderr.String(), "auto-update failed") {
+		t.Errorf("generate with auto_update_on_generate did not attempt update (stderr %q)", stderr.String())
+	}
+}
+
+func TestGenerateCommandOfflineUninitializedCache(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	if err := os.RemoveAll(filepath.Join(xdg.ConfigHome, "ignr", "cache")); err != nil {
+		t.Fatalf("failed to remove cache: %v", err)
+	}
+
+	opts := &Options{Chdir: t.TempDir()}
+	cmd := newGenerateCommand(opts)
+	cmd.SetArgs([]string{"--offline", "--no-interactive", "Go"})
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--offline") {
+		t.Errorf("generate --offline with missing cache error = %v, want offline error", err)
+	}
+}
+
+func TestGenerateCommandCommentStyle(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	opts := &Options{Chdir: dir}
+	cmd := newGenerateCommand(opts)
+	cmd.SetArgs([]string{"--no-interactive", "--comment-style", ";", "-o", ".toolignore", "Go"})
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("generate error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".toolignore"))
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	content := string(data)
+	if !strings.HasPrefix(content, "; Generated by ignr") || !strings.Contains(content, "; --- Go ---") {
+		t.Errorf("generate --comment-style ; output = %q, want ;-prefixed header and marker", content)
+	}
+
+	cmd = newGenerateCommand(opts)
+	cmd.SetArgs([]string{"--no-interactive", "--force", "--comment-style", "rem", "Go"})
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "comment prefix") {
+		t.Errorf("generate --comment-style rem error = %v, want invalid prefix error", err)
+	}
+
+	for _, args := range [][]string{
+		{"--comment-style", "//"},
+		{"--comment-style", ";", "-o", "sub/.gitignore"},
+		{"--comment-style", ";", "-o", ".git/info/exclude"},
+		{"--comment-style", ";", "-o", ".toolignore", "--personal", "Global"},
+	} {
+		cmd = newGenerateCommand(opts)
+		cmd.SetArgs(append([]string{"--no-interactive", "--force"}, append(args, "Go")...))
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "git only treats # as a comment") {
+			t.Errorf("generate %v error = %v, want the prefix refused for files git reads", args, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".gitignore")); !os.IsNotExist(err) {
+		t.Errorf("generate with a ; prefix wrote .gitignore")
+	}
+}
+
+func TestGenerateCommandSuggestWithoutCache(t *testing.T) {
+	for _, flag := range []string{"--offline", "--no-suggest-network"} {
+		t.Run(flag, func(t *testing.T) {
+			cleanup := setupGenerateTest(t)
+			defer cleanup()
+
+			cacheDir := filepath.Join(xdg.ConfigHome, "ignr", "cache")
+			if err := os.RemoveAll(cacheDir); err != nil {
+				t.Fatalf("failed to remove cache: %v", err)
+			}
+
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example\n"), 0o644); err != nil {
+				t.Fatalf("failed to write go.mod: %v", err)
+			}
+
+			cmd := newGenerateCommand(&Options{Chdir: dir})
+			cmd.SetArgs([]string{flag, "--suggest"})
+			var stdout, stderr bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stderr)
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("generate %s --suggest error = %v", flag, err)
+			}
+			if !strings.Contains(stdout.String(), "Go") {
+				t.Errorf("generate %s --suggest output = %q, want suggested Go template", flag, stdout.String())
+			}
+			if _, err := os.Stat(cacheDir); !os.IsNotExist(err) {
+				t.Errorf("generate %s --suggest created the cache, want it left untouched", flag)
+			}
+			if _, err := os.Stat(filepath.Join(dir, ".gitignore")); !os.IsNotExist(err) {
+				t.Errorf("generate %s --suggest wrote .gitignore without a cache", flag)
+			}
+	
+			cmd = newGenerateCommand(&Options{Chdir: dir})
+			cmd.SetArgs([]string{flag, "--suggest", "Go"})
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			if err := cmd.Execute(); !errors.Is(err, errCacheOffline) {
+				t.Errorf("generate %s --suggest Go error = %v, want the missing cache error", flag, err)
+			}
+		})
+	}
+}
+
+func TestGenerateCommandPrintPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		quiet bool
+		args  []string
+		want  func(target string) string
+	}{
+		{
+			name: "print-path prints only the path",
+			args: []string{"--print-path"},
+			want: func(target string) string { return target + "\n" },
+		},
+		{
+			name:  "print-path still prints under quiet",
+			quiet: true,
+			args:  []string{"--print-path"},
+			want:  func(target string) string { return target + "\n" },
+		},
+		{
+			name:  "quiet prints nothing",
+			quiet: true,
+			want:  func(string) string { return "" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupGenerateTest(t)
+			defer cleanup()
+
+			dir := t.TempDir()
+			cmd := newGenerateCommand(&Options{Chdir: dir, Quiet: tt.quiet})
+			cmd.SetArgs(append(tt.args, "--no-interactive", "Go"))
+			var stdout, stderr bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stderr)
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("generate error = %v", err)
+			}
+			want := tt.want(filepath.Join(dir, ".gitignore"))
+			if stdout.String() != want {
+				t.Errorf("generate stdout = %q, want %q", stdout.String(), want)
+			}
+		})
+	}
+}
+
+func TestGenerateCommandOutputIsDirectory(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, "sub")
+	if err := os.Mkdir(target, 0o755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	for _, args := range [][]string{
+		{"--no-interactive", "--force", "-o", target, "Go"},
+		{"--no-interactive", "--append", "-o", "sub", "Go"},
+	} {
+		cmd := newGenerateCommand(&Options{Chdir: dir})
+		cmd.SetArgs(args)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+
+		err := cmd.Execute()
+		if err == nil || err.Error() != "output path is a directory: "+target {
+			t.Errorf("generate %v error = %v, want output path is a directory", args, err)
+		}
+	}
+}
+
+func TestGenerateCommandOutputInternalPath(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	configDir := filepath.Join(xdg.ConfigHome, "ignr")
+	presetsPath := filepath.Join(configDir, "presets.yaml")
+	if err := os.WriteFile(presetsPath, []byte("presets: []\n"), 0o644); err != nil {
+		t.Fatalf("failed to write presets: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		output  string
+		wantErr string
+	}{
+		{name: "presets file", output: presetsPath, wantErr: "presets file"},
+		{name: "config file", output: filepath.Join(configDir, "config.json"), wantErr: "config file"},
+		{name: "inside cache", output: filepath.Join(configDir, "cache", "github-gitignore", "Go.gitignore"), wantErr: "template cache"},
+		{name: "next to cache is allowed", output: filepath.Join(configDir, "cache", "github-gitignore-notes")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newGenerateCommand(&Options{})
+			cmd.SetArgs([]string{"--no-interactive", "--force", "-o", tt.output, "Go"})
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetErr(&buf)
+
+			err := cmd.Execute()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("generate -o %s error = %v", tt.output, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("generate -o %s error = %v, want %q", tt.output, err, tt.wantErr)
+			}
+		})
+	}
+
+	data, err := os.ReadFile(presetsPath)
+	if err != nil || string(data) != "presets: []\n" {
+		t.Errorf("presets file changed to %q (err %v)", data, err)
+	}
+}
+
+func TestGenerateCommandOutputInternalPathSymlink(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	link := filepath.Join(t.TempDir(), "settings")
+	if err := os.Symlink(filepath.Join(xdg.ConfigHome, "ignr"), link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		output  string
+		wantErr string
+	}{
+		{name: "config file", output: filepath.Join(link, "config.json"), wantErr: "config file"},
+		{name: "inside cache", output: filepath.Join(link, "cache", "github-gitignore", "new", ".gitignore"), wantErr: "template cache"},
+		{name: "other file", output: filepath.Join(link, "notes", ".gitignore")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newGenerateCommand(&Options{})
+			cmd.SetArgs([]string{"--no-interactive", "--force", "-o", tt.output, "Go"})
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+
+			err := cmd.Execute()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("generate -o %s error = %v", tt.output, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("generate -o %s error = %v, want %q", tt.output, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateCommandOnlyCategory(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	globalDir := filepath.Join(xdg.ConfigHome, "ignr", "cache", "github-gitignore", "Global")
+	if err := os.MkdirAll(globalDir, 0o755); err != nil {
+		t.Fatalf("failed to create Global dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(globalDir, "Go.gitignore"), []byte("# Global Go\n.gopls/\n"), 0o644); err != nil {
+		t.Fatalf("failed to create template file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		notWant string
+		wantErr string
+	}{
+		{name: "--only Global", args: []string{"--only", "Global", "Go"}, want: ".gopls/", notWant: "vendor/"},
+		{name: "--only root", args: []string{"--only", "root", "go"}, want: "vendor/", notWant: ".gopls/"},
+		{name: "name outside category", args: []string{"--only", "Global", "Python"}, wantErr: "template not found: Python"},
+		{name: "empty category", args: []string{"--only", "community", "Go"}, wantErr: "no templates in category: community"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			cmd := newGenerateCommand(&Options{Chdir: dir})
+			cmd.SetArgs(append([]string{"--no-interactive"}, tt.args...))
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetErr(&buf)
+
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("generate error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("generate error = %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			content := string(data)
+			if !strings.Contains(content, tt.want) || strings.Contains(content, tt.notWant) {
+				t.Errorf("generate %v output = %q, want %q and not %q", tt.args, content, tt.want, tt.notWant)
+			}
+		})
+	}
+}
+
+func TestGenerateCommandReport(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	reportPath := filepath.Join(t.TempDir(), "github_output")
+	target := filepath.Join(dir, ".gitignore")
+
+	run := func(templates ...string) {
+		t.Helper()
+		cmd := newGenerateCommand(&Options{Chdir: dir, Quiet: true})
+		cmd.SetArgs(append([]string{"--no-interactive", "--force", "--report", reportPath}, templates...))
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("generate %v error = %v", templates, err)
+		}
+	}
+
+	// First run creates the file, the second regenerates identical content
+	// (only the header timestamp can differ), and the third adds a template.
+	run("Go")
+	run("Go")
+	run("Go", "Node")
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	want := "path=" + target + "\ntemplate_count=1\nchanged=true\n" +
+		"path=" + target + "\ntemplate_count=1\nchanged=false\n" +
+		"path=" + target + "\ntemplate_count=2\nchanged=true\n"
+	if string(data) != want {
+		t.Errorf("report = %q, want %q", string(data), want)
+	}
+}
+
+func TestStripTimestamp(t *testing.T) {
+	a := "; Generated by ignr\n; Timestamp: 2024-01-01T00:00:00Z\n*.exe\n"
+	b := "; Generated by ignr\n; Timestamp: 2025-06-01T12:00:00Z\n*.exe\n"
+	if stripTimestamp(a, ";") != stripTimestamp(b, ";") {
+		t.Errorf("stripTimestamp() kept differing timestamp lines")
+	}
+	if stripTimestamp(a, "#") == stripTimestamp(b, "#") {
+		t.Errorf("stripTimestamp() with another prefix removed unrelated lines")
+	}
+}
+
+func TestGenerateCommandAlwaysExclude(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	configPath := filepath.Join(xdg.ConfigHome, "ignr", "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"always_exclude": ["*.log"]}`), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		command []string
+		wantLog bool
+	}{
+		{name: "generate applies policy", command: []string{"generate", "--no-interactive", "Node"}},
+		{name: "generate --ignore-policy", command: []string{"generate", "--no-interactive", "--ignore-policy", "Node"}, wantLog: true},
+		{name: "preset use applies policy", command: []string{"preset", "use", "web"}},
+		{name: "preset use --ignore-policy", command: []string{"preset", "use", "--ignore-policy", "web"}, wantLog: true},
+	}
+
+	if err := presets.CreatePreset("web", []string{"Node"}, nil); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			root := NewRootCommand(&Options{})
+			root.SetArgs(append([]string{"-C", dir}, tt.command...))
+			var buf bytes.Buffer
+			root.SetOut(&buf)
+			root.SetErr(&buf)
+			if err := root.Execute(); err != nil {
+				t.Fatalf("%v error = %v", tt.command, err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			content := string(data)
+			if !strings.Contains(content, "node_modules/") {
+				t.Errorf("output = %q, want the rest of the Node template", content)
+			}
+			if got := strings.Contains(content, "*.log"); got != tt.wantLog {
+				t.Errorf("output contains *.log = %v, want %v\n%s", got, tt.wantLog, content)
+			}
+		})
+	}
+}
+
+func TestGenerateCommandDefaultTemplates(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	configPath := filepath.Join(xdg.ConfigHome, "ignr", "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"default_templates": ["Go", "Missing", "node"]}`), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if err := presets.CreatePreset("py", []string{"Python"}, nil); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		command []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "generate prepends defaults", command: []string{"generate", "--no-interactive", "Python", "Node"}, want: []string{"Go", "Node", "Python"}},
+		{name: "generate --no-defaults", command: []string{"generate", "--no-interactive", "--no-defaults", "Python"}, want: []string{"Python"}},
+		{name: "exclude drops a default", command: []string{"generate", "--no-interactive", "--exclude", "Go", "Python"}, want: []string{"Node", "Python"}},
+		{name: "preset use prepends defaults", command: []string{"preset", "use", "py"}, want: []string{"Go", "Node", "Python"}},
+		{name: "preset use --no-defaults", command: []string{"preset", "use", "--no-defaults", "py"}, want: []string{"Python"}},
+		{name: "strict fails on missing default", command: []string{"--strict", "generate", "--no-interactive", "Python"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			root := NewRootCommand(&Options{})
+			root.SetArgs(append([]string{"-C", dir}, append(tt.command, "--print-templates")...))
+			var stdout, stderr bytes.Buffer
+			root.SetOut(&stdout)
+			root.SetErr(&stderr)
+			err := root.Execute()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "default template not found: Missing") {
+					t.Fatalf("%v error = %v, want missing default", tt.command, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%v error = %v", tt.command, err)
+			}
+			wantWarning := !slices.Contains(tt.command, "--no-defaults")
+			if got := strings.Contains(stderr.String(), "default template not found: Missing"); got != wantWarning {
+				t.Errorf("stderr = %q, want missing-default warning = %v", stderr.String(), wantWarning)
+			}
+			var got []string
+			for _, line := range strings.Split(strings.TrimSpace(stderr.String()), "\n") {
+				if !strings.Contains(line, "not found") {
+					got = append(got, line)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("templates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateCommandRefreshesStaleCache(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	configPath := filepath.Join(xdg.ConfigHome, "ignr", "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"cache_ttl": "1ns"}`), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	// The test cache is not a real repository, so a refresh attempt fails with a warning.
+	tests := []struct {
+		name     string
+		args     []string
+		wantWarn bool
+	}{
+		{name: "stale cache is refreshed", args: []string{"generate", "--no-interactive", "Go"}, wantWarn: true},
+		{name: "--no-update", args: []string{"--no-update", "generate", "--no-interactive", "Go"}},
+		{name: "--offline", args: []string{"generate", "--offline", "--no-interactive", "Go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := NewRootCommand(&Options{})
+			root.SetArgs(append([]string{"-C", t.TempDir()}, tt.args...))
+			var stderr bytes.Buffer
+			root.SetOut(&bytes.Buffer{})
+			root.SetErr(&stderr)
+			if err := root.Execute(); err != nil {
+				t.Fatalf("%v error = %v", tt.args, err)
+			}
+			if got := strings.Contains(stderr.String(), "could not refresh the template cache"); got != tt.wantWarn {
+				t.Errorf("stderr = %q, want refresh warning = %v", stderr.String(), tt.wantWarn)
+			}
+		})
+	}
+	root := NewRootCommand(&Options{})
+	root.SetArgs([]string{"-C", t.TempDir(), "--strict", "generate", "--no-interactive", "Go"})
+	var stderr bytes.Buffer
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&stderr)
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "could not refresh the template cache") || !strings.Contains(err.Error(), "--strict") {
+		t.Errorf("--strict with a failed refresh error = %v, want the warning as an error", err)
+	}
+	if strings.Contains(stderr.String(), "warning:") {
+		t.Errorf("stderr = %q, want no warning line under --strict", stderr.String())
+	}
+}
+
+func TestGenerateCommandInjectAt(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name     string
+		existing string
+		args     []string
+		wantErr  string
+		want     []string
+	}{
+		{
+			name:     "replaces marker in place",
+			existing: "# manual top\n.env\n# ignr:here\n# manual bottom\nlocal/\n",
+			args:     []string{"--inject-at", "# ignr:here"},
+			want:     []string{"# manual top\n.env\n", "vendor/", "\n# manual bottom\nlocal/\n"},
+		},
+		{
+			name:     "missing marker",
+			existing: "# manual\n.env\n",
+			args:     []string{"--inject-at", "# ignr:here"},
+			wantErr:  "marker not found",
+		},
+		{
+			name:    "missing file",
+			args:    []string{"--inject-at", "# ignr:here"},
+			wantErr: "output file does not exist",
+		},
+		{
+			name:     "conflicts with append",
+			existing: "# ignr:here\n",
+			args:     []string{"--inject-at", "# ignr:here", "--append"},
+			wantErr:  "cannot be used with --append",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, ".gitignore")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0o644); err != nil {
+					t.Fatalf("failed to write .gitignore: %v", err)
+				}
+			}
+
+			cmd := newGenerateCommand(&Options{Chdir: dir})
+			cmd.SetArgs(append([]string{"--no-interactive", "Go"}, tt.args...))
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetErr(&buf)
+			err := cmd.Execute()
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("generate error = %v, want %q", err, tt.wantErr)
+				}
+				if tt.existing != "" {
+					data, _ := os.ReadFile(path)
+					if string(data) != tt.existing {
+						t.Errorf("file changed on error: %q", data)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("generate error = %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read .gitignore: %v", err)
+			}
+			content := string(data)
+			if strings.Contains(content, "ignr:here") {
+				t.Errorf("marker still present:\n%s", content)
+			}
+			if !strings.HasPrefix(content, tt.want[0]) || !strings.HasSuffix(content, tt.want[2]) || !strings.Contains(content, tt.want[1]) {
+				t.Errorf("injected content = %q, want generated content between the manual sections", content)
+			}
+		})
+	}
+}
+
+func TestConfirmPreviewExcludes(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	cachePath := filepath.Join(xdg.ConfigHome, "ignr", "cache", "github-gitignore")
+	tmpl := func(name string) templates.Template {
+		return templates.Template{Name: name, Category: templates.CategoryRoot, Path: filepath.Join(cachePath, name+".gitignore")}
+	}
+	items := []templates.Template{tmpl("Go"), tmpl("Node"), tmpl("Python")}
+	selected := []templates.Template{tmpl("Go"), tmpl("Node")}
+	target := filepath.Join(t.TempDir(), ".gitignore")
+
+	preview := confirmPreview(target, []templates.Template{tmpl("Python")}, excludedPaths(items, []string{"node.gitignore", "Python"}), templates.MergeOptions{Deduplicate: true}, false, false, "")
+	got, err := preview(selected)
+	if err != nil {
+		t.Fatalf("preview error = %v", err)
+	}
+	if !strings.Contains(got, "# --- Go ---") {
+		t.Errorf("preview = %q, want the Go section", got)
+	}
+	for _, excluded := range []string{"--- Node ---", "--- Python ---"} {
+		if strings.Contains(got, excluded) {
+			t.Errorf("preview = %q, want the excluded %q section left out", got, excluded)
+		}
+	}
+	if len(selected) != 2 || selected[1].Name != "Node" {
+		t.Errorf("preview changed the selection to %v", selected)
+	}
+}
+
+func TestPreviewOutput(t *testing.T) {
+	cleanup, cachePath := setupListTest(t)
+	defer cleanup()
+
+	goTemplate := templates.Template{Name: "Go", Category: templates.CategoryRoot, Path: filepath.Join(cachePath, "Go.gitignore")}
+	opts := templates.MergeOptions{Deduplicate: true}
+
+	tests := []struct {
+		name       string
+		existing   string
+		appendMode bool
+		mergeMode  bool
+		injectAt   string
+		want       []string
+		wantErr    string
+	}{
+		{name: "new file shows content", want: []string{"# --- Go ---"}},
+		{name: "existing file shows diff", existing: "old.txt\n", want: []string{"(current)", "(generated)", "-old.txt", "+# --- Go ---"}},
+		{name: "append keeps existing lines", existing: "old.txt\n", appendMode: true, want: []string{" old.txt", "+# --- Go ---"}},
+		{name: "merge without new rules", existing: "old.txt\n", mergeMode: true, want: []string{"No changes to "}},
+		{name: "inject replaces marker", existing: "a\n# ignr:here\nb\n", injectAt: "# ignr:here", want: []string{"-# ignr:here", "+# --- Go ---", " b"}},
+		{name: "inject needs marker", existing: "a\n", injectAt: "# ignr:here", wantErr: "marker not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := filepath.Join(t.TempDir(), ".gitignore")
+			if tt.existing != "" {
+				if err := os.WriteFile(target, []byte(tt.existing), 0o644); err != nil {
+					t.Fatalf("failed to write target: %v", err)
+				}
+			}
+
+			got, err := previewOutput(target, []templates.Template{goTemplate}, opts, tt.appendMode, tt.mergeMode, tt.injectAt)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("previewOutput() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("previewOutput() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("previewOutput() = %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+
+	t.Run("unchanged file", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), ".gitignore")
+		content, err := previewOutput(target, []templates.Template{goTemplate}, opts, false, false, "")
+		if err != nil {
+			t.Fatalf("previewOutput() error = %v", err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write target: %v", err)
+		}
+		got, err := previewOutput(target, []templates.Template{goTemplate}, opts, false, false, "")
+		if err != nil || !strings.HasPrefix(got, "No changes to ") {
+			t.Errorf("previewOutput() = %q, %v; want no changes", got, err)
+		}
+	})
+}
+
+func TestGenerateCommandCanonical(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	if err := presets.CreatePreset("web", []string{"Node", "Go"}, nil); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+
+	runs := [][]string{
+		{"generate", "--no-interactive", "--canonical", "Node", "Go"},
+		{"generate", "--no-interactive", "--canonical", "Go", "Node"},
+		{"preset", "use", "--canonical", "web"},
+	}
+	want := "# Generated by ignr " + Version + "\n# Templates: Go, Node\n\n" +
+		"# --- Go ---\n# Go\n*.exe\nvendor/\n\n" +
+		"# --- Node ---\n# Node\n*.log\nnode_modules/\n"
+
+	for _, args := range runs {
+		dir := t.TempDir()
+		root := NewRootCommand(&Options{})
+		root.SetArgs(append([]string{"-C", dir}, args...))
+		var buf bytes.Buffer
+		root.SetOut(&buf)
+		root.SetErr(&buf)
+		if err := root.Execute(); err != nil {
+			t.Fatalf("%v error = %v", args, err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+		if err != nil {
+			t.Fatalf("failed to read output: %v", err)
+		}
+		if string(data) != want {
+			t.Errorf("%v output = %q, want %q", args, data, want)
+		}
+	}
+}
+
+func TestGenerateCommandAppendSectionHeader(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	today := "# --- Added by ignr on " + time.Now().Format(time.DateOnly) + " ---\n"
+	tests := []struct {
+		name       string
+		args       []string
+		wantBanner string
+	}{
+		{name: "append", args: []string{"--append"}, wantBanner: today},
+		{name: "append without timestamp", args: []string{"--append", "--no-timestamp"}, wantBanner: "# --- Added by ignr ---\n"},
+		{name: "append without banner", args: []string{"--append", "--append-section-header=false"}},
+		{name: "overwrite", args: []string{"--force"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			existing := "# Existing\nold.txt\n"
+			if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte(existing), 0o644); err != nil {
+				t.Fatalf("failed to write existing file: %v", err)
+			}
+
+			root := NewRootCommand(&Options{})
+			root.SetArgs(append([]string{"-C", dir, "generate", "--no-interactive", "Go"}, tt.args...))
+			var buf bytes.Buffer
+			root.SetOut(&buf)
+			root.SetErr(&buf)
+			if err := root.Execute(); err != nil {
+				t.Fatalf("%v error = %v", tt.args, err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			content := string(data)
+			if tt.wantBanner != "" && !strings.HasPrefix(content, existing+tt.wantBanner+"# Generated by ignr") {
+				t.Errorf("%v output = %q, want banner %q after the existing content", tt.args, content, tt.wantBanner)
+			}
+			if tt.wantBanner == "" && strings.Contains(content, "Added by ignr") {
+				t.Errorf("%v output = %q, want no append banner", tt.args, content)
+			}
+		})
+	}
+}
+
+func TestGenerateCommandMerge(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".gitignore")
+	if err := os.WriteFile(path, []byte("# Existing\nold.txt\n*.exe"), 0o644); err != nil {
+		t.Fatalf("failed to write existing file: %v", err)
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		root := NewRootCommand(&Options{})
+		root.SetArgs(append([]string{"-C", dir, "generate", "--no-interactive", "--merge"}, args...))
+		var buf bytes.Buffer
+		root.SetOut(&buf)
+		root.SetErr(&buf)
+		if err := root.Execute(); err != nil {
+			t.Fatalf("generate --merge %v error = %v", args, err)
+		}
+		return buf.String()
+	}
+
+	run("Go")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	first := string(data)
+	if !strings.HasPrefix(first, "# Existing\nold.txt\n*.exe\n\n") {
+		t.Errorf("merged output = %q, want the existing content followed by a blank line", first)
+	}
+	if strings.Count(first, "*.exe") != 1 || strings.Count(first, "vendor/") != 1 {
+		t.Errorf("merged output = %q, want *.exe and vendor/ exactly once", first)
+	}
+
+	if out := run("Go"); !strings.Contains(out, "already has every rule") {
+		t.Errorf("second merge output = %q, want an up-to-date note", out)
+	}
+	if data, _ := os.ReadFile(path); string(data) != first {
+		t.Errorf("second merge changed the file:\n%s", data)
+	}
+
+	run("Go", "Node")
+	data, _ = os.ReadFile(path)
+	if content := string(data); strings.Count(content, "vendor/") != 1 || strings.Count(content, "node_modules/") != 1 {
+		t.Errorf("merged output = %q, want only the Node rules added", content)
+	}
+
+	root := NewRootCommand(&Options{})
+	root.SetArgs([]string{"-C", dir, "generate", "--no-interactive", "--merge", "--append", "Go"})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "--merge cannot be used") {
+		t.Errorf("--merge --append error = %v, want a conflict", err)
+	}
+}
+
+func TestGenerateCommandMergeKeepsOneHeader(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	for _, args := range [][]string{{"Go"}, {"--merge", "Node"}} {
+		root := NewRootCommand(&Options{})
+		root.SetArgs(append([]string{"-C", dir, "generate", "--no-interactive", "--force"}, args...))
+		root.SetOut(&bytes.Buffer{})
+		root.SetErr(&bytes.Buffer{})
+		if err := root.Execute(); err != nil {
+			t.Fatalf("generate %v error = %v", args, err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	content := string(data)
+	for _, line := range []string{"# Generated by ignr", "# Timestamp: ", "# Templates: "} {
+		if n := strings.Count(content, line); n != 1 {
+			t.Errorf("merged output has %d %q lines, want exactly one header:\n%s", n, line, content)
+		}
+	}
+	if !strings.Contains(content, "# Templates: Go\n") || !strings.Contains(content, "node_modules/") {
+		t.Errorf("merged output = %q, want the original header and the Node rules", content)
+	}
+}
+
+func TestGenerateCommandMergeTwiceIsStable(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name     string
+		existing string
+	}{
+		{name: "no file"},
+		{name: "trailing whitespace", existing: "*.exe  \nlocal/\t\n"},
+		{name: "crlf", existing: "*.exe\r\nlocal/\r\n"},
+		{name: "no final newline", existing: "local/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, ".gitignore")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0o644); err != nil {
+					t.Fatalf("failed to write existing file: %v", err)
+				}
+			}
+
+			var outputs []string
+			for i := 0; i < 2; i++ {
+				root := NewRootCommand(&Options{})
+				root.SetArgs([]string{"-C", dir, "generate", "--no-interactive", "--merge", "Go"})
+				root.SetOut(&bytes.Buffer{})
+				root.SetErr(&bytes.Buffer{})
+				if err := root.Execute(); err != nil {
+					t.Fatalf("generate --merge run %d error = %v", i+1, err)
+				}
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatalf("failed to read output: %v", err)
+				}
+				outputs = append(outputs, string(data))
+			}
+
+			if outputs[1] != outputs[0] {
+				t.Errorf("second merge changed the file:\nfirst:\n%s\nsecond:\n%s", outputs[0], outputs[1])
+			}
+			if n := strings.Count(outputs[0], "*.exe"); n != 1 {
+				t.Errorf("merged output = %q, want *.exe once, got %d", outputs[0], n)
+			}
+			if strings.Contains(outputs[0], "local/\n# ---") || strings.Contains(outputs[0], "local/\r\n# ---") {
+				t.Errorf("merged output = %q, want a blank line before the appended block", outputs[0])
+			}
+		})
+	}
+}
+
+func TestGenerateCommandNestedOutput(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{name: "nested --output", args: []string{"-o", "build/configs/.gitignore"}, want: "build/configs/.gitignore"},
+		{name: "nested --append", args: []string{"--append", "-o", "new/.gitignore"}, want: "new/.gitignore"},
+		{name: "--output-dir", args: []string{"--output-dir", "services/api"}, want: "services/api/.gitignore"},
+		{name: "--output-dir with --output", args: []string{"--output-dir", "web", "-o", ".dockerignore"}, want: "web/.dockerignore"},
+		{name: "--output-dir with stdout", args: []string{"--output-dir", "web", "-o", "-"}, wantErr: "--output-dir cannot be used"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			root := NewRootCommand(&Options{})
+			root.SetArgs(append([]string{"-C", dir, "generate", "--no-interactive", "Go"}, tt.args...))
+			var buf bytes.Buffer
+			root.SetOut(&buf)
+			root.SetErr(&buf)
+
+			err := root.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("generate %v error = %v, want %q", tt.args, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("generate %v error = %v", tt.args, err)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(tt.want)))
+			if err != nil {
+				t.Fatalf("generate %v did not create %s: %v", tt.args, tt.want, err)
+			}
+			if !strings.Contains(string(data), "vendor/") {
+				t.Errorf("%s = %q, want the Go template", tt.want, data)
+			}
+		})
+	}
+}
+
+func TestGenerateCommandStdout(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	existing := filepath.Join(dir, ".gitignore")
+	if err := os.WriteFile(existing, []byte("keep.txt\n"), 0o644); err != nil {
+		t.Fatalf("failed to write existing file: %v", err)
+	}
+
+	root := NewRootCommand(&Options{})
+	root.SetArgs([]string{"-C", dir, "generate", "--no-interactive", "--no-timestamp", "-o", "-", "Go"})
+	var stdout, stderr bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("generate -o - error = %v", err)
+	}
+
+	want := "# Generated by ignr " + Version + "\n# Templates: Go\n\n# --- Go ---\n# Go\n*.exe\nvendor/\n"
+	if stdout.String() != want {
+		t.Errorf("stdout = %q, want only the generated content %q", stdout.String(), want)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "keep.txt\n" {
+		t.Errorf("existing .gitignore = %q, want it untouched", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "-")); !os.IsNotExist(err) {
+		t.Error("generate -o - created a file named -")
+	}
+
+	root = NewRootCommand(&Options{})
+	root.SetArgs([]string{"-C", dir, "generate", "--no-interactive", "--append", "-o", "-", "Go"})
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	if err := root.Execute(); err == nil {
+		t.Error("generate --append -o - error = nil, want rejected")
+	}
+}
+
+func TestGenerateCommandStdinNames(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "spaces", input: "Go Python\n"},
+		{name: "newlines", input: "Go\n\nPython\n"},
+		{name: "unknown", input: "Go Rust\n", wantErr: "template not found: Rust"},
+		{name: "empty", input: "\n", wantErr: "no template names on stdin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), ".gitignore")
+			root := NewRootCommand(&Options{})
+			root.SetArgs([]string{"generate", "--stdin", "--output", outputPath})
+			root.SetIn(strings.NewReader(tt.input))
+			var stdout bytes.Buffer
+			root.SetOut(&stdout)
+			root.SetErr(&stdout)
+			err := root.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("generate --stdin error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("generate --stdin error = %v", err)
+			}
+			data, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			if !strings.Contains(string(data), "# --- Go ---") || !strings.Contains(string(data), "# --- Python ---") {
+				t.Errorf("output = %q, want Go and Python sections", data)
+			}
+		})
+	}
+}
+
+func TestLineEnding(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		config   string
+		wantCRLF bool
+	}{
+		{name: "default", args: []string{"generate", "--no-interactive", "Go"}},
+		{name: "crlf", args: []string{"generate", "--no-interactive", "--line-ending", "crlf", "Go"}, wantCRLF: true},
+		{name: "config crlf", args: []string{"generate", "--no-interactive", "Go"}, config: `{"line_ending": "crlf"}`, wantCRLF: true},
+		{name: "flag overrides config", args: []string{"generate", "--no-interactive", "--line-ending", "lf", "Go"}, config: `{"line_ending": "crlf"}`},
+		{name: "preset use crlf", args: []string{"preset", "use", "--line-ending", "crlf", "dev"}, wantCRLF: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupGenerateTest(t)
+			defer cleanup()
+			if tt.config != "" {
+				configPath := filepath.Join(xdg.ConfigHome, "ignr", "config.json")
+				if err := os.WriteFile(configPath, []byte(tt.config), 0o644); err != nil {
+					t.Fatalf("failed to write config: %v", err)
+				}
+			}
+			if err := presets.CreatePreset("dev", []string{"Go", "Node"}, nil); err != nil {
+				t.Fatalf("CreatePreset() error = %v", err)
+			}
+
+			outputPath := filepath.Join(t.TempDir(), ".gitignore")
+			root := NewRootCommand(&Options{})
+			root.SetArgs(append(tt.args, "--output", outputPath))
+			var stdout bytes.Buffer
+			root.SetOut(&stdout)
+			root.SetErr(&stdout)
+			if err := root.Execute(); err != nil {
+				t.Fatalf("%v error = %v", tt.args, err)
+			}
+
+			data, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			content := string(data)
+			if tt.wantCRLF {
+				if strings.Count(content, "\r\n") != strings.Count(content, "\n") {
+					t.Errorf("output = %q, want every line to end in \\r\\n", content)
+				}
+			} else if strings.Contains(content, "\r") {
+				t.Errorf("output = %q, want LF line endings only", content)
+			}
+		})
+	}
+
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+	root := NewRootCommand(&Options{})
+	root.SetArgs([]string{"generate", "--no-interactive", "--line-ending", "cr", "-o", filepath.Join(t.TempDir(), ".gitignore"), "Go"})
+	var stdout bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetErr(&stdout)
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "invalid line ending") {
+		t.Errorf("generate --line-ending cr error = %v, want invalid line ending", err)
+	}
+}
+
+func TestPrintTemplates(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "generate", args: []string{"generate", "--no-interactive", "--print-templates", "Python", "Go"}},
+		{name: "preset use", args: []string{"preset", "use", "--print-templates", "dev"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupGenerateTest(t)
+			defer cleanup()
+			if err := presets.CreatePreset("dev", []string{"Python", "Go"}, nil); err != nil {
+				t.Fatalf("CreatePreset() error = %v", err)
+			}
+
+			outputPath := filepath.Join(t.TempDir(), ".gitignore")
+			root := NewRootCommand(&Options{})
+			root.SetArgs(append(tt.args, "--output", outputPath))
+			var stdout, stderr bytes.Buffer
+			root.SetOut(&stdout)
+			root.SetErr(&stderr)
+			if err := root.Execute(); err != nil {
+				t.Fatalf("%v error = %v", tt.args, err)
+			}
+
+			data, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			var merged []string
+			for _, line := range strings.Split(string(data), "\n") {
+				if name, ok := strings.CutPrefix(line, "# --- "); ok {
+					merged = append(merged, strings.TrimSuffix(name, " ---"))
+				}
+			}
+			printed := strings.Fields(stderr.String())
+			if !reflect.DeepEqual(printed, merged) {
+				t.Errorf("printed templates = %v, want the merged sections %v", printed, merged)
+			}
+		})
+	}
+}
+
+func TestGenerateSections(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	root := NewRootCommand(&Options{})
+	root.SetArgs([]string{"generate", "--no-interactive", "--no-header", "--sections", "-o", "-", "Go", "Node"})
+	var stdout, stderr bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("generate --sections error = %v", err)
+	}
+	want := "### Go ###\n# Go\n*.exe\nvendor/\n\n### Node ###\n# Node\nnode_modules/\n*.log\n"
+	if stdout.String() != want {
+		t.Errorf("generate --sections = %q, want %q", stdout.String(), want)
+	}
+}
+
+func TestGenerateSelectFromDryRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		list    string
+		wantErr bool
+		want    []string
+	}{
+		{
+			name: "all valid",
+			list: "# team templates\nGo, node.gitignore\n\nPython\n",
+			want: []string{"templates.txt: all 3 templates found"},
+		},
+		{
+			name:    "invalid entries",
+			list:    "Go\nRust Node\n# comment\nElixir\n",
+			wantErr: true,
+			want:    []string{"templates.txt:2: template not found: Rust", "templates.txt:4: template not found: Elixir"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupGenerateTest(t)
+			defer cleanup()
+
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "templates.txt"), []byte(tt.list), 0o644); err != nil {
+				t.Fatalf("failed to write list: %v", err)
+			}
+			root := NewRootCommand(&Options{})
+			root.SetArgs([]string{"-C", dir, "generate", "--select-from", "templates.txt", "--dry-run"})
+			var stdout, stderr bytes.Buffer
+			root.SetOut(&stdout)
+			root.SetErr(&stderr)
+			err := root.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("generate --select-from --dry-run error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(stdout.String(), filepath.Join(dir, want)) {
+					t.Errorf("output = %q, want it to contain %q", stdout.String(), want)
+				}
+			}
+			if _, err := os.Stat(filepath.Join(dir, ".gitignore")); !os.IsNotExist(err) {
+				t.Errorf("--dry-run wrote .gitignore (stat error = %v)", err)
+			}
+		})
+	}
+}
+
+func TestExcludeTemplates(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "generate", args: []string{"generate", "--no-interactive", "Python", "Go", "Node"}},
+		{name: "preset use", args: []string{"preset", "use", "dev"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupGenerateTest(t)
+			defer cleanup()
+			if err := presets.CreatePreset("dev", []string{"Python", "Go", "Node"}, nil); err != nil {
+				t.Fatalf("CreatePreset() error = %v", err)
+			}
+
+			outputPath := filepath.Join(t.TempDir(), ".gitignore")
+			root := NewRootCommand(&Options{})
+			root.SetArgs(append(tt.args, "--output", outputPath, "--exclude", "node.gitignore", "--exclude", "Python", "--exclude", "Rust"))
+			var stdout, stderr bytes.Buffer
+			root.SetOut(&stdout)
+			root.SetErr(&stderr)
+			if err := root.Execute(); err != nil {
+				t.Fatalf("%v error = %v", tt.args, err)
+			}
+
+			data, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			content := string(data)
+			if !strings.Contains(content, "# --- Go ---") || strings.Contains(content, "# --- Node ---") || strings.Contains(content, "# --- Python ---") {
+				t.Errorf("output = %q, want only Go", content)
+			}
+			if !strings.Contains(stderr.String(), "warning: --exclude Rust matches no selected template") {
+				t.Errorf("stderr = %q, want a warning for the unmatched exclusion", stderr.String())
+			}
+		})
+	}
+}
+
+func TestPersonalCategoryRouting(t *testing.T) {
+	cleanup, _ := setupListTest(t)
+	defer cleanup()
+
+	if err := presets.CreatePreset("dev", []string{"Go", "macOS"}, nil); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+
+	runs := [][]string{
+		{"generate", "--no-interactive", "--personal", "Global", "Go", "macOS"},
+		{"preset", "use", "--personal", "global", "dev"},
+	}
+	for _, args := range runs {
+		dir := t.TempDir()
+		infoDir := filepath.Join(dir, ".git", "info")
+		if err := os.MkdirAll(infoDir, 0o755); err != nil {
+			t.Fatalf("failed to create git dir: %v", err)
+		}
+		// git init writes an exclude file holding only comments.
+		if err := os.WriteFile(filepath.Join(infoDir, "exclude"), []byte("# git ls-files --others --exclude-from=.git/info/exclude\n"), 0o644); err != nil {
+			t.Fatalf("failed to write exclude: %v", err)
+		}
+
+		root := NewRootCommand(&Options{})
+		root.SetArgs(append([]string{"-C", dir}, args...))
+		var buf bytes.Buffer
+		root.SetOut(&buf)
+		root.SetErr(&buf)
+		if err := root.Execute(); err != nil {
+			t.Fatalf("%v error = %v", args, err)
+		}
+
+		shared, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+		if err != nil {
+			t.Fatalf("failed to read .gitignore: %v", err)
+		}
+		personal, err := os.ReadFile(filepath.Join(infoDir, "exclude"))
+		if err != nil {
+			t.Fatalf("failed to read exclude: %v", err)
+		}
+		if !strings.Contains(string(shared), "# --- Go ---") || strings.Contains(string(shared), "macOS") {
+			t.Errorf("%v .gitignore = %q, want only Go", args, shared)
+		}
+		if !strings.Contains(string(personal), "# --- macOS ---") || strings.Contains(string(personal), "# --- Go ---") {
+			t.Errorf("%v exclude = %q, want only macOS", args, personal)
+		}
+	}
+
+	root := NewRootCommand(&Options{})
+	root.SetArgs([]string{"-C", t.TempDir(), "generate", "--no-interactive", "--personal", "Global", "macOS"})
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "git repository") {
+		t.Errorf("--personal outside a repo error = %v, want git repository error", err)
+	}
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"go.seanlatimer.dev/ignr/internal/cache"
+)
+
+func newInitCommand(opts *Options) *cobra.Command {
+	var force bool
+	var ref string
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Clone the gitignore template cache",
+		Long:  "Clone the github/gitignore template repository into the cache so later commands work offline.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			refChanged := cmd.Flags().Changed("ref")
+			if refChanged {
+				if err := saveTemplateRepoRef(ref); err != nil {
+					return err
+				}
+			}
+
+			initialized, err := cache.IsCacheInitialized()
+			if err != nil {
+				return err
+			}
+
+			var cachePath string
+			switch {
+			case initialized && !force && !refChanged:
+				cachePath, err = cache.GetCachePath(cache.DefaultSource)
+				if err != nil {
+					return err
+				}
+				infof(cmd, opts, "Cache already initialized at %s (use --force to re-clone)", cachePath)
+				if head, err := cache.GetHeadCommit(cachePath); err == nil {
+					infof(cmd, opts, "HEAD %s", head)
+				}
+				return nil
+			case force:
+				cachePath, err = cache.ReinitializeCache(cmd.Context())
+			default:
+				// InitializeCache re-clones an existing cache whose ref no longer matches.
+				cachePath, err = cache.InitializeCache(cmd.Context())
+			}
+			if err != nil {
+				return err
+			}
+
+			head, err := cache.GetHeadCommit(cachePath)
+			if err != nil {
+				return err
+			}
+			infof(cmd, opts, "Initialized cache at %s", cachePath)
+			infof(cmd, opts, "HEAD %s", head)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Delete the existing cache and clone it again")
+	cmd.Flags().StringVar(&ref, "ref", "", "Clone the template cache at a branch, tag, or commit (empty follows the default branch)")
+
+	return cmd
+}
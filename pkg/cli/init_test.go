@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/adrg/xdg"
+)
+
+func TestInitCommandAlreadyInitialized(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	root := NewRootCommand(&Options{})
+	root.SetArgs([]string{"init"})
+	var stdout bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetErr(&stdout)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("init error = %v", err)
+	}
+
+	out := stdout.String()
+	if !strings.Contains(out, "Cache already initialized at ") {
+		t.Errorf("init output = %q, want already-initialized message", out)
+	}
+	if !strings.Contains(out, xdg.ConfigHome) {
+		t.Errorf("init output = %q, want cache path under %s", out, xdg.ConfigHome)
+	}
+	if !strings.Contains(out, "--force") {
+		t.Errorf("init output = %q, want --force hint", out)
+	}
+}
+
+func TestNewInitCommand(t *testing.T) {
+	cmd := newInitCommand(&Options{})
+	if cmd.Use != "init" {
+		t.Errorf("Use = %q, want init", cmd.Use)
+	}
+	if cmd.Flags().Lookup("force") == nil {
+		t.Error("init command missing --force flag")
+	}
+	if cmd.Flags().Lookup("ref") == nil {
+		t.Error("init command missing --ref flag")
+	}
+	if err := cmd.Args(cmd, []string{"extra"}); err == nil {
+		t.Error("init accepted positional arguments")
+	}
+}
@@ -0,0 +1,17 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// writeJSON writes v as JSON followed by a newline: indented by two spaces for reading, or on
+// one line when compact is set, for piping. Fields keep their struct order, so output is stable
+// across runs; every --json output goes through here.
+func writeJSON(w io.Writer, v any, compact bool) error {
+	encoder := json.NewEncoder(w)
+	if !compact {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(v)
+}
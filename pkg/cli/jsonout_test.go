@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteJSON(t *testing.T) {
+	value := []templateJSON{{Name: "Go", Category: "root", Source: "cache", Path: "/cache/Go.gitignore"}}
+	tests := []struct {
+		name    string
+		compact bool
+		want    string
+	}{
+		{
+			name: "pretty",
+			want: "[\n" +
+				"  {\n" +
+				"    \"name\": \"Go\",\n" +
+				"    \"category\": \"root\",\n" +
+				"    \"source\": \"cache\",\n" +
+				"    \"path\": \"/cache/Go.gitignore\"\n" +
+				"  }\n" +
+				"]\n",
+		},
+		{
+			name:    "compact",
+			compact: true,
+			want:    `[{"name":"Go","category":"root","source":"cache","path":"/cache/Go.gitignore"}]` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 3 {
+				var buf bytes.Buffer
+				if err := writeJSON(&buf, value, tt.compact); err != nil {
+					t.Fatalf("writeJSON() error = %v", err)
+				}
+				if got := buf.String(); got != tt.want {
+					t.Fatalf("writeJSON() = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
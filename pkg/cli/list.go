@@ -1,42 +1,265 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"go.seanlatimer.dev/ignr/internal/cache"
+	"go.seanlatimer.dev/ignr/internal/config"
 	"go.seanlatimer.dev/ignr/internal/templates"
 )
 
 func newListCommand(opts *Options) *cobra.Command {
 	var category string
+	var showDates bool
+	var tree bool
+	var table bool
+	var showHidden bool
+	var namesOnly bool
+	var offline bool
+	var format string
+	var compact bool
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List available gitignore templates",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cachePath, err := cache.InitializeCache()
+			if format != listFormatText && format != listFormatJSON {
+				return fmt.Errorf("invalid format %q: must be %s or %s", format, listFormatText, listFormatJSON)
+			}
+			if format == listFormatJSON && (tree || table || namesOnly) {
+				return fmt.Errorf("--format json cannot be combined with --tree, --table, or --names-only")
+			}
+
+			cachePath, err := readCache(cmd, offline)
 			if err != nil {
 				return err
 			}
+			debugf(cmd, opts, "template cache: %s", cachePath)
 
 			items, err := templates.DiscoverTemplates(cachePath)
 			if err != nil {
 				return err
 			}
+			debugf(cmd, opts, "discovered %d cached templates", len(items))
+			if !showHidden {
+				cfg, err := config.LoadConfig()
+				if err != nil {
+					return err
+				}
+				items = templates.WithoutCategories(items, cfg.HiddenCategories)
+			}
 
 			categoryFilter := strings.ToLower(strings.TrimSpace(category))
+			filtered := make([]templates.Template, 0, len(items))
 			for _, item := range items {
 				if categoryFilter != "" && strings.ToLower(string(item.Category)) != categoryFilter {
 					continue
 				}
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[%s] %s\n", item.Category, item.Name)
+				filtered = append(filtered, item)
+			}
+
+			if namesOnly {
+				for _, item := range filtered {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), item.Name)
+				}
+				return nil
+			}
+
+			var dates map[string]time.Time
+			if showDates {
+				dates, err = templateDates(cmd, cachePath, filtered)
+				if err != nil {
+					return err
+				}
+			}
+			label := func(item templates.Template) string {
+				if !showDates {
+					return item.Name
+				}
+				date := "unknown"
+				if when, ok := dates[item.Path]; ok {
+					date = when.Format(time.DateOnly)
+				}
+				return fmt.Sprintf("%s (%s)", item.Name, date)
+			}
+
+			if format == listFormatJSON {
+				return writeTemplatesJSON(cmd.OutOrStdout(), filtered, dates, compact)
+			}
+			if tree {
+				writeTemplateTree(cmd, filtered, label)
+				return nil
+			}
+			if isTTY, width := terminalWidth(cmd.OutOrStdout()); table && isTTY {
+				headers := []string{"Name", "Category", "Source"}
+				if showDates {
+					headers = append(headers, "Updated")
+				}
+				rows := make([][]string, 0, len(filtered))
+				for _, item := range filtered {
+					row := []string{item.Name, item.QualifiedCategory(), string(item.Source)}
+					if showDates {
+						date := "unknown"
+						if when, ok := dates[item.Path]; ok {
+							date = when.Format(time.DateOnly)
+						}
+						row = append(row, date)
+					}
+					rows = append(rows, row)
+				}
+				writeTable(cmd.OutOrStdout(), headers, rows, width)
+				return nil
+			}
+			for _, item := range filtered {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[%s] %s\n", item.Category, label(item))
 			}
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&category, "category", "", "Filter by category (root, Global, community)")
+	cmd.Flags().BoolVar(&showDates, "show-dates", false, "Show the last commit date of each template")
+	cmd.Flags().BoolVar(&tree, "tree", false, "Group templates by category and subcategory")
+	cmd.Flags().BoolVar(&table, "table", false, "Show aligned columns when writing to a terminal")
+	cmd.Flags().BoolVar(&showHidden, "show-hidden", false, "Include categories hidden by hidden_categories in config")
+	cmd.Flags().BoolVar(&namesOnly, "names-only", false, "Print only template names, one per line, for piping")
+	cmd.Flags().StringVar(&format, "format", listFormatText, "Output format: text, or json for an array of templates")
+	cmd.Flags().BoolVar(&compact, "compact", false, "With --format json, print the JSON on one line")
+	cmd.Flags().BoolVar(&offline, "offline", false, "Never touch the network; fail if the cache is missing")
+	cmd.MarkFlagsMutuallyExclusive("tree", "table", "names-only")
+	cmd.MarkFlagsMutuallyExclusive("show-dates", "names-only")
 	return cmd
 }
+
+const (
+	listFormatText = "text"
+	listFormatJSON = "json"
+)
+
+// templateJSON is the --format json form of a template.
+type templateJSON struct {
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	Subcategory string `json:"subcategory,omitempty"`
+	Source      string `json:"source"`
+	Path        string `json:"path"`
+	Updated     string `json:"updated,omitempty"`
+}
+
+// writeTemplatesJSON writes items as a JSON array; an empty list is written as [].
+// dates, when set by --show-dates, fills in each template's last commit date.
+func writeTemplatesJSON(w io.Writer, items []templates.Template, dates map[string]time.Time, compact bool) error {
+	out := make([]templateJSON, 0, len(items))
+	for _, item := range items {
+		entry := newTemplateJSON(item)
+		if when, ok := dates[item.Path]; ok {
+			entry.Updated = when.Format(time.DateOnly)
+		}
+		out = append(out, entry)
+	}
+	return writeJSON(w, out, compact)
+}
+
+// newTemplateJSON converts item to its JSON form, without the last commit date.
+func newTemplateJSON(item templates.Template) templateJSON {
+	return templateJSON{
+		Name:        item.Name,
+		Category:    string(item.Category),
+		Subcategory: item.Subcategory,
+		Source:      string(item.Source),
+		Path:        item.Path,
+	}
+}
+
+// errNoTemplatesCached is returned by read-only commands that find no cache and must not clone one.
+var errNoTemplatesCached = errors.New("no templates cached; run `ignr init` to clone them")
+
+// readCache returns the cache path for read-only commands. A missing cache is only cloned
+// when writing to a terminal and not offline, so scripts never trigger a surprise download.
+func readCache(cmd *cobra.Command, offline bool) (string, error) {
+	initialized, err := cache.IsCacheInitialized()
+	if err != nil {
+		return "", err
+	}
+	if !initialized {
+		if isTTY, _ := terminalWidth(cmd.OutOrStdout()); offline || !isTTY {
+			return "", errNoTemplatesCached
+		}
+	}
+	if offline {
+		return cache.GetCachePath(cache.DefaultSource)
+	}
+	return cache.InitializeCache(cmd.Context())
+}
+
+// templateDates maps template paths to their last commit date in the cache repo.
+func templateDates(cmd *cobra.Command, cachePath string, items []templates.Template) (map[string]time.Time, error) {
+	relPaths := make([]string, 0, len(items))
+	byRel := make(map[string]string, len(items))
+	for _, item := range items {
+		rel, err := filepath.Rel(cachePath, item.Path)
+		if err != nil {
+			return nil, fmt.Errorf("rel path: %w", err)
+		}
+		rel = filepath.ToSlash(rel)
+		relPaths = append(relPaths, rel)
+		byRel[rel] = item.Path
+	}
+
+	shallow, err := cache.IsShallowRepo(cachePath)
+	if err != nil {
+		return nil, err
+	}
+	if shallow {
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "note: template cache is a shallow clone; some dates are unavailable")
+	}
+
+	relDates, err := cache.GetLastCommitDates(cachePath, relPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	dates := make(map[string]time.Time, len(relDates))
+	for rel, when := range relDates {
+		dates[byRel[rel]] = when
+	}
+	return dates, nil
+}
+
+// writeTemplateTree prints templates grouped by category, then subcategory, in discovery order.
+func writeTemplateTree(cmd *cobra.Command, items []templates.Template, label func(templates.Template) string) {
+	categories := []templates.Category{}
+	subcategories := map[templates.Category][]string{}
+	grouped := map[string][]templates.Template{}
+	for _, item := range items {
+		if _, ok := subcategories[item.Category]; !ok {
+			categories = append(categories, item.Category)
+			subcategories[item.Category] = []string{}
+		}
+		key := item.QualifiedCategory()
+		if _, ok := grouped[key]; !ok && item.Subcategory != "" {
+			subcategories[item.Category] = append(subcategories[item.Category], item.Subcategory)
+		}
+		grouped[key] = append(grouped[key], item)
+	}
+
+	out := cmd.OutOrStdout()
+	for _, category := range categories {
+		_, _ = fmt.Fprintf(out, "%s\n", category)
+		for _, item := range grouped[string(category)] {
+			_, _ = fmt.Fprintf(out, "  %s\n", label(item))
+		}
+		for _, sub := range subcategories[category] {
+			_, _ = fmt.Fprintf(out, "  %s/\n", sub)
+			for _, item := range grouped[string(category)+"/"+sub] {
+				_, _ = fmt.Fprintf(out, "    %s\n", label(item))
+			}
+		}
+	}
+}
@@ -2,8 +2,12 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -169,9 +173,194 @@ func TestListCommandWithCategoryFilter(t *testing.T) {
 	}
 }
 
+func TestListCommandTree(t *testing.T) {
+	cleanup, cachePath := setupListTest(t)
+	defer cleanup()
+
+	vueDir := filepath.Join(cachePath, "community", "JavaScript")
+	if err := os.MkdirAll(vueDir, 0o755); err != nil {
+		t.Fatalf("failed to create community dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(vueDir, "Vue.gitignore"), []byte("# Vue"), 0o644); err != nil {
+		t.Fatalf("failed to create community template: %v", err)
+	}
+
+	cmd := newListCommand(&Options{})
+	cmd.SetArgs([]string{"--tree", "--category", "community"})
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("list --tree error = %v", err)
+	}
+
+	want := "community\n  JavaScript/\n    Vue\n"
+	if buf.String() != want {
+		t.Errorf("list --tree output = %q, want %q", buf.String(), want)
+	}
+}
+
 func TestListCommandEmptyCache(t *testing.T) {
-	// Note: This test may pass if a real cache exists on the system
-	// since cache.InitializeCache() will use the real cache if it exists
-	// To test uninitialized cache, we'd need to mock or disable cache initialization
-	t.Skip("Skipping test - InitializeCache uses real cache directory which may be initialized")
+	cleanup := setupUpdateTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "list non-interactive", args: []string{"list"}},
+		{name: "list offline", args: []string{"list", "--offline"}},
+		{name: "search non-interactive", args: []string{"search", "go"}},
+		{name: "search offline", args: []string{"search", "--offline", "go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := NewRootCommand(&Options{})
+			root.SetArgs(tt.args)
+			var buf bytes.Buffer
+			root.SetOut(&buf)
+			root.SetErr(&buf)
+
+			err := root.Execute()
+			if !errors.Is(err, errNoTemplatesCached) {
+				t.Fatalf("%v error = %v, want %v", tt.args, err, errNoTemplatesCached)
+			}
+			if _, statErr := os.Stat(filepath.Join(xdg.ConfigHome, "ignr", "cache")); !os.IsNotExist(statErr) {
+				t.Errorf("%v created the cache dir, want no clone attempt", tt.args)
+			}
+		})
+	}
+}
+
+func TestHiddenCategories(t *testing.T) {
+	cleanup, _ := setupListTest(t)
+	defer cleanup()
+
+	configPath := filepath.Join(xdg.ConfigHome, "ignr", "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"hidden_categories": ["global"]}`), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	run := func(cmd interface {
+		SetArgs([]string)
+		SetOut(io.Writer)
+		SetErr(io.Writer)
+		Execute() error
+	}, args ...string) string {
+		t.Helper()
+		var buf bytes.Buffer
+		cmd.SetArgs(args)
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v error = %v", args, err)
+		}
+		return buf.String()
+	}
+
+	if out := run(newListCommand(&Options{})); strings.Contains(out, "macOS") || !strings.Contains(out, "Go") {
+		t.Errorf("list output = %q, want Global templates hidden", out)
+	}
+	if out := run(newListCommand(&Options{}), "--show-hidden"); !strings.Contains(out, "macOS") {
+		t.Errorf("list --show-hidden output = %q, want macOS", out)
+	}
+	if out := run(newSearchCommand(&Options{}), "mac"); strings.Contains(out, "macOS") {
+		t.Errorf("search output = %q, want Global templates hidden", out)
+	}
+	if out := run(newSearchCommand(&Options{}), "--show-hidden", "mac"); !strings.Contains(out, "macOS") {
+		t.Errorf("search --show-hidden output = %q, want macOS", out)
+	}
+
+	dir := t.TempDir()
+	run(newGenerateCommand(&Options{Chdir: dir}), "--no-interactive", "macOS")
+	data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(string(data), "# --- macOS ---") {
+		t.Errorf("generate macOS output = %q, want hidden template resolved by name", data)
+	}
+}
+
+func TestListCommandNamesOnly(t *testing.T) {
+	cleanup, _ := setupListTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "all templates", args: []string{"--names-only"}, want: "macOS\nGo\nNode\nPython\n"},
+		{name: "category filter", args: []string{"--names-only", "--category", "Global"}, want: "macOS\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newListCommand(&Options{})
+			cmd.SetArgs(tt.args)
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("list %v error = %v", tt.args, err)
+			}
+			if strings.Contains(buf.String(), "[") {
+				t.Errorf("list %v output = %q, want no category brackets", tt.args, buf.String())
+			}
+			if buf.String() != tt.want {
+				t.Errorf("list %v output = %q, want %q", tt.args, buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestListCommandJSON(t *testing.T) {
+	cleanup, cachePath := setupListTest(t)
+	defer cleanup()
+
+	cmd := newListCommand(&Options{})
+	cmd.SetArgs([]string{"--format", "json", "--category", "Global"})
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("list --format json error = %v", err)
+	}
+
+	var got []map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not a JSON array: %v\n%s", err, buf.String())
+	}
+	want := []map[string]string{{
+		"name":     "macOS",
+		"category": "Global",
+		"source":   "cache",
+		"path":     filepath.Join(cachePath, "Global", "macOS.gitignore"),
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("list --format json = %v, want %v", got, want)
+	}
+
+	cmd = newListCommand(&Options{})
+	cmd.SetArgs([]string{"--format", "json", "--category", "nope"})
+	buf.Reset()
+	cmd.SetOut(&buf)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("list --format json error = %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("list --format json with no matches = %q, want []", buf.String())
+	}
+
+	for _, args := range [][]string{{"--format", "yaml"}, {"--format", "json", "--tree"}} {
+		cmd = newListCommand(&Options{})
+		cmd.SetArgs(args)
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		if err := cmd.Execute(); err == nil {
+			t.Errorf("list %v error = nil, want rejected", args)
+		}
+	}
 }
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.seanlatimer.dev/ignr/internal/ignorefile"
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+// splitPersonal separates the selected templates in category, which go to .git/info/exclude,
+// from the rest, which go to the shared output file.
+func splitPersonal(selected []templates.Template, category string) (shared, personal []templates.Template, err error) {
+	personal = templates.OnlyCategory(selected, category)
+	if len(personal) == 0 {
+		return nil, nil, fmt.Errorf("no selected templates in category %s for --personal", category)
+	}
+	return templates.WithoutCategories(selected, []templates.Category{templates.Category(category)}), personal, nil
+}
+
+// infoExcludePath returns the .git/info/exclude file of the repository at baseDir.
+func infoExcludePath(baseDir string) (string, error) {
+	gitDir := filepath.Join(baseDir, ".git")
+	info, err := os.Stat(gitDir)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("--personal needs a git repository: no .git directory in %s", baseDir)
+	}
+	infoDir := filepath.Join(gitDir, "info")
+	if err := os.MkdirAll(infoDir, 0o755); err != nil {
+		return "", fmt.Errorf("create %s: %w", infoDir, err)
+	}
+	return filepath.Join(infoDir, "exclude"), nil
+}
+
+// writePersonal merges personal into baseDir's .git/info/exclude and returns its path. The
+// comment-only exclude file git creates is replaced; any other content follows the same
+// --append and --force rules as the shared output.
+func writePersonal(cmd *cobra.Command, baseDir string, personal []templates.Template, mergeOptions templates.MergeOptions, appendMode, force, interactive bool) (string, error) {
+	target, err := infoExcludePath(baseDir)
+	if err != nil {
+		return "", err
+	}
+	loaded, err := templates.LoadTemplates(personal)
+	if err != nil {
+		return "", err
+	}
+	content := templates.MergeTemplates(loaded, mergeOptions)
+
+	if !onlyComments(target) {
+		if err := handleExistingOutput(cmd, target, appendMode, force, interactive, personal); err != nil {
+			return "", err
+		}
+	} else {
+		appendMode = false
+	}
+	// git reads either line ending; the local exclude file keeps LF.
+	if err := ignorefile.Write(target, content, appendMode, ignorefile.LineEndingLF); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// onlyComments reports whether path is missing or holds nothing but blank and comment lines,
+// like the exclude file git init writes.
+func onlyComments(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return os.IsNotExist(err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
@@ -4,14 +4,18 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"go.seanlatimer.dev/ignr/internal/cache"
 	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/ignorefile"
 	"go.seanlatimer.dev/ignr/internal/presets"
+	"go.seanlatimer.dev/ignr/internal/remote"
 	"go.seanlatimer.dev/ignr/internal/templates"
 	"go.seanlatimer.dev/ignr/internal/tui"
 )
@@ -23,12 +27,23 @@ func newPresetCommand(opts *Options) *cobra.Command {
 	showCmd := newPresetShowCommand(opts)
 	deleteCmd := newPresetDeleteCommand(opts)
 	useCmd := newPresetUseCommand(opts)
+	lintCmd := newPresetLintCommand(opts)
+	exportCmd := newPresetExportCommand(opts)
+	importCmd := newPresetImportCommand(opts)
+	renameCmd := newPresetRenameCommand(opts)
+	duplicateCmd := newPresetDuplicateCommand(opts)
 
+	var offline bool
+	var noInteractive bool
 	cmd := &cobra.Command{
 		Use:   "preset",
 		Short: "Manage template presets",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			err := tui.ShowPresetApp()
+			// The TUI needs a terminal; scripts and pipes get the subcommand list instead.
+			if isTTY, _ := terminalWidth(cmd.OutOrStdout()); noInteractive || !isTTY {
+				return cmd.Help()
+			}
+			err := tui.ShowPresetApp(opts.BaseDir(), offline)
 			if err != nil {
 				if errors.Is(err, tui.ErrCancelled) {
 					return nil
@@ -39,19 +54,29 @@ func newPresetCommand(opts *Options) *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&offline, "offline", false, "Skip checking the remote for template updates")
+	cmd.Flags().BoolVar(&noInteractive, "no-interactive", false, "Print subcommand help instead of opening the TUI")
 	cmd.AddCommand(
 		createCmd,
 		editCmd,
 		listCmd,
 		showCmd,
 		deleteCmd,
+		renameCmd,
+		duplicateCmd,
 		useCmd,
+		lintCmd,
+		exportCmd,
+		importCmd,
 	)
 	return cmd
 }
 
 func newPresetCreateCommand(opts *Options) *cobra.Command {
 	var noInteractive bool
+	var noValidate bool
+	var description string
+	var showHidden bool
 	cmd := &cobra.Command{
 		Use:   "create [name] [template1 template2...]",
 		Short: "Create a preset from template names",
@@ -65,7 +90,7 @@ func newPresetCreateCommand(opts *Options) *cobra.Command {
 				}
 			}
 
-			items, err := discoverAllTemplates()
+			items, err := discoverAllTemplates(cmd, opts)
 			if err != nil {
 				return err
 			}
@@ -74,68 +99,195 @@ func newPresetCreateCommand(opts *Options) *cobra.Command {
 				if strings.TrimSpace(name) == "" {
 					return fmt.Errorf("preset name is required in non-interactive mode")
 				}
-				index := templates.BuildIndex(items)
-				for _, tmpl := range templateNames {
-					if _, ok := templates.FindTemplate(index, tmpl); !ok {
-						return fmt.Errorf("template not found: %s", tmpl)
+				index := templateIndex(items, noValidate)
+				if hasTemplateExpr(templateNames) {
+					templateNames, err = presets.ParseTemplateExpr(strings.Join(templateNames, ","), index)
+					if err != nil {
+						return err
+					}
+				} else {
+					templateNames, err = dedupeTemplateArgs(cmd, opts, templateNames)
+					if err != nil {
+						return err
 					}
 				}
-				if err := presets.CreatePreset(name, templateNames); err != nil {
+				if err := createPreset(cmd, opts, name, description, templateNames, index); err != nil {
 					return err
 				}
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created preset %s with %d templates\n", name, len(templateNames))
+				infof(cmd, opts, "Created preset %s with %d templates", name, len(templateNames))
 				return nil
 			}
 
-			existingKeys, err := presetKeys()
-			if err != nil {
-				return err
-			}
-
-			if strings.TrimSpace(name) == "" {
-				name, err = tui.ShowPresetNameInput("Preset name:", existingKeys, false)
-				if err != nil {
-					if errors.Is(err, tui.ErrCancelled) {
-						return nil
-					}
-					return err
-				}
-			} else {
-				key := presets.SluggifyName(name)
-				if presetKeyExists(existingKeys, key) {
-					return fmt.Errorf("preset key already exists: %s", key)
-				}
-			}
+			return createPresetInteractive(cmd, opts, items, name, description, showHidden)
+		},
+	}
+	cmd.ValidArgsFunction = completePresetTemplateArgs(nil)
+	cmd.Flags().BoolVar(&noInteractive, "no-interactive", false, "Disable interactive selection")
+	cmd.Flags().BoolVar(&noValidate, "no-validate", false, "Allow template names that are not in the cache or user templates")
+	cmd.Flags().StringVar(&description, "description", "", "A short note on what the preset is for")
+	cmd.Flags().BoolVar(&showHidden, "show-hidden", false, "Include categories hidden by hidden_categories in config in the selector")
+	return cmd
+}
 
-			selected, err := tui.ShowInteractiveSelector(items, nil, nil, nil)
-			if err != nil {
-				if errors.Is(err, tui.ErrCancelled) {
-					return nil
-				}
-				return err
-			}
-			if len(selected) == 0 {
-				return fmt.Errorf("no templates selected")
-			}
+// createPresetInteractive prompts for a name (unless given) and templates, then saves the preset.
+// Unless showHidden, the selector leaves out the hidden_categories from config.
+func createPresetInteractive(cmd *cobra.Command, opts *Options, items []templates.Template, name, description string, showHidden bool) error {
+	existingKeys, err := presetKeys()
+	if err != nil {
+		return err
+	}
 
-			templateNames = make([]string, 0, len(selected))
-			for _, tmpl := range selected {
-				templateNames = append(templateNames, tmpl.Name)
+	if strings.TrimSpace(name) == "" {
+		name, err = tui.ShowPresetNameInput("Preset name:", existingKeys, false)
+		if err != nil {
+			if errors.Is(err, tui.ErrCancelled) {
+				return nil
 			}
+			return err
+		}
+	} else {
+		key := presets.SluggifyName(name)
+		if presetKeyExists(existingKeys, key) {
+			return fmt.Errorf("%w: %s", presets.ErrPresetExists, key)
+		}
+	}
 
-			if err := presets.CreatePreset(name, templateNames); err != nil {
-				return err
-			}
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created preset %s with %d templates\n", name, len(templateNames))
+	visible, err := selectorTemplates(items, showHidden, nil)
+	if err != nil {
+		return err
+	}
+	selected, err := tui.ShowInteractiveSelector(visible, nil, nil, nil, "")
+	if err != nil {
+		if errors.Is(err, tui.ErrCancelled) {
 			return nil
-		},
+		}
+		return err
 	}
-	cmd.Flags().BoolVar(&noInteractive, "no-interactive", false, "Disable interactive selection")
-	return cmd
+	if len(selected) == 0 {
+		return fmt.Errorf("no templates selected")
+	}
+
+	templateNames := make([]string, 0, len(selected))
+	for _, tmpl := range selected {
+		templateNames = append(templateNames, tmpl.Name)
+	}
+
+	if err := createPreset(cmd, opts, name, description, templateNames, templateIndex(items, false)); err != nil {
+		return err
+	}
+	infof(cmd, opts, "Created preset %s with %d templates", name, len(templateNames))
+	return nil
+}
+
+// selectorTemplates returns the templates offered in a preset selector: items without the
+// hidden_categories from config unless showHidden. Templates named in keep stay listed, so
+// editing a preset never silently drops one of its hidden templates.
+func selectorTemplates(items []templates.Template, showHidden bool, keep []string) ([]templates.Template, error) {
+	if showHidden {
+		return items, nil
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	visible := templates.WithoutCategories(items, cfg.HiddenCategories)
+	if len(keep) == 0 || len(visible) == len(items) {
+		return visible, nil
+	}
+	listed := make(map[string]bool, len(visible))
+	for _, item := range visible {
+		listed[item.Path] = true
+	}
+	index := templates.BuildIndex(items)
+	for _, name := range keep {
+		if t, ok := templates.FindTemplate(index, name); ok && !listed[t.Path] {
+			visible = append(visible, t)
+			listed[t.Path] = true
+		}
+	}
+	return visible, nil
+}
+
+// createPreset saves a new preset, warning when its display name is already
+// used by a preset with a different key. Under --strict that warning fails before saving.
+func createPreset(cmd *cobra.Command, opts *Options, name, description string, templateNames []string, index *templates.Index) error {
+	duplicates, err := presets.DuplicateNames(name)
+	if err != nil {
+		return err
+	}
+	for _, preset := range duplicates {
+		if err := warn(cmd, opts, "preset %s is also named %q; refer to presets by key to avoid ambiguity", preset.Key, preset.Name); err != nil {
+			return err
+		}
+	}
+	return presets.CreatePresetWithDescription(name, description, templateNames, index)
+}
+
+// dedupeTemplateArgs drops repeated template names, warning about any it removes.
+func dedupeTemplateArgs(cmd *cobra.Command, opts *Options, templateNames []string) ([]string, error) {
+	unique, removed := presets.DedupeTemplates(templateNames)
+	if len(removed) > 0 {
+		if err := warn(cmd, opts, "removed duplicate templates: %s", strings.Join(removed, ", ")); err != nil {
+			return nil, err
+		}
+	}
+	return unique, nil
+}
+
+// hasTemplateExpr reports whether any template argument uses set expression syntax.
+func hasTemplateExpr(args []string) bool {
+	for _, arg := range args {
+		if presets.IsTemplateExpr(arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// templateIndex builds the index used to validate preset templates, or nil when validation is disabled.
+func templateIndex(items []templates.Template, noValidate bool) *templates.Index {
+	if noValidate {
+		return nil
+	}
+	index := templates.BuildIndex(items)
+	return &index
+}
+
+// offerPresetCreate handles an empty preset store by offering to run the create flow.
+func offerPresetCreate(cmd *cobra.Command, opts *Options) error {
+	_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No presets found.")
+	confirm, err := confirmPrompt(cmd, "Create one now?")
+	if err != nil {
+		return err
+	}
+	if !confirm {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Create one with: ignr preset create <name> <template...>")
+		return nil
+	}
+
+	items, err := discoverAllTemplates(cmd, opts)
+	if err != nil {
+		return err
+	}
+	return createPresetInteractive(cmd, opts, items, "", "", false)
+}
+
+// presetJSON is the --json form of a preset.
+type presetJSON struct {
+	Key         string   `json:"key"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Templates   []string `json:"templates"`
+	Created     string   `json:"created,omitempty"`
+	Updated     string   `json:"updated,omitempty"`
 }
 
 func newPresetListCommand(opts *Options) *cobra.Command {
-	return &cobra.Command{
+	var table bool
+	var jsonOutput bool
+	var compact bool
+	var porcelain bool
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List presets",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -143,24 +295,84 @@ func newPresetListCommand(opts *Options) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			// Machine-readable modes stay parseable when the store is empty: [] or no lines.
+			switch {
+			case jsonOutput:
+				return writePresetsJSON(cmd.OutOrStdout(), list, compact)
+			case porcelain:
+				for _, preset := range list {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", presetKey(preset), preset.Name, strings.Join(preset.Templates, ","))
+				}
+				return nil
+			}
 			if len(list) == 0 {
 				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No presets found.")
 				return nil
 			}
+			isTTY, width := terminalWidth(cmd.OutOrStdout())
+			rows := make([][]string, 0, len(list))
 			for _, preset := range list {
-				key := preset.Key
-				if strings.TrimSpace(key) == "" {
-					key = presets.SluggifyName(preset.Name)
+				key := presetKey(preset)
+				if table && isTTY {
+					rows = append(rows, []string{preset.Name, key, strconv.Itoa(len(preset.Templates))})
+					continue
 				}
 				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s [%s] (%d templates)\n", preset.Name, key, len(preset.Templates))
 			}
+			if table && isTTY {
+				writeTable(cmd.OutOrStdout(), []string{"Name", "Key", "Templates"}, rows, width)
+			}
 			return nil
 		},
 	}
+	cmd.Flags().BoolVar(&table, "table", false, "Show aligned columns when writing to a terminal")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print presets as a JSON array")
+	cmd.Flags().BoolVar(&compact, "compact", false, "With --json, print the JSON on one line")
+	cmd.Flags().BoolVar(&porcelain, "porcelain", false, "Print one tab-separated key, name, and comma-separated templates line per preset")
+	cmd.MarkFlagsMutuallyExclusive("table", "json", "porcelain")
+	return cmd
+}
+
+// presetKey returns the preset's key, deriving it from the name for presets saved without one.
+func presetKey(preset presets.Preset) string {
+	if strings.TrimSpace(preset.Key) == "" {
+		return presets.SluggifyName(preset.Name)
+	}
+	return preset.Key
+}
+
+// writePresetsJSON writes list as a JSON array; an empty list is written as [].
+func writePresetsJSON(w io.Writer, list []presets.Preset, compact bool) error {
+	out := make([]presetJSON, 0, len(list))
+	for _, preset := range list {
+		out = append(out, newPresetJSON(preset))
+	}
+	return writeJSON(w, out, compact)
+}
+
+// newPresetJSON converts preset to its --json form; missing templates are written as [].
+func newPresetJSON(preset presets.Preset) presetJSON {
+	templateNames := preset.Templates
+	if templateNames == nil {
+		templateNames = []string{}
+	}
+	return presetJSON{
+		Key:         presetKey(preset),
+		Name:        preset.Name,
+		Description: preset.Description,
+		Templates:   templateNames,
+		Created:     preset.Created,
+		Updated:     preset.Updated,
+	}
 }
 
 func newPresetEditCommand(opts *Options) *cobra.Command {
 	var noInteractive bool
+	var noValidate bool
+	var description string
+	var add []string
+	var remove []string
+	var showHidden bool
 	cmd := &cobra.Command{
 		Use:   "edit [key] [template1 template2...]",
 		Short: "Edit a preset",
@@ -174,25 +386,80 @@ func newPresetEditCommand(opts *Options) *cobra.Command {
 				}
 			}
 
-			items, err := discoverAllTemplates()
+			changeTemplates := len(add) > 0 || len(remove) > 0
+			if changeTemplates && len(templateNames) > 0 {
+				return fmt.Errorf("--add and --remove cannot be combined with template arguments")
+			}
+			setDescription := cmd.Flags().Changed("description")
+			if setDescription && len(templateNames) == 0 && !changeTemplates {
+				if strings.TrimSpace(name) == "" {
+					return fmt.Errorf("preset key or name is required with --description")
+				}
+				if err := presets.SetDescription(name, description); err != nil {
+					return err
+				}
+				infof(cmd, opts, "Updated description of preset %s", name)
+				return nil
+			}
+
+			items, err := discoverAllTemplates(cmd, opts)
 			if err != nil {
 				return err
 			}
 
+			if changeTemplates {
+				if strings.TrimSpace(name) == "" {
+					return fmt.Errorf("preset key or name is required with --add or --remove")
+				}
+				preset, ok, err := presets.FindPreset(name)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return fmt.Errorf("preset not found: %s", name)
+				}
+				if err := presets.ValidateTemplates(add, templateIndex(items, noValidate)); err != nil {
+					return err
+				}
+				templateNames, notFound := presets.ApplyTemplateChanges(preset.Templates, add, remove)
+				for _, missing := range notFound {
+					if err := warn(cmd, opts, "%s is not in preset %s; nothing to remove", missing, name); err != nil {
+						return err
+					}
+				}
+				if len(templateNames) == 0 {
+					return fmt.Errorf("preset %s would have no templates left", name)
+				}
+				// add was validated above; templates already in the preset are kept as they are.
+				if err := presets.EditPreset(presetKey(preset), templateNames, nil); err != nil {
+					return err
+				}
+				if setDescription {
+					if err := presets.SetDescription(presetKey(preset), description); err != nil {
+						return err
+					}
+				}
+				infof(cmd, opts, "Updated preset %s with %d templates", name, len(templateNames))
+				return nil
+			}
+
 			if len(templateNames) > 0 || noInteractive {
 				if strings.TrimSpace(name) == "" {
 					return fmt.Errorf("preset key or name is required in non-interactive mode")
 				}
-				index := templates.BuildIndex(items)
-				for _, tmpl := range templateNames {
-					if _, ok := templates.FindTemplate(index, tmpl); !ok {
-						return fmt.Errorf("template not found: %s", tmpl)
-					}
+				templateNames, err = dedupeTemplateArgs(cmd, opts, templateNames)
+				if err != nil {
+					return err
 				}
-				if err := presets.EditPreset(name, templateNames); err != nil {
+				if err := presets.EditPreset(name, templateNames, templateIndex(items, noValidate)); err != nil {
 					return err
 				}
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Updated preset %s with %d templates\n", name, len(templateNames))
+				if setDescription {
+					if err := presets.SetDescription(name, description); err != nil {
+						return err
+					}
+				}
+				infof(cmd, opts, "Updated preset %s with %d templates", name, len(templateNames))
 				return nil
 			}
 
@@ -203,7 +470,7 @@ func newPresetEditCommand(opts *Options) *cobra.Command {
 					return err
 				}
 				if len(list) == 0 {
-					return fmt.Errorf("no presets found")
+					return offerPresetCreate(cmd, opts)
 				}
 				preset, err = tui.ShowPresetSelector(list)
 				if err != nil {
@@ -223,7 +490,11 @@ func newPresetEditCommand(opts *Options) *cobra.Command {
 				preset = found
 			}
 
-			selected, err := tui.ShowInteractiveSelector(items, nil, preset.Templates, nil)
+			visible, err := selectorTemplates(items, showHidden, preset.Templates)
+			if err != nil {
+				return err
+			}
+			selected, err := tui.ShowInteractiveSelector(visible, nil, preset.Templates, nil, "")
 			if err != nil {
 				if errors.Is(err, tui.ErrCancelled) {
 					return nil
@@ -243,19 +514,28 @@ func newPresetEditCommand(opts *Options) *cobra.Command {
 			if strings.TrimSpace(presetKey) == "" {
 				presetKey = preset.Name
 			}
-			if err := presets.EditPreset(presetKey, templateNames); err != nil {
+			if err := presets.EditPreset(presetKey, templateNames, templateIndex(items, noValidate)); err != nil {
 				return err
 			}
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Updated preset %s with %d templates\n", preset.Name, len(templateNames))
+			infof(cmd, opts, "Updated preset %s with %d templates", preset.Name, len(templateNames))
 			return nil
 		},
 	}
+	cmd.ValidArgsFunction = completePresetTemplateArgs(completePresetKeys)
 	cmd.Flags().BoolVar(&noInteractive, "no-interactive", false, "Disable interactive selection")
+	cmd.Flags().BoolVar(&noValidate, "no-validate", false, "Allow template names that are not in the cache or user templates")
+	cmd.Flags().StringVar(&description, "description", "", "Replace the preset's description (empty removes it); without templates, only the description changes")
+	cmd.Flags().StringArrayVar(&add, "add", nil, "Add a template to the preset's current list (repeatable)")
+	cmd.Flags().StringArrayVar(&remove, "remove", nil, "Remove a template from the preset's current list (repeatable)")
+	cmd.Flags().BoolVar(&showHidden, "show-hidden", false, "Include categories hidden by hidden_categories in config in the selector")
 	return cmd
 }
 
 func newPresetShowCommand(opts *Options) *cobra.Command {
-	return &cobra.Command{
+	var jsonOutput bool
+	var compact bool
+	var resolve bool
+	cmd := &cobra.Command{
 		Use:   "show <name>",
 		Short: "Show preset details",
 		Args:  cobra.ExactArgs(1),
@@ -268,20 +548,332 @@ func newPresetShowCommand(opts *Options) *cobra.Command {
 			if !ok {
 				return fmt.Errorf("preset not found: %s", name)
 			}
-			if strings.TrimSpace(preset.Key) != "" {
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Key: %s\n", preset.Key)
+
+			var statuses []presetTemplateStatus
+			if resolve {
+				items, err := discoverAllTemplates(cmd, opts)
+				if err != nil {
+					return err
+				}
+				statuses = resolvePresetTemplates(preset.Templates, templates.BuildIndex(items))
+			}
+
+			if jsonOutput {
+				return writeJSON(cmd.OutOrStdout(), presetShowJSON{presetJSON: newPresetJSON(preset), Resolution: statuses}, compact)
+			}
+
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "Key: %s\n", presetKey(preset))
+			_, _ = fmt.Fprintf(out, "Name: %s\n", preset.Name)
+			if preset.Description != "" {
+				_, _ = fmt.Fprintf(out, "Description: %s\n", preset.Description)
+			}
+			if resolve {
+				_, _ = fmt.Fprintln(out, "Templates:")
+				for _, status := range statuses {
+					state := "not found"
+					if status.Found {
+						state = status.Source
+					}
+					_, _ = fmt.Fprintf(out, "  %s (%s)\n", status.Name, state)
+				}
+			} else {
+				_, _ = fmt.Fprintf(out, "Templates: %s\n", strings.Join(preset.Templates, ", "))
+			}
+			if preset.Created != "" {
+				_, _ = fmt.Fprintf(out, "Created: %s\n", preset.Created)
+			}
+			if preset.Updated != "" {
+				_, _ = fmt.Fprintf(out, "Updated: %s\n", preset.Updated)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the preset as a JSON object")
+	cmd.Flags().BoolVar(&compact, "compact", false, "With --json, print the JSON on one line")
+	cmd.Flags().BoolVar(&resolve, "resolve", false, "Look up each template in the cache and custom templates and show where it resolves")
+	return cmd
+}
+
+// presetTemplateStatus is where one of a preset's template names resolves under preset show --resolve.
+type presetTemplateStatus struct {
+	Name   string `json:"name"`
+	Found  bool   `json:"found"`
+	Source string `json:"source,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+// presetShowJSON is the --json form of preset show; Resolution is only set with --resolve.
+type presetShowJSON struct {
+	presetJSON
+	Resolution []presetTemplateStatus `json:"resolution,omitempty"`
+}
+
+// resolvePresetTemplates looks up each of names in index, in order.
+func resolvePresetTemplates(names []string, index templates.Index) []presetTemplateStatus {
+	statuses := make([]presetTemplateStatus, 0, len(names))
+	for _, name := range names {
+		status := presetTemplateStatus{Name: name}
+		if tmpl, ok := templates.FindTemplate(index, name); ok {
+			status.Found = true
+			status.Source = string(tmpl.Source)
+			status.Path = tmpl.Path
 		}
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Name: %s\n", preset.Name)
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Templates: %s\n", strings.Join(preset.Templates, ", "))
-		if preset.Created != "" {
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created: %s\n", preset.Created)
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+const (
+	exportFormatYAML = "yaml"
+	exportFormatGist = "gist"
+)
+
+func newPresetExportCommand(opts *Options) *cobra.Command {
+	var format string
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "export [key...]",
+		Short: "Print presets as YAML for sharing",
+		Long: "Write the named presets, or all presets when none are named, as a standalone presets file\n" +
+			"that preset import accepts on another machine.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != exportFormatYAML && format != exportFormatGist {
+				return fmt.Errorf("invalid format %q: must be %s or %s", format, exportFormatYAML, exportFormatGist)
+			}
+
+			list, err := exportedPresets(args)
+			if err != nil {
+				return err
+			}
+			if format == exportFormatGist && len(list) != 1 {
+				return fmt.Errorf("--format %s exports exactly one preset", exportFormatGist)
+			}
+
+			data, err := presets.MarshalPresets(list)
+			if err != nil {
+				return err
+			}
+			if format == exportFormatGist {
+				data = append([]byte(gistHeader(list[0])), data...)
+			}
+			if output == "" || output == stdoutTarget {
+				_, _ = cmd.OutOrStdout().Write(data)
+				return nil
+			}
+
+			path := joinBaseDir(opts.BaseDir(), output)
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				return fmt.Errorf("write %s: %w", path, err)
+			}
+			if !opts.Quiet {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d presets to %s\n", len(list), path)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", exportFormatYAML, "Output format: yaml, or gist for a snippet with import instructions")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write to this file instead of stdout")
+	return cmd
+}
+
+// exportedPresets returns the presets named by keys in order, or every preset when keys is empty.
+func exportedPresets(keys []string) ([]presets.Preset, error) {
+	if len(keys) == 0 {
+		list, err := presets.ListPresets()
+		if err != nil {
+			return nil, err
+		}
+		if len(list) == 0 {
+			return nil, fmt.Errorf("no presets to export")
+		}
+		return list, nil
+	}
+
+	list := make([]presets.Preset, 0, len(keys))
+	for _, key := range keys {
+		preset, ok, err := presets.FindPreset(key)
+		if err != nil {
+			return nil, err
 		}
-		if preset.Updated != "" {
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Updated: %s\n", preset.Updated)
+		if !ok {
+			return nil, fmt.Errorf("preset not found: %s", key)
 		}
+		list = append(list, preset)
+	}
+	return list, nil
+}
+
+// gistHeader introduces a shared preset snippet. Every line is a YAML comment
+// so the whole snippet can be piped straight into preset import.
+func gistHeader(preset presets.Preset) string {
+	return fmt.Sprintf("# ignr preset %q (%s)\n# Import: save this snippet as %s.yaml and run `ignr preset import - < %s.yaml`\n",
+		preset.Name, strings.Join(preset.Templates, ", "), preset.Key, preset.Key)
+}
+
+func newPresetImportCommand(opts *Options) *cobra.Command {
+	var noValidate bool
+	var overwrite bool
+	var rename bool
+
+	cmd := &cobra.Command{
+		Use:   "import <file|url|->",
+		Short: "Import presets from a shared presets file",
+		Long: "Read a presets YAML file (such as one written by preset export) from a path, an HTTPS URL,\n" +
+			"or stdin (-) and add its presets. Presets whose key already exists are skipped unless\n" +
+			"--overwrite or --rename is set. Nothing is imported if any preset in the file is invalid.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if overwrite && rename {
+				return fmt.Errorf("--overwrite and --rename cannot be used together")
+			}
+			conflict := presets.ConflictSkip
+			switch {
+			case overwrite:
+				conflict = presets.ConflictOverwrite
+			case rename:
+				conflict = presets.ConflictRename
+			}
+
+			data, err := readPresetSource(cmd, opts, args[0])
+			if err != nil {
+				return err
+			}
+			list, err := presets.ParsePresets(data)
+			if err != nil {
+				return err
+			}
+
+			var index *templates.Index
+			if !noValidate {
+				items, err := discoverAllTemplates(cmd, opts)
+				if err != nil {
+					return err
+				}
+				index = templateIndex(items, false)
+			}
+			if conflict == presets.ConflictSkip {
+				// Conflicts are reported before saving so --strict leaves the store untouched.
+				keys, err := presetKeys()
+				if err != nil {
+					return err
+				}
+				for _, preset := range list {
+					if !presetKeyExists(keys, preset.Key) {
+						continue
+					}
+					if err := warn(cmd, opts, "skipped preset %s: key already exists (use --overwrite or --rename)", preset.Key); err != nil {
+						return err
+					}
+				}
+			}
+			imported, _, err := presets.ImportPresets(list, index, conflict)
+			if err != nil {
+				return err
+			}
+
+			for i, preset := range imported {
+				if conflict == presets.ConflictRename && !strings.EqualFold(preset.Key, list[i].Key) {
+					infof(cmd, opts, "Imported preset %s as %s with %d templates", preset.Name, preset.Key, len(preset.Templates))
+					continue
+				}
+				infof(cmd, opts, "Imported preset %s with %d templates", preset.Name, len(preset.Templates))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&noValidate, "no-validate", false, "Allow template names that are not in the cache or user templates")
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace existing presets with the same key")
+	cmd.Flags().BoolVar(&rename, "rename", false, "Import presets whose key is taken under a numbered key (e.g. web-dev-2)")
+	return cmd
+}
+
+// readPresetSource reads a presets file from stdin for "-", over HTTPS for a URL, or from
+// a path relative to the base directory.
+func readPresetSource(cmd *cobra.Command, opts *Options, source string) ([]byte, error) {
+	if source == "-" {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return nil, fmt.Errorf("read stdin: %w", err)
+		}
+		return data, nil
+	}
+	lower := strings.ToLower(source)
+	if strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://") {
+		return remote.Fetch(cmd.Context(), source)
+	}
+	path := joinBaseDir(opts.BaseDir(), source)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+func newPresetLintCommand(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint",
+		Short: "Check presets.yaml for structural problems",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, issues, err := presets.LintPresetsFile()
+			if err != nil {
+				return err
+			}
+			if len(issues) == 0 {
+				infof(cmd, opts, "%s: no problems found", path)
+				return nil
+			}
+			for _, issue := range issues {
+				if issue.Line > 0 {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s:%d: %s\n", path, issue.Line, issue.Message)
+					continue
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", path, issue.Message)
+			}
+			return fmt.Errorf("%d problem(s) found in %s", len(issues), path)
+		},
+	}
+}
+
+func newPresetRenameCommand(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rename <key> <new-name>",
+		Short: "Rename a preset and update its key",
+		Long:  "Give a preset a new name and the key derived from it, keeping its templates and created time.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			renamed, err := presets.RenamePreset(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			infof(cmd, opts, "Renamed preset %s to %s (key %s)", args[0], renamed.Name, renamed.Key)
 			return nil
 		},
 	}
+	cmd.ValidArgsFunction = completeFirstPresetKey
+	return cmd
+}
+
+func newPresetDuplicateCommand(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "duplicate <key> <new-name>",
+		Short: "Copy a preset's templates into a new preset",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			copied, err := presets.CopyPreset(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			infof(cmd, opts, "Created preset %s (key %s) with %d templates", copied.Name, copied.Key, len(copied.Templates))
+			return nil
+		},
+	}
+	cmd.ValidArgsFunction = completeFirstPresetKey
+	return cmd
 }
 
 func newPresetDeleteCommand(opts *Options) *cobra.Command {
@@ -296,7 +888,7 @@ func newPresetDeleteCommand(opts *Options) *cobra.Command {
 					return err
 				}
 				if len(list) == 0 {
-					return fmt.Errorf("no presets found")
+					return offerPresetCreate(cmd, opts)
 				}
 				preset, err = tui.ShowPresetSelector(list)
 				if err != nil {
@@ -321,7 +913,7 @@ func newPresetDeleteCommand(opts *Options) *cobra.Command {
 				return err
 			}
 			if !confirm {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Cancelled.")
+				infof(cmd, opts, "Cancelled.")
 				return nil
 			}
 
@@ -332,7 +924,7 @@ func newPresetDeleteCommand(opts *Options) *cobra.Command {
 			if err := presets.DeletePreset(key); err != nil {
 				return err
 			}
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Deleted preset %s\n", preset.Name)
+			infof(cmd, opts, "Deleted preset %s", preset.Name)
 			return nil
 		},
 	}
@@ -340,9 +932,20 @@ func newPresetDeleteCommand(opts *Options) *cobra.Command {
 
 func newPresetUseCommand(opts *Options) *cobra.Command {
 	var output string
+	var outputDir string
 	var appendMode bool
 	var noHeader bool
 	var force bool
+	var printPath bool
+	var withSuggestions bool
+	var reportPath string
+	var ignorePolicy bool
+	var canonical bool
+	var personalCategory string
+	var lineEnding string
+	var printTemplates bool
+	var excludes []string
+	var noDefaults bool
 
 	cmd := &cobra.Command{
 		Use:   "use [key]",
@@ -356,7 +959,7 @@ func newPresetUseCommand(opts *Options) *cobra.Command {
 					return err
 				}
 				if len(list) == 0 {
-					return fmt.Errorf("no presets found")
+					return offerPresetCreate(cmd, opts)
 				}
 				preset, err = tui.ShowPresetSelector(list)
 				if err != nil {
@@ -374,36 +977,109 @@ func newPresetUseCommand(opts *Options) *cobra.Command {
 				preset = found
 			}
 
-			items, err := discoverAllTemplates()
+			items, err := discoverAllTemplates(cmd, opts)
 			if err != nil {
 				return err
 			}
+			userPath, err := config.GetUserTemplatePath()
+			if err != nil {
+				return err
+			}
+			if err := warnUnnamedTemplates(cmd, opts, userPath); err != nil {
+				return err
+			}
 
-			selected, _, err := selectTemplates(preset.Templates, items, nil, nil, true)
+			names := preset.Templates
+			if withSuggestions {
+				var added []string
+				names, added, err = addSuggestedTemplates(opts.BaseDir(), names, items)
+				if err != nil {
+					return err
+				}
+				if len(added) > 0 && !opts.Quiet && !printPath && strings.TrimSpace(output) != stdoutTarget {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Added detected templates: %s\n", strings.Join(added, ", "))
+				}
+			}
+
+			selected, _, err := selectTemplates(names, items, items, nil, nil, true, "", nil)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.LoadConfig()
 			if err != nil {
 				return err
 			}
+			if !noDefaults {
+				defaults, err := resolveDefaultTemplates(cmd, opts, cfg.DefaultTemplates, items)
+				if err != nil {
+					return err
+				}
+				selected = ignorefile.WithDefaults(defaults, selected)
+			}
+			if selected, err = excludeTemplates(cmd, opts, selected, items, excludes); err != nil {
+				return err
+			}
 			if len(selected) == 0 {
 				return fmt.Errorf("no templates selected")
 			}
+			if printTemplates {
+				printSelectedTemplates(cmd, selected)
+			}
 
-			loaded, err := templates.LoadTemplates(selected)
+			if output, err = outputInDir(outputDir, output); err != nil {
+				return err
+			}
+			target, err := resolveOutputPath(opts.BaseDir(), output)
 			if err != nil {
 				return err
 			}
+			debugf(cmd, opts, "output: %s", target)
+			if target == stdoutTarget && (appendMode || reportPath != "" || personalCategory != "") {
+				return fmt.Errorf("--output - cannot be used with --append, --report, or --personal")
+			}
 
-			target, err := resolveOutputPath(output)
+			ending, err := ignorefile.ResolveLineEnding(lineEnding, cfg)
 			if err != nil {
 				return err
 			}
 
-			content := templates.MergeTemplates(loaded, templates.MergeOptions{
-				Deduplicate: true,
-				AddHeader:   !noHeader,
-				Generator:   "ignr",
-				Version:     Version,
-				Timestamp:   time.Now(),
-			})
+			mergeOptions := templates.MergeOptions{
+				Deduplicate:     true,
+				AddHeader:       !noHeader,
+				Generator:       "ignr",
+				Version:         Version,
+				Timestamp:       time.Now(),
+				ExcludePatterns: policyExcludes(cfg, ignorePolicy),
+			}
+			if canonical {
+				mergeOptions = mergeOptions.Canonical()
+			}
+			if personalCategory != "" {
+				var personal []templates.Template
+				if selected, personal, err = splitPersonal(selected, personalCategory); err != nil {
+					return err
+				}
+				excludePath, err := writePersonal(cmd, opts.BaseDir(), personal, mergeOptions, appendMode, force, interactiveUsed)
+				if err != nil {
+					if errors.Is(err, tui.ErrCancelled) {
+						return nil
+					}
+					return err
+				}
+				reportGenerated(cmd, opts, excludePath, len(personal), printPath)
+				if len(selected) == 0 {
+					return nil
+				}
+			}
+
+			content, err := ignorefile.Render(selected, mergeOptions)
+			if err != nil {
+				return err
+			}
+			if target == stdoutTarget {
+				_, err := io.WriteString(cmd.OutOrStdout(), ignorefile.ConvertLineEndings(content, ending))
+				return err
+			}
 
 			if err := handleExistingOutput(cmd, target, appendMode, force, interactiveUsed, selected); err != nil {
 				if errors.Is(err, tui.ErrCancelled) {
@@ -412,27 +1088,71 @@ func newPresetUseCommand(opts *Options) *cobra.Command {
 				return err
 			}
 
-			if err := writeOutput(target, content, appendMode, force); err != nil {
+			if err := cmd.Context().Err(); err != nil {
+				return err
+			}
+			before, _ := os.ReadFile(target)
+			if err := ignorefile.Write(target, content, appendMode, ending); err != nil {
 				return err
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Generated %s with %d templates\n", target, len(selected))
+			reportGenerated(cmd, opts, target, len(selected), printPath)
+			if reportPath != "" {
+				return writeReport(reportPath, target, len(selected), before, templates.DefaultCommentPrefix)
+			}
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file path (default: .gitignore)")
+	cmd.ValidArgsFunction = completeFirstPresetKey
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file path, or - for stdout (default: .gitignore)")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "Write the output file into this directory, creating it if needed")
 	cmd.Flags().BoolVar(&appendMode, "append", false, "Append to existing file instead of overwrite")
 	cmd.Flags().BoolVar(&noHeader, "no-header", false, "Skip generator header")
 	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing file without prompt")
+	cmd.Flags().BoolVar(&printPath, "print-path", false, "Print only the path of the written file")
+	cmd.Flags().BoolVar(&printTemplates, "print-templates", false, "Print the resolved template names to stderr, one per line, before writing")
+	cmd.Flags().StringArrayVar(&excludes, "exclude", nil, "Drop this template from the preset's selection (repeatable)")
+	cmd.Flags().BoolVar(&noDefaults, "no-defaults", false, "Skip the default_templates from config for this run")
+	cmd.Flags().StringVar(&reportPath, "report", "", "Append path, template_count, and changed as key=value lines to this file (e.g. $GITHUB_OUTPUT)")
+	cmd.Flags().BoolVar(&canonical, "canonical", false, "Byte-stable output: sort sections and lines, omit the timestamp, and normalize whitespace")
+	cmd.Flags().BoolVar(&ignorePolicy, "ignore-policy", false, "Keep patterns listed in always_exclude in config")
+	cmd.Flags().StringVar(&personalCategory, "personal", "", "Write the preset's templates in this category to .git/info/exclude and the rest to the output file")
+	cmd.Flags().StringVar(&lineEnding, "line-ending", "", "Line ending of the written file: lf or crlf (default: line_ending in config, else lf)")
+	cmd.Flags().BoolVar(&withSuggestions, "with-suggestions", false, "Also include templates detected from the repo contents")
 	return cmd
 }
 
-func discoverAllTemplates() ([]templates.Template, error) {
-	cachePath, err := cache.InitializeCache()
+// addSuggestedTemplates appends templates detected in baseDir to names, skipping ones
+// already present and ones missing from items. It returns the union and what was added.
+func addSuggestedTemplates(baseDir string, names []string, items []templates.Template) ([]string, []string, error) {
+	detected, err := presets.DetectFiles(baseDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	suggested, err := presets.SuggestTemplates(detected)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index := templates.BuildIndex(items)
+	union, _ := presets.DedupeTemplates(names)
+	kept := len(union)
+	for _, name := range suggested {
+		if _, ok := templates.FindTemplate(index, name); ok {
+			union = append(union, name)
+		}
+	}
+	union, _ = presets.DedupeTemplates(union)
+	return union, union[kept:], nil
+}
+
+func discoverAllTemplates(cmd *cobra.Command, opts *Options) ([]templates.Template, error) {
+	cachePath, err := cache.InitializeCache(cmd.Context())
 	if err != nil {
 		return nil, err
 	}
+	debugf(cmd, opts, "template cache: %s", cachePath)
 
 	items, err := templates.DiscoverTemplates(cachePath)
 	if err != nil {
@@ -447,10 +1167,78 @@ func discoverAllTemplates() ([]templates.Template, error) {
 	if err != nil {
 		return nil, err
 	}
+	logDiscovered(cmd, opts, items, userItems, userPath)
 
 	return append(items, userItems...), nil
 }
 
+// completePresetTemplateArgs completes the first positional arg with first (if set)
+// and every later arg with template names not already given.
+func completePresetTemplateArgs(
+	first func(toComplete string) []string,
+) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			if first == nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return first(toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return completeTemplateArgs(cmd, args[1:], toComplete)
+	}
+}
+
+// completeFirstPresetKey completes a preset key as the first argument and nothing after it.
+func completeFirstPresetKey(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completePresetKeys(toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completePresetKeys(toComplete string) []string {
+	keys, err := presetKeys()
+	if err != nil {
+		return nil
+	}
+	prefix := strings.ToLower(toComplete)
+	matches := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if strings.HasPrefix(strings.ToLower(key), prefix) {
+			matches = append(matches, key)
+		}
+	}
+	return matches
+}
+
+// remainingTemplateNames returns template names starting with toComplete, skipping names already typed.
+func remainingTemplateNames(items []templates.Template, typed []string, toComplete string) []string {
+	used := make(map[string]struct{}, len(typed))
+	for _, name := range typed {
+		used[strings.ToLower(name)] = struct{}{}
+	}
+
+	prefix := strings.ToLower(toComplete)
+	seen := make(map[string]struct{}, len(items))
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		key := strings.ToLower(item.Name)
+		if _, ok := used[key]; ok {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		seen[key] = struct{}{}
+		names = append(names, item.Name)
+	}
+	return names
+}
+
 func presetKeys() ([]string, error) {
 	list, err := presets.ListPresets()
 	if err != nil {
@@ -478,10 +1266,10 @@ func presetKeyExists(keys []string, key string) bool {
 }
 
 func confirmPrompt(cmd *cobra.Command, prompt string) (bool, error) {
-	reader := bufio.NewReader(os.Stdin)
+	reader := bufio.NewReader(cmd.InOrStdin())
 	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s [y/N]: ", prompt)
 	line, err := reader.ReadString('\n')
-	if err != nil {
+	if err != nil && !errors.Is(err, io.EOF) {
 		return false, err
 	}
 	response := strings.ToLower(strings.TrimSpace(line))
@@ -0,0 +1,960 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"github.com/spf13/cobra"
+	"go.seanlatimer.dev/ignr/internal/presets"
+	"go.seanlatimer.dev/ignr/internal/remote"
+	"go.seanlatimer.dev/ignr/internal/templates"
+	"gopkg.in/yaml.v3"
+)
+
+func TestPresetCreateCompletesRemainingTemplates(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	cmd := newPresetCreateCommand(&Options{})
+
+	tests := []struct {
+		name       string
+		args       []string
+		toComplete string
+		want       []string
+	}{
+		{
+			name: "no completion for preset name",
+			args: []string{},
+			want: nil,
+		},
+		{
+			name: "excludes templates already typed",
+			args: []string{"web", "Go"},
+			want: []string{"Node", "Python"},
+		},
+		{
+			name:       "filters by prefix case-insensitively",
+			args:       []string{"web"},
+			toComplete: "py",
+			want:       []string{"Python"},
+		},
+		{
+			name: "typed names match case-insensitively",
+			args: []string{"web", "node", "python"},
+			want: []string{"Go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, directive := cmd.ValidArgsFunction(cmd, tt.args, tt.toComplete)
+			if directive != cobra.ShellCompDirectiveNoFileComp {
+				t.Errorf("ValidArgsFunction() directive = %v, want NoFileComp", directive)
+			}
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidArgsFunction(%v, %q) = %v, want %v", tt.args, tt.toComplete, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPresetCommandsEmptyStoreOfferCreate(t *testing.T) {
+	for _, sub := range []string{"use", "edit", "delete"} {
+		t.Run(sub, func(t *testing.T) {
+			cleanup := setupGenerateTest(t)
+			defer cleanup()
+
+			root := NewRootCommand(&Options{})
+			root.SetArgs([]string{"preset", sub})
+			root.SetIn(strings.NewReader("n\n"))
+
+			var buf bytes.Buffer
+			root.SetOut(&buf)
+			root.SetErr(&buf)
+
+			if err := root.Execute(); err != nil {
+				t.Fatalf("preset %s with empty store error = %v, want nil", sub, err)
+			}
+
+			output := buf.String()
+			for _, want := range []string{"No presets found.", "Create one now?", "ignr preset create"} {
+				if !strings.Contains(output, want) {
+					t.Errorf("preset %s output = %q, want it to contain %q", sub, output, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPresetCreateWarnsOnDuplicateName(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	store := presets.PresetStore{Presets: []presets.Preset{{Key: "web-legacy", Name: "Web", Templates: []string{"Node"}}}}
+	if err := presets.SavePresets(store); err != nil {
+		t.Fatalf("SavePresets() error = %v", err)
+	}
+
+	cmd := newPresetCreateCommand(&Options{})
+	cmd.SetArgs([]string{"Web", "Go"})
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("preset create error = %v", err)
+	}
+	if !strings.Contains(stderr.String(), "web-legacy") {
+		t.Errorf("preset create stderr = %q, want duplicate-name warning naming web-legacy", stderr.String())
+	}
+}
+
+func TestPresetCreateNoValidate(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	cmd := newPresetCreateCommand(&Options{})
+	cmd.SetArgs([]string{"web", "Go", "Missing"})
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "template not found: Missing") {
+		t.Errorf("preset create with unknown template error = %v, want template not found", err)
+	}
+
+	cmd = newPresetCreateCommand(&Options{})
+	cmd.SetArgs([]string{"--no-validate", "web", "Go", "Missing"})
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("preset create --no-validate error = %v", err)
+	}
+	preset, ok, err := presets.FindPreset("web")
+	if err != nil || !ok {
+		t.Fatalf("FindPreset() = %v, %v", ok, err)
+	}
+	if !reflect.DeepEqual(preset.Templates, []string{"Go", "Missing"}) {
+		t.Errorf("preset templates = %v, want [Go Missing]", preset.Templates)
+	}
+}
+
+func TestPresetUsePrintPath(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	if err := presets.CreatePreset("web", []string{"Go", "Node"}, nil); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	cmd := newPresetUseCommand(&Options{Chdir: dir})
+	cmd.SetArgs([]string{"--print-path", "web"})
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("preset use error = %v", err)
+	}
+	if want := filepath.Join(dir, ".gitignore") + "\n"; stdout.String() != want {
+		t.Errorf("preset use --print-path stdout = %q, want %q", stdout.String(), want)
+	}
+}
+
+func TestPresetCreateDuplicateTemplates(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	cmd := newPresetCreateCommand(&Options{})
+	cmd.SetArgs([]string{"web", "Go", "go", "Go"})
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("preset create error = %v", err)
+	}
+	if !strings.Contains(stderr.String(), "removed duplicate templates: go, Go") {
+		t.Errorf("preset create stderr = %q, want duplicate warning", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "with 1 templates") {
+		t.Errorf("preset create stdout = %q, want count after dedupe", stdout.String())
+	}
+	preset, _, err := presets.FindPreset("web")
+	if err != nil {
+		t.Fatalf("FindPreset() error = %v", err)
+	}
+	if !reflect.DeepEqual(preset.Templates, []string{"Go"}) {
+		t.Errorf("preset templates = %v, want [Go]", preset.Templates)
+	}
+}
+
+func TestPresetCreateTemplateExpr(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	cmd := newPresetCreateCommand(&Options{})
+	cmd.SetArgs([]string{"web", "Node,+Go,+Python,-Go"})
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("preset create error = %v", err)
+	}
+	preset, _, err := presets.FindPreset("web")
+	if err != nil {
+		t.Fatalf("FindPreset() error = %v", err)
+	}
+	if !reflect.DeepEqual(preset.Templates, []string{"Node", "Python"}) {
+		t.Errorf("preset templates = %v, want [Node Python]", preset.Templates)
+	}
+
+	cmd = newPresetCreateCommand(&Options{})
+	cmd.SetArgs([]string{"api", "Go,-Node"})
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	if err := cmd.Execute(); !errors.Is(err, presets.ErrInvalidTemplateExpr) {
+		t.Errorf("preset create with bad removal error = %v, want %v", err, presets.ErrInvalidTemplateExpr)
+	}
+}
+
+func TestPresetLint(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+		wantOut string
+	}{
+		{
+			name:    "clean file",
+			data:    "presets:\n  - name: Web\n    templates: [Go]\n",
+			wantOut: "no problems found",
+		},
+		{
+			name:    "reports line numbers",
+			data:    "presets:\n  - name: Web\n    templates: Go\n",
+			wantErr: true,
+			wantOut: `presets.yaml:3: preset 1: "templates" must be a list of template names`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupGenerateTest(t)
+			defer cleanup()
+
+			path := filepath.Join(xdg.ConfigHome, "ignr", "presets.yaml")
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatalf("failed to write presets: %v", err)
+			}
+
+			root := NewRootCommand(&Options{})
+			root.SetArgs([]string{"preset", "lint"})
+			var buf bytes.Buffer
+			root.SetOut(&buf)
+			root.SetErr(&buf)
+
+			err := root.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("preset lint error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(buf.String(), tt.wantOut) {
+				t.Errorf("preset lint output = %q, want it to contain %q", buf.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestPresetUseWithSuggestions(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	if err := presets.CreatePreset("web", []string{"Go", "Node"}, nil); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	// go.mod suggests Go (already in the preset), requirements.txt suggests Python,
+	// and Cargo.toml suggests Rust, which is not in the cache.
+	for _, name := range []string{"go.mod", "requirements.txt", "Cargo.toml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantPython bool
+	}{
+		{name: "preset only", args: []string{"web"}},
+		{name: "with suggestions", args: []string{"--with-suggestions", "web"}, wantPython: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newPresetUseCommand(&Options{Chdir: dir})
+			cmd.SetArgs(append([]string{"--force"}, tt.args...))
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetErr(&buf)
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("preset use error = %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			content := string(data)
+			if strings.Count(content, "# --- Go ---") != 1 || !strings.Contains(content, "# --- Node ---") {
+				t.Errorf("preset use output = %q, want Go and Node sections once each", content)
+			}
+			if got := strings.Contains(content, "# --- Python ---"); got != tt.wantPython {
+				t.Errorf("preset use output has Python = %v, want %v", got, tt.wantPython)
+			}
+			if tt.wantPython && !strings.Contains(buf.String(), "Added detected templates: Python\n") {
+				t.Errorf("preset use output = %q, want added templates message", buf.String())
+			}
+		})
+	}
+}
+
+func TestPresetExportGist(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	if err := presets.CreatePreset("My Web", []string{"Go", "Node"}, nil); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+
+	cmd := newPresetExportCommand(&Options{})
+	cmd.SetArgs([]string{"--format", "gist", "my-web"})
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("preset export error = %v", err)
+	}
+	out := buf.String()
+
+	lines := strings.Split(out, "\n")
+	if len(lines) < 2 || !strings.HasPrefix(lines[0], "# ") || !strings.HasPrefix(lines[1], "# ") {
+		t.Fatalf("preset export --format gist output = %q, want a comment header", out)
+	}
+	if !strings.Contains(lines[1], "ignr preset import -") {
+		t.Errorf("preset export --format gist header = %q, want import instruction", lines[1])
+	}
+
+	var store presets.PresetStore
+	if err := yaml.Unmarshal(buf.Bytes(), &store); err != nil {
+		t.Fatalf("preset export --format gist output is not valid YAML: %v\n%s", err, out)
+	}
+	if len(store.Presets) != 1 {
+		t.Fatalf("exported presets = %d, want 1", len(store.Presets))
+	}
+	got := store.Presets[0]
+	if got.Key != "my-web" || got.Name != "My Web" || !reflect.DeepEqual(got.Templates, []string{"Go", "Node"}) {
+		t.Errorf("exported preset = %+v, want my-web with Go, Node", got)
+	}
+	if issues := presets.LintPresets(buf.Bytes()); len(issues) != 0 {
+		t.Errorf("LintPresets() on exported snippet = %v, want none", issues)
+	}
+
+	cmd = newPresetExportCommand(&Options{})
+	cmd.SetArgs([]string{"--format", "json", "my-web"})
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid format") {
+		t.Errorf("preset export --format json error = %v, want invalid format", err)
+	}
+}
+
+func TestPresetExportAll(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	for _, name := range []string{"Web", "Backend"} {
+		if err := presets.CreatePreset(name, []string{"Go", "Node"}, nil); err != nil {
+			t.Fatalf("CreatePreset(%s) error = %v", name, err)
+		}
+	}
+	stored, err := presets.ListPresets()
+	if err != nil {
+		t.Fatalf("ListPresets() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		args     []string
+		wantKeys []string
+	}{
+		{name: "all", args: []string{"-o", "all.yaml"}, wantKeys: []string{"web", "backend"}},
+		{name: "selected", args: []string{"-o", "one.yaml", "backend"}, wantKeys: []string{"backend"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := NewRootCommand(&Options{})
+			root.SetArgs(append([]string{"-C", dir, "preset", "export"}, tt.args...))
+			var stdout bytes.Buffer
+			root.SetOut(&stdout)
+			root.SetErr(&stdout)
+			if err := root.Execute(); err != nil {
+				t.Fatalf("preset export error = %v", err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, tt.args[1]))
+			if err != nil {
+				t.Fatalf("failed to read export: %v", err)
+			}
+			list, err := presets.ParsePresets(data)
+			if err != nil {
+				t.Fatalf("ParsePresets() on export error = %v", err)
+			}
+			var keys []string
+			for _, preset := range list {
+				keys = append(keys, preset.Key)
+				for _, want := range stored {
+					if want.Key == preset.Key && !reflect.DeepEqual(preset, want) {
+						t.Errorf("exported preset = %+v, want %+v", preset, want)
+					}
+				}
+			}
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("exported keys = %v, want %v", keys, tt.wantKeys)
+			}
+		})
+	}
+
+	root := NewRootCommand(&Options{})
+	root.SetArgs([]string{"preset", "export", "--format", "gist"})
+	var stdout bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetErr(&stdout)
+	if err := root.Execute(); err == nil {
+		t.Error("preset export --format gist with two presets error = nil, want rejected")
+	}
+}
+
+func TestPresetCommandNonInteractivePrintsHelp(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "non-TTY output", args: []string{"preset"}},
+		{name: "--no-interactive", args: []string{"preset", "--no-interactive"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupGenerateTest(t)
+			defer cleanup()
+
+			root := NewRootCommand(&Options{})
+			root.SetArgs(tt.args)
+			var buf bytes.Buffer
+			root.SetOut(&buf)
+			root.SetErr(&buf)
+
+			if err := root.Execute(); err != nil {
+				t.Fatalf("%v error = %v", tt.args, err)
+			}
+			out := buf.String()
+			for _, want := range []string{"Available Commands:", "create", "use"} {
+				if !strings.Contains(out, want) {
+					t.Errorf("%v output = %q, want help containing %q", tt.args, out, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPresetUseReport(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	if err := presets.CreatePreset("web", []string{"Go", "Node"}, nil); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	reportPath := filepath.Join(t.TempDir(), "github_output")
+	for i := 0; i < 2; i++ {
+		cmd := newPresetUseCommand(&Options{Chdir: dir, Quiet: true})
+		cmd.SetArgs([]string{"--force", "--report", reportPath, "web"})
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("preset use error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	target := filepath.Join(dir, ".gitignore")
+	want := "path=" + target + "\ntemplate_count=2\nchanged=true\n" +
+		"path=" + target + "\ntemplate_count=2\nchanged=false\n"
+	if string(data) != want {
+		t.Errorf("report = %q, want %q", string(data), want)
+	}
+}
+
+func TestPresetListOutputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		presets map[string][]string
+		args    []string
+		want    string
+	}{
+		{name: "empty human", want: "No presets found.\n"},
+		{name: "empty json", args: []string{"--json"}, want: "[]\n"},
+		{name: "empty porcelain", args: []string{"--porcelain"}, want: ""},
+		{name: "human", presets: map[string][]string{"Web": {"Go", "Node"}}, want: "Web [web] (2 templates)\n"},
+		{name: "porcelain", presets: map[string][]string{"Web": {"Go", "Node"}}, args: []string{"--porcelain"}, want: "web\tWeb\tGo,Node\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupGenerateTest(t)
+			defer cleanup()
+			for name, templateNames := range tt.presets {
+				if err := presets.CreatePreset(name, templateNames, nil); err != nil {
+					t.Fatalf("CreatePreset() error = %v", err)
+				}
+			}
+
+			cmd := newPresetListCommand(&Options{})
+			cmd.SetArgs(tt.args)
+			var stdout bytes.Buffer
+			cmd.SetOut(&stdout)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("preset list error = %v", err)
+			}
+			if stdout.String() != tt.want {
+				t.Errorf("preset list %v = %q, want %q", tt.args, stdout.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPresetListJSON(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	if err := presets.CreatePreset("Web", []string{"Go", "Node"}, nil); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+
+	cmd := newPresetListCommand(&Options{})
+	cmd.SetArgs([]string{"--json"})
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("preset list --json error = %v", err)
+	}
+
+	var got []presetJSON
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("preset list --json output is not JSON: %v\n%s", err, stdout.String())
+	}
+	if len(got) != 1 || got[0].Key != "web" || got[0].Name != "Web" || !reflect.DeepEqual(got[0].Templates, []string{"Go", "Node"}) {
+		t.Errorf("preset list --json = %+v, want the Web preset", got)
+	}
+}
+
+// serveTLS serves body at every path over HTTPS and points remote fetches at the test server.
+func serveTLS(t *testing.T, contentType, body string) string {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	original := remote.Client
+	remote.Client = server.Client()
+	t.Cleanup(func() { remote.Client = original })
+	return server.URL
+}
+
+func TestPresetImportURL(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	if err := presets.CreatePreset("web", []string{"Node"}, nil); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+	url := serveTLS(t, "text/plain; charset=utf-8",
+		"presets:\n  - key: web\n    name: Web\n    templates: [Go]\n  - name: Backend\n    templates: [Go, Python]\n")
+
+	root := NewRootCommand(&Options{})
+	root.SetArgs([]string{"preset", "import", url + "/presets.yaml"})
+	var stdout, stderr bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("preset import error = %v", err)
+	}
+
+	if !strings.Contains(stdout.String(), "Imported preset Backend with 2 templates") {
+		t.Errorf("stdout = %q, want Backend imported", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "skipped preset web") {
+		t.Errorf("stderr = %q, want web skipped", stderr.String())
+	}
+	backend, ok, err := presets.FindPreset("backend")
+	if err != nil || !ok {
+		t.Fatalf("FindPreset(backend) = %v, %v, want imported preset", ok, err)
+	}
+	if !reflect.DeepEqual(backend.Templates, []string{"Go", "Python"}) {
+		t.Errorf("backend templates = %v, want [Go Python]", backend.Templates)
+	}
+	web, _, _ := presets.FindPreset("web")
+	if !reflect.DeepEqual(web.Templates, []string{"Node"}) {
+		t.Errorf("web templates = %v, want the existing preset kept", web.Templates)
+	}
+}
+
+func TestPresetImportFile(t *testing.T) {
+	const file = "presets:\n  - key: web\n    name: Web\n    templates: [Go]\n"
+
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		content  string
+		wantErr  string
+		wantKeys []string
+		wantWeb  []string
+	}{
+		{name: "skip", args: []string{"shared.yaml"}, content: file, wantKeys: []string{"web"}, wantWeb: []string{"Node"}},
+		{name: "overwrite", args: []string{"--overwrite", "shared.yaml"}, content: file, wantKeys: []string{"web"}, wantWeb: []string{"Go"}},
+		{name: "rename", args: []string{"--rename", "shared.yaml"}, content: file, wantKeys: []string{"web", "web-2"}, wantWeb: []string{"Node"}},
+		{name: "stdin", args: []string{"--overwrite", "-"}, stdin: file, wantKeys: []string{"web"}, wantWeb: []string{"Go"}},
+		{
+			name:     "invalid entry",
+			args:     []string{"shared.yaml"},
+			content:  "presets:\n  - name: Backend\n    templates: [Go]\n  - templates: [Python]\n",
+			wantErr:  `missing required field "name"`,
+			wantKeys: []string{"web"},
+			wantWeb:  []string{"Node"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := setupGenerateTest(t)
+			defer cleanup()
+
+			if err := presets.CreatePreset("web", []string{"Node"}, nil); err != nil {
+				t.Fatalf("CreatePreset() error = %v", err)
+			}
+			dir := t.TempDir()
+			if tt.content != "" {
+				if err := os.WriteFile(filepath.Join(dir, "shared.yaml"), []byte(tt.content), 0o644); err != nil {
+					t.Fatalf("failed to write presets file: %v", err)
+				}
+			}
+
+			root := NewRootCommand(&Options{})
+			root.SetArgs(append([]string{"-C", dir, "preset", "import"}, tt.args...))
+			root.SetIn(strings.NewReader(tt.stdin))
+			var stdout bytes.Buffer
+			root.SetOut(&stdout)
+			root.SetErr(&stdout)
+			err := root.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("preset import error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("preset import error = %v", err)
+			}
+
+			list, err := presets.ListPresets()
+			if err != nil {
+				t.Fatalf("ListPresets() error = %v", err)
+			}
+			var keys []string
+			for _, preset := range list {
+				keys = append(keys, preset.Key)
+			}
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("keys = %v, want %v", keys, tt.wantKeys)
+			}
+			if !reflect.DeepEqual(list[0].Templates, tt.wantWeb) {
+				t.Errorf("web templates = %v, want %v", list[0].Templates, tt.wantWeb)
+			}
+		})
+	}
+}
+
+func TestPresetDescription(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	run := func(args ...string) string {
+		t.Helper()
+		root := NewRootCommand(&Options{})
+		root.SetArgs(args)
+		var stdout bytes.Buffer
+		root.SetOut(&stdout)
+		root.SetErr(&stdout)
+		if err := root.Execute(); err != nil {
+			t.Fatalf("%v error = %v", args, err)
+		}
+		return stdout.String()
+	}
+
+	run("preset", "create", "--description", "Frontend and API", "Web Dev", "Node", "Go")
+	if out := run("preset", "show", "web-dev"); !strings.Contains(out, "Description: Frontend and API") {
+		t.Errorf("preset show = %q, want the description", out)
+	}
+
+	run("preset", "edit", "web-dev", "--description", "Frontend only")
+	preset, _, err := presets.FindPreset("web-dev")
+	if err != nil {
+		t.Fatalf("FindPreset() error = %v", err)
+	}
+	if preset.Description != "Frontend only" || !reflect.DeepEqual(preset.Templates, []string{"Node", "Go"}) {
+		t.Errorf("preset after edit --description = %+v, want new description and templates kept", preset)
+	}
+
+	run("preset", "edit", "web-dev", "--description", "")
+	if out := run("preset", "show", "web-dev"); strings.Contains(out, "Description:") {
+		t.Errorf("preset show = %q, want no description after clearing it", out)
+	}
+}
+
+func TestPresetEditAddRemove(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+	if err := presets.CreatePreset("web", []string{"Node", "Go"}, nil); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+
+	root := NewRootCommand(&Options{})
+	root.SetArgs([]string{"preset", "edit", "web", "--add", "Python", "--remove", "go", "--remove", "Rust"})
+	var stdout, stderr bytes.Buffer
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("preset edit --add --remove error = %v", err)
+	}
+	preset, _, err := presets.FindPreset("web")
+	if err != nil {
+		t.Fatalf("FindPreset() error = %v", err)
+	}
+	if !reflect.DeepEqual(preset.Templates, []string{"Node", "Python"}) {
+		t.Errorf("templates = %v, want [Node Python]", preset.Templates)
+	}
+	if !strings.Contains(stderr.String(), "warning: Rust is not in preset web") {
+		t.Errorf("stderr = %q, want a warning for the missing template", stderr.String())
+	}
+
+	root = NewRootCommand(&Options{})
+	root.SetArgs([]string{"preset", "edit", "web", "--add", "Missing"})
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	if err := root.Execute(); !errors.Is(err, presets.ErrTemplateNotFound) {
+		t.Errorf("preset edit --add Missing error = %v, want ErrTemplateNotFound", err)
+	}
+}
+
+func TestPresetShowJSON(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	run := func(args ...string) string {
+		t.Helper()
+		root := NewRootCommand(&Options{})
+		root.SetArgs(args)
+		var stdout bytes.Buffer
+		root.SetOut(&stdout)
+		root.SetErr(&stdout)
+		if err := root.Execute(); err != nil {
+			t.Fatalf("%v error = %v", args, err)
+		}
+		return stdout.String()
+	}
+
+	run("preset", "create", "--no-validate", "--description", "Frontend and API", "Web Dev", "Node", "Missing")
+	stored, _, err := presets.FindPreset("web-dev")
+	if err != nil {
+		t.Fatalf("FindPreset() error = %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(run("preset", "show", "web-dev", "--json")), &got); err != nil {
+		t.Fatalf("preset show --json is not JSON: %v", err)
+	}
+	want := map[string]any{
+		"key":         "web-dev",
+		"name":        "Web Dev",
+		"description": "Frontend and API",
+		"templates":   []any{"Node", "Missing"},
+		"created":     stored.Created,
+		"updated":     stored.Updated,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("preset show --json = %v, want %v", got, want)
+	}
+
+	var resolved presetShowJSON
+	if err := json.Unmarshal([]byte(run("preset", "show", "web-dev", "--json", "--resolve")), &resolved); err != nil {
+		t.Fatalf("preset show --json --resolve is not JSON: %v", err)
+	}
+	if len(resolved.Resolution) != 2 {
+		t.Fatalf("resolution = %+v, want one entry per template", resolved.Resolution)
+	}
+	if node := resolved.Resolution[0]; !node.Found || node.Source != "cache" || node.Path == "" {
+		t.Errorf("Node resolution = %+v, want found in the cache", node)
+	}
+	if missing := resolved.Resolution[1]; missing.Found || missing.Source != "" {
+		t.Errorf("Missing resolution = %+v, want not found", missing)
+	}
+
+	out := run("preset", "show", "web-dev", "--resolve")
+	if !strings.Contains(out, "  Node (cache)\n") || !strings.Contains(out, "  Missing (not found)\n") {
+		t.Errorf("preset show --resolve = %q, want each template with its resolution", out)
+	}
+}
+
+func TestGenerateCommandListURL(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	url := serveTLS(t, "text/plain", "# team list\nGo\nNode\n")
+	dir := t.TempDir()
+
+	root := NewRootCommand(&Options{})
+	root.SetArgs([]string{"-C", dir, "generate", "--no-interactive", "--list-url", url + "/list.txt"})
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("generate --list-url error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(string(data), "# --- Go ---") || !strings.Contains(string(data), "# --- Node ---") {
+		t.Errorf("output = %q, want Go and Node from the list", data)
+	}
+
+	root = NewRootCommand(&Options{})
+	root.SetArgs([]string{"-C", dir, "generate", "--offline", "--list-url", url + "/list.txt"})
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "--offline") {
+		t.Errorf("generate --offline --list-url error = %v, want offline guard", err)
+	}
+}
+
+func TestStrictPromotesWarnings(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	store := presets.PresetStore{Presets: []presets.Preset{{Key: "web-legacy", Name: "Web", Templates: []string{"Node"}}}}
+	if err := presets.SavePresets(store); err != nil {
+		t.Fatalf("SavePresets() error = %v", err)
+	}
+	url := serveTLS(t, "text/plain", "presets:\n  - key: web-legacy\n    name: Web\n    templates: [Go]\n  - name: Backend\n    templates: [Go]\n")
+
+	tests := []struct {
+		name    string
+		args    []string
+		unsaved string
+	}{
+		{name: "duplicate templates", args: []string{"preset", "create", "api", "Go", "go"}, unsaved: "api"},
+		{name: "duplicate name", args: []string{"preset", "create", "Web", "Go"}, unsaved: "web"},
+		{name: "import conflict", args: []string{"preset", "import", url + "/presets.yaml"}, unsaved: "backend"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := NewRootCommand(&Options{})
+			root.SetArgs(append([]string{"--strict"}, tt.args...))
+			var buf bytes.Buffer
+			root.SetOut(&buf)
+			root.SetErr(&buf)
+
+			err := root.Execute()
+			if err == nil || !strings.Contains(err.Error(), "--strict") {
+				t.Fatalf("%v error = %v, want warning promoted under --strict", tt.args, err)
+			}
+			if strings.Contains(buf.String(), "warning:") {
+				t.Errorf("%v output = %q, want the warning returned instead of printed", tt.args, buf.String())
+			}
+			keys, err := presetKeys()
+			if err != nil {
+				t.Fatalf("presetKeys() error = %v", err)
+			}
+			if presetKeyExists(keys, tt.unsaved) {
+				t.Errorf("%v saved preset %s, want nothing saved under --strict", tt.args, tt.unsaved)
+			}
+		})
+	}
+}
+
+func TestSelectorTemplatesHidesCategories(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	configPath := filepath.Join(xdg.ConfigHome, "ignr", "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"hidden_categories": ["Global"]}`), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	items := []templates.Template{
+		{Name: "Go", Category: templates.CategoryRoot, Path: "/Go.gitignore"},
+		{Name: "macOS", Category: templates.CategoryGlobal, Path: "/Global/macOS.gitignore"},
+		{Name: "Windows", Category: templates.CategoryGlobal, Path: "/Global/Windows.gitignore"},
+	}
+	names := func(list []templates.Template) []string {
+		var got []string
+		for _, item := range list {
+			got = append(got, item.Name)
+		}
+		return got
+	}
+
+	tests := []struct {
+		name       string
+		showHidden bool
+		keep       []string
+		want       []string
+	}{
+		{name: "hidden", want: []string{"Go"}},
+		{name: "show hidden", showHidden: true, want: []string{"Go", "macOS", "Windows"}},
+		{name: "preset templates kept", keep: []string{"Go", "macos"}, want: []string{"Go", "macOS"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectorTemplates(items, tt.showHidden, tt.keep)
+			if err != nil {
+				t.Fatalf("selectorTemplates() error = %v", err)
+			}
+			if !reflect.DeepEqual(names(got), tt.want) {
+				t.Errorf("selectorTemplates() = %v, want %v", names(got), tt.want)
+			}
+		})
+	}
+}
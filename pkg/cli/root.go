@@ -2,42 +2,88 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"go.seanlatimer.dev/ignr/internal/cache"
+	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/tui"
 )
 
 type Options struct {
 	ConfigPath string
+	Chdir      string
 	Verbose    bool
 	Quiet      bool
+	Plain      bool
+	Strict     bool
+	NoUpdate   bool
 }
 
 var Version = "dev"
 
+// exitCancelled is the exit status after Ctrl-C, matching shells' 128+SIGINT.
+const exitCancelled = 130
+
+// Execute runs the root command. Ctrl-C cancels the command's context, which stops clones,
+// pulls, and fetches; any error after that is reported as context.Canceled.
 func Execute() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
 	opts := &Options{}
 	root := NewRootCommand(opts)
-	return root.Execute()
+	if err := root.ExecuteContext(ctx); err != nil {
+		if ctx.Err() != nil {
+			return context.Canceled
+		}
+		return err
+	}
+	return nil
 }
 
 func NewRootCommand(opts *Options) *cobra.Command {
 	root := &cobra.Command{
 		Use:   "ignr",
 		Short: "Offline-first gitignore generator",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			config.SetConfigPath(opts.ConfigPath)
+			cache.SetStaleRefresh(!opts.NoUpdate, func(err error) error {
+				return warn(cmd, opts, "could not refresh the template cache, using cached templates: %v", err)
+			})
+			tui.SetPlain(usePlainTUI(opts))
+			return validateChdir(opts.Chdir)
+		},
 	}
 
-	root.PersistentFlags().StringVar(&opts.ConfigPath, "config", "", "Config file path")
+	root.PersistentFlags().StringVar(&opts.ConfigPath, "config", "", "Config file path (overrides $IGNR_CONFIG)")
+	root.PersistentFlags().StringVarP(&opts.Chdir, "chdir", "C", "", "Resolve output and detection paths relative to this directory")
 	root.PersistentFlags().BoolVar(&opts.Verbose, "verbose", false, "Enable verbose output")
 	root.PersistentFlags().BoolVar(&opts.Quiet, "quiet", false, "Suppress non-error output")
+	root.MarkFlagsMutuallyExclusive("quiet", "verbose")
+	root.PersistentFlags().BoolVar(&opts.Plain, "plain", false, "Draw interactive views with ASCII borders")
+	root.PersistentFlags().BoolVar(&opts.Strict, "strict", false, "Treat warnings as errors")
+	root.PersistentFlags().BoolVar(&opts.NoUpdate, "no-update", false, "Never refresh a template cache older than cache_ttl in config")
 
 	root.AddCommand(
 		newListCommand(opts),
 		newSearchCommand(opts),
 		newGenerateCommand(opts),
+		newSuggestCommand(opts),
 		newPresetCommand(opts),
+		newTemplateCommand(opts),
+		newInitCommand(opts),
 		newUpdateCommand(opts),
+		newCacheCommand(opts),
+		newConfigCommand(opts),
+		newUndoCommand(opts),
+		newExplainCommand(opts),
+		newCompletionCommand(opts),
 	)
 
 	root.Version = Version
@@ -46,10 +92,74 @@ func NewRootCommand(opts *Options) *cobra.Command {
 	return root
 }
 
+// BaseDir returns the effective working directory for path resolution.
+func (o *Options) BaseDir() string {
+	if o == nil || strings.TrimSpace(o.Chdir) == "" {
+		return "."
+	}
+	return o.Chdir
+}
+
+// warn prints a warning to stderr. Under --strict it returns the warning as an error instead,
+// so CI runs fail on problems that are otherwise only reported.
+func warn(cmd *cobra.Command, opts *Options, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if opts != nil && opts.Strict {
+		return fmt.Errorf("%s (--strict)", msg)
+	}
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: %s\n", msg)
+	return nil
+}
+
+// infof prints a status line, such as a "Created preset" confirmation, to stdout unless
+// --quiet is set. Requested output, like list results, is printed directly instead.
+func infof(cmd *cobra.Command, opts *Options, format string, args ...any) {
+	if opts != nil && opts.Quiet {
+		return
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), format+"\n", args...)
+}
+
+// debugf prints a diagnostic line to stderr when --verbose is set.
+func debugf(cmd *cobra.Command, opts *Options, format string, args ...any) {
+	if opts == nil || !opts.Verbose {
+		return
+	}
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "debug: "+format+"\n", args...)
+}
+
+// usePlainTUI reports whether interactive views should use ASCII borders, from --plain
+// or the plain_tui config setting. An unreadable config leaves the default borders.
+func usePlainTUI(opts *Options) bool {
+	if opts.Plain {
+		return true
+	}
+	cfg, err := config.LoadConfig()
+	return err == nil && cfg.PlainTUI
+}
+
+func validateChdir(dir string) error {
+	if strings.TrimSpace(dir) == "" {
+		return nil
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("chdir: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("chdir: not a directory: %s", dir)
+	}
+	return nil
+}
+
 func ExitWithError(err error) {
 	if err == nil {
 		return
 	}
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, "cancelled")
+		os.Exit(exitCancelled)
+	}
 	fmt.Fprintln(os.Stderr, err.Error())
 	os.Exit(1)
 }
@@ -2,7 +2,10 @@ package cli
 
 import (
 	"bytes"
+	"strings"
 	"testing"
+
+	"go.seanlatimer.dev/ignr/internal/config"
 )
 
 func TestNewRootCommand(t *testing.T) {
@@ -91,6 +94,7 @@ func TestRootCommandInvalidCommand(t *testing.T) {
 }
 
 func TestRootCommandFlags(t *testing.T) {
+	t.Cleanup(func() { config.SetConfigPath("") })
 	opts := &Options{}
 	cmd := NewRootCommand(opts)
 	
@@ -120,3 +124,61 @@ func TestExecute(t *testing.T) {
 	// This will likely fail due to missing cache, but should not panic
 	_ = Execute()
 }
+
+func TestQuietAndVerbose(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantStdout string
+		wantStderr []string
+		wantErr    bool
+	}{
+		{name: "generate", args: []string{"generate", "--no-interactive", "Go"}, wantStdout: "Generated"},
+		{name: "generate --quiet", args: []string{"--quiet", "generate", "--no-interactive", "Go"}},
+		{
+			name:       "generate --verbose",
+			args:       []string{"--verbose", "generate", "--no-interactive", "Go"},
+			wantStdout: "Generated",
+			wantStderr: []string{"debug: template cache: ", "debug: discovered 3 cached templates and 0 user templates", "debug: output: "},
+		},
+		{name: "preset create --quiet", args: []string{"--quiet", "preset", "create", "quiet-web", "Node"}},
+		{name: "--quiet with --verbose", args: []string{"--quiet", "--verbose", "generate", "Go"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := NewRootCommand(&Options{})
+			root.SetArgs(append([]string{"-C", t.TempDir()}, tt.args...))
+			var stdout, stderr bytes.Buffer
+			root.SetOut(&stdout)
+			root.SetErr(&stderr)
+			err := root.Execute()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("%v expected error, got nil", tt.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%v error = %v", tt.args, err)
+			}
+			if tt.wantStdout == "" && stdout.Len() > 0 {
+				t.Errorf("stdout = %q, want nothing", stdout.String())
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.wantStdout)
+			}
+			for _, want := range tt.wantStderr {
+				if !strings.Contains(stderr.String(), want) {
+					t.Errorf("stderr = %q, want %q", stderr.String(), want)
+				}
+			}
+			if len(tt.wantStderr) == 0 && strings.Contains(stderr.String(), "debug:") {
+				t.Errorf("stderr = %q, want no debug lines without --verbose", stderr.String())
+			}
+		})
+	}
+}
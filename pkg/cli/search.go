@@ -4,52 +4,88 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/sahilm/fuzzy"
 	"github.com/spf13/cobra"
-	"go.seanlatimer.dev/ignr/internal/cache"
+	"go.seanlatimer.dev/ignr/internal/config"
 	"go.seanlatimer.dev/ignr/internal/templates"
+	"go.seanlatimer.dev/ignr/internal/tui"
 )
 
 func newSearchCommand(opts *Options) *cobra.Command {
+	var showHidden bool
+	var offline bool
+	var jsonOutput bool
+	var compact bool
+	var limit int
 	cmd := &cobra.Command{
 		Use:   "search <pattern>",
 		Short: "Search templates by name",
 		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cachePath, err := cache.InitializeCache()
+			if limit < 0 {
+				return fmt.Errorf("--limit must not be negative")
+			}
+			cachePath, err := readCache(cmd, offline)
 			if err != nil {
 				return err
 			}
+			debugf(cmd, opts, "template cache: %s", cachePath)
 
 			items, err := templates.DiscoverTemplates(cachePath)
 			if err != nil {
 				return err
 			}
+			// Like generate, custom templates are searched too; the cache is still only read
+			// through readCache so a script never triggers a clone.
+			userPath, err := config.GetUserTemplatePath()
+			if err != nil {
+				return err
+			}
+			userItems, err := templates.DiscoverUserTemplates(userPath)
+			if err != nil {
+				return err
+			}
+			logDiscovered(cmd, opts, items, userItems, userPath)
+			items = append(items, userItems...)
 
-			pattern := strings.Join(args, " ")
-			names := make([]string, 0, len(items))
-			for _, item := range items {
-				names = append(names, item.Name)
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return err
+			}
+
+			if !showHidden {
+				items = templates.WithoutCategories(items, cfg.HiddenCategories)
 			}
 
-			matches := fuzzy.FindFrom(pattern, stringSource(names))
+			pattern := strings.Join(args, " ")
+			matches := tui.SearchTemplates(pattern, items, cfg.SearchMode)
+			if limit > 0 && len(matches) > limit {
+				matches = matches[:limit]
+			}
+			if jsonOutput {
+				out := make([]searchResultJSON, 0, len(matches))
+				for _, match := range matches {
+					out = append(out, searchResultJSON{templateJSON: newTemplateJSON(match.Template), Score: match.Score})
+				}
+				return writeJSON(cmd.OutOrStdout(), out, compact)
+			}
 			for _, match := range matches {
-				item := items[match.Index]
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[%s] %s\n", item.Category, item.Name)
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[%s] %s (%s)\n", match.Template.Category, match.Template.Name, match.Template.Source)
 			}
 			return nil
 		},
 	}
 
+	cmd.Flags().BoolVar(&showHidden, "show-hidden", false, "Include categories hidden by hidden_categories in config")
+	cmd.Flags().BoolVar(&offline, "offline", false, "Never touch the network; fail if the cache is missing")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print matches as a JSON array of templates with their match score")
+	cmd.Flags().BoolVar(&compact, "compact", false, "With --json, print the JSON on one line")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Show at most this many matches, best first (0 means no limit)")
 	return cmd
 }
 
-type stringSource []string
-
-func (s stringSource) Len() int {
-	return len(s)
-}
-
-func (s stringSource) String(i int) string {
-	return s[i]
+// searchResultJSON is the --json form of a search match. Score is the fuzzy match score,
+// higher for closer matches; it is 0 in substring and prefix search modes.
+type searchResultJSON struct {
+	templateJSON
+	Score int `json:"score"`
 }
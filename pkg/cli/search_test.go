@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -211,3 +212,73 @@ func TestSearchCommandRequiresPattern(t *testing.T) {
 		t.Error("search command expected error for missing pattern, got nil")
 	}
 }
+
+func TestSearchCommandSourceJSONAndLimit(t *testing.T) {
+	cleanup := setupSearchTest(t)
+	defer cleanup()
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := newSearchCommand(&Options{})
+		cmd.SetArgs(args)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("search %v error = %v", args, err)
+		}
+		return buf.String()
+	}
+
+	if out := run("python"); !strings.Contains(out, "[root] Python (cache)\n") {
+		t.Errorf("search output = %q, want the template source", out)
+	}
+
+	var all []searchResultJSON
+	if err := json.Unmarshal([]byte(run("--json", "node")), &all); err != nil {
+		t.Fatalf("search --json is not JSON: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("search --json node = %+v, want Node and Nodejs", all)
+	}
+	for _, match := range all {
+		if match.Source != "cache" || match.Path == "" || match.Score == 0 {
+			t.Errorf("match = %+v, want source, path, and a fuzzy score", match)
+		}
+	}
+
+	var limited []searchResultJSON
+	if err := json.Unmarshal([]byte(run("--json", "--limit", "1", "node")), &limited); err != nil {
+		t.Fatalf("search --json --limit is not JSON: %v", err)
+	}
+	if len(limited) != 1 || limited[0].Name != all[0].Name {
+		t.Errorf("search --limit 1 = %+v, want only the best match %s", limited, all[0].Name)
+	}
+}
+
+func TestSearchCommandFindsUserTemplates(t *testing.T) {
+	cleanup := setupSearchTest(t)
+	defer cleanup()
+
+	userPath := filepath.Join(xdg.ConfigHome, "ignr", "templates")
+	if err := os.MkdirAll(userPath, 0o755); err != nil {
+		t.Fatalf("failed to create user template dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(userPath, "Custom.gitignore"), []byte("*.custom\n"), 0o644); err != nil {
+		t.Fatalf("failed to write user template: %v", err)
+	}
+
+	cmd := newSearchCommand(&Options{})
+	cmd.SetArgs([]string{"--json", "custom"})
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("search error = %v", err)
+	}
+	var matches []searchResultJSON
+	if err := json.Unmarshal(buf.Bytes(), &matches); err != nil {
+		t.Fatalf("search --json is not JSON: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Name != "Custom" || matches[0].Source != "user" {
+		t.Errorf("search custom = %+v, want the user template Custom", matches)
+	}
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.seanlatimer.dev/ignr/internal/cache"
+	"go.seanlatimer.dev/ignr/internal/presets"
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+// suggestionJSON is the --json form of the templates suggested for one directory.
+type suggestionJSON struct {
+	Dir       string   `json:"dir"`
+	Templates []string `json:"templates"`
+}
+
+func newSuggestCommand(opts *Options) *cobra.Command {
+	var recursive bool
+	var depth int
+	var jsonOutput bool
+	var compact bool
+
+	cmd := &cobra.Command{
+		Use:   "suggest [path]",
+		Short: "Print the templates suggested by the files in a directory (default: the current one)",
+		Long: "Print the templates suggested by the files in a directory, one per line, without generating anything. " +
+			"Nothing is printed to stdout when there are no suggestions.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := opts.BaseDir()
+			if len(args) > 0 {
+				dir = joinBaseDir(dir, args[0])
+			}
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				return fmt.Errorf("not a directory: %s", dir)
+			}
+
+			resolvable, err := suggestionFilter()
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if !recursive {
+				detected, err := presets.DetectFiles(dir)
+				if err != nil {
+					return err
+				}
+				suggested, err := presets.SuggestTemplates(detected)
+				if err != nil {
+					return err
+				}
+				suggested = resolvable(suggested)
+				if jsonOutput {
+					return writeJSON(out, suggestionJSON{Dir: dir, Templates: suggested}, compact)
+				}
+				if len(suggested) == 0 {
+					noSuggestions(cmd, opts)
+					return nil
+				}
+				for _, name := range suggested {
+					_, _ = fmt.Fprintln(out, name)
+				}
+				return nil
+			}
+
+			found, err := presets.SuggestSubdirs(dir, depth)
+			if err != nil {
+				return err
+			}
+			groups := make([]presets.DirSuggestions, 0, len(found))
+			for _, group := range found {
+				if group.Templates = resolvable(group.Templates); len(group.Templates) > 0 {
+					groups = append(groups, group)
+				}
+			}
+			if jsonOutput {
+				list := make([]suggestionJSON, 0, len(groups))
+				for _, group := range groups {
+					list = append(list, suggestionJSON{Dir: group.Dir, Templates: group.Templates})
+				}
+				return writeJSON(out, list, compact)
+			}
+			if len(groups) == 0 {
+				noSuggestions(cmd, opts)
+				return nil
+			}
+			for _, group := range groups {
+				_, _ = fmt.Fprintf(out, "%s/: %s\n", group.Dir, strings.Join(group.Templates, ", "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&recursive, "recursive", false, "Suggest templates separately for each subdirectory (e.g. monorepo packages)")
+	cmd.Flags().IntVar(&depth, "depth", 1, "How many directory levels --recursive descends")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print {\"dir\", \"templates\"} as JSON (a list of them with --recursive)")
+	cmd.Flags().BoolVar(&compact, "compact", false, "With --json, print the JSON on one line")
+	return cmd
+}
+
+// noSuggestions notes on stderr that detection found nothing, keeping stdout empty for scripts.
+func noSuggestions(cmd *cobra.Command, opts *Options) {
+	if !opts.Quiet {
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "note: no templates suggested")
+	}
+}
+
+// suggestionFilter returns a function keeping the suggested names that resolve to a cached or
+// user template, so a detection rule naming a template nobody has is not printed. Without a
+// cache nothing can be checked, and names are kept as they are.
+func suggestionFilter() (func([]string) []string, error) {
+	initialized, err := cache.IsCacheInitialized()
+	if err != nil {
+		return nil, err
+	}
+	if !initialized {
+		return func(names []string) []string { return names }, nil
+	}
+	items, err := completionTemplates()
+	if err != nil {
+		return nil, err
+	}
+	index := templates.BuildIndex(items)
+	return func(names []string) []string {
+		return slices.DeleteFunc(slices.Clone(names), func(name string) bool {
+			_, ok := templates.FindTemplate(index, name)
+			return !ok
+		})
+	}, nil
+}
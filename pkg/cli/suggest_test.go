@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+func TestSuggestCommand(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{"api/go.mod", "web/package.json", "web/pyproject.toml", "docs/index.md"} {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{name: "whole tree", args: nil, want: "Node\nGo\nPython\n"},
+		{name: "path argument", args: []string{"web"}, want: "Node\nPython\n"},
+		{name: "json", args: []string{"--json", "--compact", "api"}, want: `{"dir":"` + filepath.Join(root, "api") + `","templates":["Go"]}` + "\n"},
+		{name: "recursive json", args: []string{"--recursive", "--json", "--compact"}, want: `[{"dir":"api","templates":["Go"]},{"dir":"web","templates":["Node","Python"]}]` + "\n"},
+		{name: "no suggestions", args: []string{"docs"}, want: "note: no templates suggested\n"},
+		{name: "missing path", args: []string{"nope"}, wantErr: true},
+		{name: "recursive", args: []string{"--recursive"}, want: "api/: Go\nweb/: Node, Python\n"},
+		{name: "invalid depth", args: []string{"--recursive", "--depth", "0"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newSuggestCommand(&Options{Chdir: root})
+			cmd.SetArgs(tt.args)
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetErr(&buf)
+
+			err := cmd.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("suggest %v error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+			if !tt.wantErr && buf.String() != tt.want {
+				t.Errorf("suggest %v output = %q, want %q", tt.args, buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestCommandSkipsUnknownTemplates(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	root := t.TempDir()
+	for _, file := range []string{"go.mod", "deno.json", "Dockerfile", "svc/deno.json"} {
+		path := filepath.Join(root, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatalf("failed to create file: %v", err)
+		}
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := newSuggestCommand(&Options{Chdir: root})
+		cmd.SetArgs(args)
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("suggest %v error = %v", args, err)
+		}
+		return buf.String()
+	}
+
+	if got := run(); got != "Go\n" {
+		t.Errorf("suggest output = %q, want only Go, which has a template", got)
+	}
+	if got := run("--recursive"); got != "note: no templates suggested\n" {
+		t.Errorf("suggest --recursive output = %q, want the Deno-only directory left out", got)
+	}
+
+	userDir := filepath.Join(xdg.ConfigHome, "ignr", "templates")
+	if err := os.MkdirAll(userDir, 0o755); err != nil {
+		t.Fatalf("failed to create user templates: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(userDir, "Deno.gitignore"), []byte(".deno/\n"), 0o644); err != nil {
+		t.Fatalf("failed to write user template: %v", err)
+	}
+	templates.ForgetDiscovered(userDir)
+	if got := run(); got != "Go\nDeno\n" {
+		t.Errorf("suggest output with a Deno user template = %q, want Go and Deno", got)
+	}
+}
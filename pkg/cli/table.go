@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+)
+
+const tableGap = "  "
+
+// terminalWidth reports whether w is a terminal and, if so, its width in columns.
+// A width of 0 means the size could not be determined.
+func terminalWidth(w io.Writer) (bool, int) {
+	f, ok := w.(*os.File)
+	if !ok || !term.IsTerminal(f.Fd()) {
+		return false, 0
+	}
+	width, _, err := term.GetSize(f.Fd())
+	if err != nil {
+		return true, 0
+	}
+	return true, width
+}
+
+// writeTable writes rows as left-aligned columns under headers. When maxWidth is
+// positive and the table would be wider, the widest column is truncated to fit.
+func writeTable(w io.Writer, headers []string, rows [][]string, maxWidth int) {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = ansi.StringWidth(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], ansi.StringWidth(cell))
+		}
+	}
+
+	if maxWidth > 0 {
+		total := len(tableGap) * (len(widths) - 1)
+		for _, width := range widths {
+			total += width
+		}
+		if overflow := total - maxWidth; overflow > 0 {
+			widest := 0
+			for i, width := range widths {
+				if width > widths[widest] {
+					widest = i
+				}
+			}
+			widths[widest] = max(widths[widest]-overflow, ansi.StringWidth(headers[widest]))
+		}
+	}
+
+	writeRow := func(cells []string) {
+		parts := make([]string, len(cells))
+		for i, cell := range cells {
+			cell = ansi.Truncate(cell, widths[i], "…")
+			if i == len(cells)-1 {
+				parts[i] = cell
+				continue
+			}
+			parts[i] = cell + strings.Repeat(" ", widths[i]-ansi.StringWidth(cell))
+		}
+		_, _ = fmt.Fprintln(w, strings.Join(parts, tableGap))
+	}
+
+	writeRow(headers)
+	for _, row := range rows {
+		writeRow(row)
+	}
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  []string
+		rows     [][]string
+		maxWidth int
+		want     string
+	}{
+		{
+			name:    "aligns to longest cell",
+			headers: []string{"Name", "Category", "Source"},
+			rows: [][]string{
+				{"Go", "root", "cache"},
+				{"VisualStudioCode", "Global", "cache"},
+				{"Vue", "community/JavaScript", "cache"},
+			},
+			want: "Name              Category              Source\n" +
+				"Go                root                  cache\n" +
+				"VisualStudioCode  Global                cache\n" +
+				"Vue               community/JavaScript  cache\n",
+		},
+		{
+			name:    "header wider than cells",
+			headers: []string{"Name", "Key", "Templates"},
+			rows: [][]string{
+				{"W", "w", "2"},
+			},
+			want: "Name  Key  Templates\n" +
+				"W     w    2\n",
+		},
+		{
+			name:    "truncates widest column to fit width",
+			headers: []string{"Name", "Key"},
+			rows: [][]string{
+				{"A Very Long Preset Name", "a"},
+			},
+			maxWidth: 15,
+			want: "Name        Key\n" +
+				"A Very Lo…  a\n",
+		},
+		{
+			name:    "no limit when width unknown",
+			headers: []string{"Name", "Key"},
+			rows: [][]string{
+				{"A Very Long Preset Name", "a"},
+			},
+			want: "Name                     Key\n" +
+				"A Very Long Preset Name  a\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeTable(&buf, tt.headers, tt.rows, tt.maxWidth)
+			if buf.String() != tt.want {
+				t.Errorf("writeTable() =\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestListCommandTableFallsBackWhenNotTTY(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	cmd := newListCommand(&Options{})
+	cmd.SetArgs([]string{"--table"})
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("list --table error = %v", err)
+	}
+	if strings.Contains(buf.String(), "Category") || !strings.Contains(buf.String(), "[root] Go") {
+		t.Errorf("list --table output = %q, want plain output when not a terminal", buf.String())
+	}
+}
@@ -0,0 +1,318 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.seanlatimer.dev/ignr/internal/cache"
+	"go.seanlatimer.dev/ignr/internal/config"
+	"go.seanlatimer.dev/ignr/internal/ignorefile"
+	"go.seanlatimer.dev/ignr/internal/presets"
+	"go.seanlatimer.dev/ignr/internal/templates"
+)
+
+func newTemplateCommand(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Inspect templates and manage user templates",
+	}
+
+	cmd.AddCommand(
+		newTemplateListCommand(opts),
+		newTemplateAddCommand(opts),
+		newTemplateRemoveCommand(opts),
+		newTemplateUsageCommand(opts),
+		newTemplateDiffCommand(opts),
+	)
+	return cmd
+}
+
+func newTemplateListCommand(opts *Options) *cobra.Command {
+	var userOnly bool
+	var jsonOutput bool
+	var compact bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List cached and user templates with their source",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var items []templates.Template
+			if !userOnly {
+				cached, err := cachedTemplates()
+				if err != nil {
+					return err
+				}
+				items = cached
+			}
+			userPath, err := config.GetUserTemplatePath()
+			if err != nil {
+				return err
+			}
+			userItems, err := templates.DiscoverUserTemplates(userPath)
+			if err != nil {
+				return err
+			}
+			logDiscovered(cmd, opts, items, userItems, userPath)
+			items = append(items, userItems...)
+
+			if jsonOutput {
+				return writeTemplatesJSON(cmd.OutOrStdout(), items, nil, compact)
+			}
+			if len(items) == 0 {
+				infof(cmd, opts, "No user templates in %s", userPath)
+				return nil
+			}
+			for _, item := range items {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[%s] %s (%s)\n", item.QualifiedCategory(), item.Name, item.Source)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&userOnly, "user", false, "List only user templates")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the templates as a JSON array")
+	cmd.Flags().BoolVar(&compact, "compact", false, "With --json, print the JSON on one line")
+	return cmd
+}
+
+func newTemplateAddCommand(opts *Options) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "add <name> [file]",
+		Short: "Add a user template from a file or stdin",
+		Long:  "Add a user template named <name>, copied from [file] or read from stdin when the file is omitted or -.",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := templates.UserTemplateName(args[0])
+			if err != nil {
+				return err
+			}
+			userPath, err := config.GetUserTemplatePath()
+			if err != nil {
+				return err
+			}
+			userItems, err := templates.DiscoverUserTemplates(userPath)
+			if err != nil {
+				return err
+			}
+			path := filepath.Join(userPath, name+".gitignore")
+			if existing, ok := templates.BuildIndex(userItems).ByName[strings.ToLower(name)]; ok {
+				if !force {
+					return fmt.Errorf("user template %s already exists at %s; pass --force to replace it", existing.Name, existing.Path)
+				}
+				path = existing.Path
+			}
+
+			source := "-"
+			if len(args) == 2 {
+				source = args[1]
+			}
+			var data []byte
+			if source == "-" {
+				data, err = io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("read stdin: %w", err)
+				}
+			} else {
+				file := joinBaseDir(opts.BaseDir(), source)
+				if data, err = os.ReadFile(file); err != nil {
+					return fmt.Errorf("read %s: %w", file, err)
+				}
+			}
+			if strings.TrimSpace(string(data)) == "" {
+				return fmt.Errorf("template %s has no content", name)
+			}
+
+			cached, err := cachedTemplates()
+			if err != nil {
+				return err
+			}
+			if upstream, ok := templates.BuildIndex(cached).ByName[strings.ToLower(name)]; ok {
+				if err := warn(cmd, opts, "user template %s overrides the cached template %s", name, upstream.Name); err != nil {
+					return err
+				}
+			}
+
+			if err := ignorefile.WriteFileAtomic(path, data); err != nil {
+				return config.WrapWriteError(userPath, err)
+			}
+			templates.ForgetDiscovered(userPath)
+			infof(cmd, opts, "Added user template %s at %s", name, path)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Replace a user template with the same name")
+	return cmd
+}
+
+func newTemplateRemoveCommand(opts *Options) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Delete a user template",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := templates.UserTemplateName(args[0])
+			if err != nil {
+				return err
+			}
+			userPath, err := config.GetUserTemplatePath()
+			if err != nil {
+				return err
+			}
+			userItems, err := templates.DiscoverUserTemplates(userPath)
+			if err != nil {
+				return err
+			}
+			template, ok := templates.BuildIndex(userItems).ByName[strings.ToLower(name)]
+			if !ok {
+				return fmt.Errorf("user template not found: %s", name)
+			}
+
+			using, err := presets.PresetsUsingTemplate(template.Name)
+			if err != nil {
+				return err
+			}
+			if len(using) > 0 {
+				if err := warn(cmd, opts, "template %s is used by %d preset(s); see `ignr template usage %s`", template.Name, len(using), template.Name); err != nil {
+					return err
+				}
+			}
+
+			if !force {
+				confirm, err := confirmPrompt(cmd, fmt.Sprintf("Delete user template %s at %s?", template.Name, template.Path))
+				if err != nil {
+					return err
+				}
+				if !confirm {
+					infof(cmd, opts, "Cancelled.")
+					return nil
+				}
+			}
+
+			if err := os.Remove(template.Path); err != nil {
+				return fmt.Errorf("remove %s: %w", template.Path, err)
+			}
+			templates.ForgetDiscovered(userPath)
+			infof(cmd, opts, "Removed user template %s", template.Name)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Skip the confirmation prompt")
+	return cmd
+}
+
+func newTemplateUsageCommand(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "usage <name>",
+		Short: "List presets that reference a template",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			using, err := presets.PresetsUsingTemplate(name)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(using) == 0 {
+				_, _ = fmt.Fprintf(out, "Template %s is not used by any preset\n", name)
+				return nil
+			}
+			_, _ = fmt.Fprintf(out, "Template %s is used by %d preset(s):\n", name, len(using))
+			for _, preset := range using {
+				_, _ = fmt.Fprintf(out, "  %s [%s]\n", preset.Name, preset.Key)
+			}
+			return nil
+		},
+	}
+}
+
+func newTemplateDiffCommand(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <name>",
+		Short: "Show how a user template differs from the upstream template of the same name",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			cachePath, err := cache.InitializeCache(cmd.Context())
+			if err != nil {
+				return err
+			}
+			debugf(cmd, opts, "template cache: %s", cachePath)
+			cacheItems, err := templates.DiscoverTemplates(cachePath)
+			if err != nil {
+				return err
+			}
+			userPath, err := config.GetUserTemplatePath()
+			if err != nil {
+				return err
+			}
+			userItems, err := templates.DiscoverUserTemplates(userPath)
+			if err != nil {
+				return err
+			}
+
+			upstream, inCache := templates.FindTemplate(templates.BuildIndex(cacheItems), name)
+			custom, inUser := templates.FindTemplate(templates.BuildIndex(userItems), name)
+			switch {
+			case !inCache && !inUser:
+				return fmt.Errorf("template not found: %s", name)
+			case !inUser:
+				return fmt.Errorf("template %s has no user override in %s; nothing to compare", upstream.Name, userPath)
+			case !inCache:
+				return fmt.Errorf("template %s exists only in your user templates; there is no upstream version", custom.Name)
+			}
+
+			from, err := templates.LoadTemplate(upstream.Path)
+			if err != nil {
+				return err
+			}
+			to, err := templates.LoadTemplate(custom.Path)
+			if err != nil {
+				return err
+			}
+
+			diff := templates.UnifiedDiff(diffLabel("upstream", cachePath, upstream.Path), diffLabel("user", userPath, custom.Path), from, to)
+			if diff == "" {
+				infof(cmd, opts, "Template %s matches upstream", upstream.Name)
+				return nil
+			}
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), diff)
+			return nil
+		},
+	}
+}
+
+// diffLabel names a template file relative to its source root, e.g. "upstream/Global/macOS.gitignore".
+func diffLabel(source, root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	return source + "/" + filepath.ToSlash(rel)
+}
+
+// cachedTemplates returns the templates in the cache, or none when it has not been cloned.
+// It never clones or updates the cache.
+func cachedTemplates() ([]templates.Template, error) {
+	initialized, err := cache.IsCacheInitialized()
+	if err != nil || !initialized {
+		return nil, err
+	}
+	cachePath, err := cache.GetCachePath(cache.DefaultSource)
+	if err != nil {
+		return nil, err
+	}
+	return templates.DiscoverTemplates(cachePath)
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"go.seanlatimer.dev/ignr/internal/presets"
+)
+
+func TestTemplateUsageCommand(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	store := presets.PresetStore{Presets: []presets.Preset{
+		{Key: "web", Name: "Web", Templates: []string{"Node", "go"}},
+		{Key: "backend", Name: "Backend", Templates: []string{"Go.gitignore", "Python"}},
+		{Key: "scripts", Name: "Scripts", Templates: []string{"Python"}},
+	}}
+	if err := presets.SavePresets(store); err != nil {
+		t.Fatalf("SavePresets() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "referenced case-insensitively",
+			template: "GO",
+			want:     "Template GO is used by 2 preset(s):\n  Web [web]\n  Backend [backend]\n",
+		},
+		{
+			name:     "single reference",
+			template: "node",
+			want:     "Template node is used by 1 preset(s):\n  Web [web]\n",
+		},
+		{
+			name:     "not referenced",
+			template: "Rust",
+			want:     "Template Rust is not used by any preset\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newTemplateUsageCommand(&Options{})
+			cmd.SetArgs([]string{tt.template})
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("template usage error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("template usage %s = %q, want %q", tt.template, buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateDiffCommand(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	userDir := filepath.Join(xdg.ConfigHome, "ignr", "templates")
+	if err := os.MkdirAll(userDir, 0o755); err != nil {
+		t.Fatalf("failed to create user templates dir: %v", err)
+	}
+	userTemplates := map[string]string{
+		// Cache Go is "# Go\n*.exe\nvendor/".
+		"Go.gitignore":     "# Go\n*.exe\n*.test\n",
+		"Python.gitignore": "# Python\n*.pyc\n__pycache__/",
+		"Mine.gitignore":   "local/\n",
+	}
+	for name, content := range userTemplates {
+		if err := os.WriteFile(filepath.Join(userDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("failed to write user template: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "override differs",
+			template: "go",
+			want: "--- upstream/Go.gitignore\n+++ user/Go.gitignore\n" +
+				"@@ -1,3 +1,3 @@\n # Go\n *.exe\n-vendor/\n+*.test\n",
+		},
+		{name: "override matches", template: "Python", want: "Template Python matches upstream\n"},
+		{name: "cache only", template: "Node", wantErr: "no user override"},
+		{name: "user only", template: "Mine", wantErr: "only in your user templates"},
+		{name: "unknown", template: "Nope", wantErr: "template not found: Nope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newTemplateDiffCommand(&Options{})
+			cmd.SetArgs([]string{tt.template})
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetErr(&buf)
+
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("template diff %s error = %v, want %q", tt.template, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("template diff %s error = %v", tt.template, err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("template diff %s output = %q, want %q", tt.template, buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateAddListRemove(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	run := func(stdin string, args ...string) (string, error) {
+		t.Helper()
+		root := NewRootCommand(&Options{})
+		root.SetArgs(append([]string{"template"}, args...))
+		root.SetIn(strings.NewReader(stdin))
+		var buf bytes.Buffer
+		root.SetOut(&buf)
+		root.SetErr(&buf)
+		err := root.Execute()
+		return buf.String(), err
+	}
+
+	source := filepath.Join(t.TempDir(), "mine.txt")
+	if err := os.WriteFile(source, []byte("local/\n"), 0o644); err != nil {
+		t.Fatalf("failed to write source file: %v", err)
+	}
+	if _, err := run("", "add", "Mine", source); err != nil {
+		t.Fatalf("template add from file error = %v", err)
+	}
+	userDir := filepath.Join(xdg.ConfigHome, "ignr", "templates")
+	if data, err := os.ReadFile(filepath.Join(userDir, "Mine.gitignore")); err != nil || string(data) != "local/\n" {
+		t.Errorf("Mine.gitignore = %q, %v; want the source content", data, err)
+	}
+
+	out, err := run("# Go\n*.test\n", "add", "go.gitignore")
+	if err != nil {
+		t.Fatalf("template add from stdin error = %v", err)
+	}
+	if !strings.Contains(out, "warning: user template go overrides the cached template Go") {
+		t.Errorf("template add go output = %q, want a collision warning", out)
+	}
+
+	if _, err := run("x\n", "add", "mine"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("template add over an existing template error = %v, want already exists", err)
+	}
+	if _, err := run("other/\n", "add", "--force", "mine"); err != nil {
+		t.Errorf("template add --force error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(userDir, "Mine.gitignore")); string(data) != "other/\n" {
+		t.Errorf("Mine.gitignore after --force = %q, want it replaced", data)
+	}
+	for _, args := range [][]string{{"add", "../escape"}, {"add", "empty"}} {
+		if _, err := run("", args...); err == nil {
+			t.Errorf("template %v error = nil, want an error", args)
+		}
+	}
+
+	out, err = run("", "list", "--user")
+	if err != nil {
+		t.Fatalf("template list --user error = %v", err)
+	}
+	if out != "[user] Mine (user)\n[user] go (user)\n" {
+		t.Errorf("template list --user = %q, want the two user templates", out)
+	}
+	if out, _ := run("", "list"); !strings.Contains(out, "[root] Go (cache)\n") || !strings.Contains(out, "[user] Mine (user)\n") {
+		t.Errorf("template list = %q, want cached and user templates", out)
+	}
+
+	if out, err := run("n\n", "remove", "Mine"); err != nil || !strings.Contains(out, "Cancelled.") {
+		t.Errorf("template remove answered no = %q, %v; want cancelled", out, err)
+	}
+	if _, err := run("", "remove", "--force", "Mine"); err != nil {
+		t.Fatalf("template remove error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(userDir, "Mine.gitignore")); !os.IsNotExist(err) {
+		t.Errorf("Mine.gitignore still exists after remove: %v", err)
+	}
+	if _, err := run("", "remove", "--force", "Mine"); err == nil || !strings.Contains(err.Error(), "user template not found") {
+		t.Errorf("template remove of a missing template error = %v, want not found", err)
+	}
+}
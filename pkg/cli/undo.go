@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.seanlatimer.dev/ignr/internal/history"
+)
+
+func newUndoCommand(opts *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "undo [path]",
+		Short: "Restore the version of a file before ignr last overwrote it",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output := ""
+			if len(args) > 0 {
+				output = args[0]
+			}
+			target, err := resolveOutputPath(opts.BaseDir(), output)
+			if err != nil {
+				return err
+			}
+
+			if err := history.Restore(target); err != nil {
+				return err
+			}
+			if !opts.Quiet {
+				remaining, err := history.Count(target)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Restored %s (%d older versions left)\n", target, remaining)
+			}
+			return nil
+		},
+	}
+}
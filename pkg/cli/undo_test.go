@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.seanlatimer.dev/ignr/internal/presets"
+)
+
+func TestUndoRestoresContentBeforePresetUse(t *testing.T) {
+	cleanup := setupGenerateTest(t)
+	defer cleanup()
+
+	if err := presets.CreatePreset("web", []string{"Go", "Node"}, nil); err != nil {
+		t.Fatalf("CreatePreset() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	target := filepath.Join(dir, ".gitignore")
+	original := "# my rules\n/secrets\n"
+	if err := os.WriteFile(target, []byte(original), 0o644); err != nil {
+		t.Fatalf("failed to write .gitignore: %v", err)
+	}
+
+	root := NewRootCommand(&Options{})
+	root.SetArgs([]string{"-C", dir, "preset", "use", "--force", "web"})
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("preset use error = %v", err)
+	}
+	if data, _ := os.ReadFile(target); string(data) == original {
+		t.Fatal("preset use did not overwrite .gitignore")
+	}
+
+	root = NewRootCommand(&Options{})
+	root.SetArgs([]string{"-C", dir, "undo"})
+	buf.Reset()
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("undo error = %v", err)
+	}
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("failed to read .gitignore: %v", err)
+	}
+	if string(data) != original {
+		t.Errorf("undo restored %q, want %q", string(data), original)
+	}
+	if !strings.Contains(buf.String(), "Restored "+target) {
+		t.Errorf("undo output = %q, want restore message", buf.String())
+	}
+
+	root = NewRootCommand(&Options{})
+	root.SetArgs([]string{"undo", target})
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "no stashed versions") {
+		t.Errorf("second undo error = %v, want no stashed versions", err)
+	}
+}
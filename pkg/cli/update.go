@@ -2,31 +2,56 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"go.seanlatimer.dev/ignr/internal/cache"
+	"go.seanlatimer.dev/ignr/internal/config"
 )
 
 func newUpdateCommand(opts *Options) *cobra.Command {
+	var ref string
+
 	cmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update the cached gitignore templates",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cachePath, err := cache.UpdateCache()
+			if cmd.Flags().Changed("ref") {
+				if err := saveTemplateRepoRef(ref); err != nil {
+					return err
+				}
+			}
+
+			cachePath, err := cache.UpdateCache(cmd.Context())
 			if err != nil {
 				return err
 			}
+			if pinned, err := cache.IsDetachedHead(cachePath); err == nil && pinned {
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "note: template cache is pinned to a tag or commit; skipped pull")
+			}
 			status, err := cache.GetStatus()
 			if err != nil {
 				return err
 			}
-		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Updated cache at %s\n", cachePath)
+		infof(cmd, opts, "Updated cache at %s", cachePath)
 		if status.HeadCommit != "" {
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "HEAD %s\n", status.HeadCommit)
+			infof(cmd, opts, "HEAD %s", status.HeadCommit)
 		}
 			return nil
 		},
 	}
 
+	cmd.Flags().StringVar(&ref, "ref", "", "Pin the template cache to a branch, tag, or commit (empty follows the default branch)")
+
 	return cmd
 }
+
+// saveTemplateRepoRef stores ref as the pinned template ref; the cache switches on the next update.
+func saveTemplateRepoRef(ref string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	cfg.TemplateRepoRef = strings.TrimSpace(ref)
+	return config.SaveConfig(cfg)
+}
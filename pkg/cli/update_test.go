@@ -8,6 +8,9 @@ import (
 	"testing"
 
 	"github.com/adrg/xdg"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"go.seanlatimer.dev/ignr/internal/cache"
 )
 
 func setupUpdateTest(t *testing.T) func() {
@@ -134,3 +137,49 @@ func TestUpdateCommandSuccess(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdateCommandPinnedSkipsPull(t *testing.T) {
+	cleanup := setupUpdateTest(t)
+	defer cleanup()
+
+	cachePath, err := cache.GetCachePath(cache.DefaultSource)
+	if err != nil {
+		t.Fatalf("GetCachePath() error = %v", err)
+	}
+	repo, err := git.PlainInit(cachePath, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cachePath, "Go.gitignore"), []byte("# Go"), 0o644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	if _, err := wt.Add("Go.gitignore"); err != nil {
+		t.Fatalf("failed to add file: %v", err)
+	}
+	sig := &object.Signature{Name: "Test User", Email: "test@example.com"}
+	hash, err := wt.Commit("add Go", &git.CommitOptions{Author: sig, Committer: sig})
+	if err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{Hash: hash}); err != nil {
+		t.Fatalf("failed to detach HEAD: %v", err)
+	}
+
+	cmd := newUpdateCommand(&Options{})
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("update on a pinned cache error = %v, want pull skipped", err)
+	}
+	if !strings.Contains(stderr.String(), "skipped pull") {
+		t.Errorf("stderr = %q, want pinned notice", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), hash.String()) {
+		t.Errorf("stdout = %q, want HEAD %s", stdout.String(), hash)
+	}
+}